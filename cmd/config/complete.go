@@ -0,0 +1,87 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	cfg "github.com/cisco-open/fsoc/config"
+)
+
+// settingValues are the values suggested for core settings that have a fixed set of values
+var settingValues = map[string][]string{
+	"oauth-flow":           {cfg.OAuthFlowBrowser, cfg.OAuthFlowDevice},
+	"credentials":          {cfg.CredentialsSourceFile, cfg.CredentialsSourceEnv, cfg.CredentialsSourceStdin, cfg.CredentialsSourceExec + ":"},
+	"envtype":              {"dev", "prod"},
+	"insecure-skip-verify": {"true", "false"},
+	"keep-alive":           {"true", "false"},
+}
+
+// settingArgsAutocomplete completes SETTING=VALUE arguments of the "config set" and "config create"
+// commands: the setting names, both core and subsystem-specific, and the values of settings
+// that have a fixed or suggested set of values
+func settingArgsAutocomplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	name, valuePrefix, hasValue := strings.Cut(toComplete, "=")
+
+	// complete the setting name
+	if !hasValue {
+		completions := []string{}
+		for _, setting := range configArgs {
+			if setting != "server" && strings.HasPrefix(setting, toComplete) { // skip deprecated settings
+				completions = append(completions, setting+"=")
+			}
+		}
+		for _, subsystemName := range cfg.GetRegisteredSubsystems() {
+			settings, _ := cfg.GetSubsystemSettings(subsystemName)
+			for _, setting := range settings {
+				if fullName := subsystemName + "." + setting.Name; strings.HasPrefix(fullName, toComplete) {
+					completions = append(completions, fullName+"=")
+				}
+			}
+		}
+		return completions, cobra.ShellCompDirectiveNoSpace
+	}
+
+	// complete the value
+	var values []string
+	switch {
+	case name == "auth":
+		values = GetAuthMethodsStringList()
+	case name == "extends":
+		values = cfg.ListAllContexts()
+	case strings.Contains(name, "."):
+		subsystemName, settingName, _ := strings.Cut(name, ".")
+		settings, _ := cfg.GetSubsystemSettings(subsystemName)
+		for _, setting := range settings {
+			if setting.Name == settingName {
+				values = setting.Values
+			}
+		}
+	default:
+		values = settingValues[name]
+	}
+	if len(values) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	completions := []string{}
+	for _, value := range values {
+		if strings.HasPrefix(value, valuePrefix) {
+			completions = append(completions, name+"="+value)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
@@ -47,6 +47,10 @@ func NewSubCmd() *cobra.Command {
 	cmd.AddCommand(newCmdConfigList())
 	cmd.AddCommand(newCmdConfigDelete())
 	cmd.AddCommand(newCmdConfigShowFields())
+	cmd.AddCommand(newCmdConfigMigrateSecrets())
+	cmd.AddCommand(newCmdConfigExport())
+	cmd.AddCommand(newCmdConfigImport())
+	cmd.AddCommand(newCmdConfigDoctor())
 
 	return cmd
 }
@@ -24,8 +24,10 @@ import (
 
 	"github.com/apex/log"
 	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
 	cfg "github.com/cisco-open/fsoc/config"
 	"github.com/cisco-open/fsoc/output"
 	"github.com/cisco-open/fsoc/platform/api"
@@ -66,6 +68,12 @@ make the new context the default one for the config file. See "fsoc help config"
   fsoc config create agent1 auth=agent-principal secret-file=collectors-values.yaml
   fsoc config create agent2 auth=agent-principal secret-file=client-values.json tenant=123456 url=https://mytenant.observe.appdynamics.com
 
+  # Create a profile that inherits the settings of the "prod" profile, overriding the URL
+  fsoc config create prod-eu extends=prod url=https://mytenant-eu.observe.appdynamics.com
+
+  # Create a copy of the "prod" profile with a different tenant
+  fsoc config create staging --from prod url=https://mytenant-staging.observe.appdynamics.com
+
   # Set local access
   fsoc config create dev auth=local url=http://localhost appd-pid=PID appd-tid=TID appd-pty=PTY
  
@@ -84,15 +92,25 @@ func newCmdConfigCreate() *cobra.Command {
 		Example:     createContextExample,
 		Annotations: map[string]string{cfg.AnnotationForConfigBypass: ""},
 		Args:        cobra.MinimumNArgs(1),
-		Run:         configCreateContext,
+		RunE:        configCreateContext,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp // new context name
+			}
+			return settingArgsAutocomplete(cmd, args, toComplete)
+		},
 	}
 
 	cmd.Flags().Bool("no-login", false, "Do not attempt to log in to the new context after creating it")
+	cmd.Flags().String("from", "", "Copy the settings of an existing profile, which the specified settings modify")
+	_ = cmd.RegisterFlagCompletionFunc("from", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return cfg.ListContexts(toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
 
 	return cmd
 }
 
-func configCreateContext(cmd *cobra.Command, args []string) {
+func configCreateContext(cmd *cobra.Command, args []string) error {
 	var contextName string
 
 	// -- Perform command-line parsing checks first (syntax)
@@ -101,7 +119,7 @@ func configCreateContext(cmd *cobra.Command, args []string) {
 	if !strings.Contains(args[0], "=") {
 		contextName = args[0]
 		if contextName == "" {
-			log.Fatal("Context name, if specified as a first positional argument, must be non-empty")
+			return fmt.Errorf("context name, if specified as a first positional argument, must be non-empty")
 		}
 		args = args[1:] // remove from the args list so that the rest can be processed as settings
 	} else {
@@ -111,39 +129,52 @@ func configCreateContext(cmd *cobra.Command, args []string) {
 		contextName = cfg.GetCurrentProfileName() // get the active profile name (if set) or default (if not set otherwise)
 	}
 	cfg.ForceSetActiveProfileName(contextName) // set to the name we chose according to the priority order above
+	if cfg.IsEnvContext(contextName) {
+		return clierror.New(clierror.Usage, "profile %q is defined by environment variables and cannot be modified; unset %v to manage the config file's profiles", contextName, cfg.FSOC_URL_ENVVAR)
+	}
 
 	// parse settings and separate the core fsoc settings from any subsystem-specific settings
 	// note that, unlike on `set`, this command does not support the legacy --flag-based settings (since it's a new command)
 	coreArgs, subsystemSettingArgs, err := parseCoreArgs(cmd, args)
 	if err != nil {
-		log.Fatalf("%v", err)
+		return err
 	}
 
 	// Check that at least one config value is specified (including empty); either core or subsytem-specific setting satisfies this check
-	if len(coreArgs) == 0 && len(subsystemSettingArgs) == 0 {
-		log.Fatal("At least one setting must be specified when creating a new context")
+	from, _ := cmd.Flags().GetString("from")
+	if len(coreArgs) == 0 && len(subsystemSettingArgs) == 0 && from == "" {
+		return fmt.Errorf("at least one setting must be specified when creating a new context")
 	}
 
 	// -- Perform other non-parsing validations (e.g., whether the context already exists)
 
 	// fail if the context name already exists
 	if _, err := cfg.GetContext(contextName); err == nil {
-		log.Fatalf("Context %q already exists; create with a different name or use 'fsoc config set' to update this one", contextName)
+		return fmt.Errorf("context %q already exists; create with a different name or use 'fsoc config set' to update this one", contextName)
 	}
 
 	// -- Fill in the new context
 
-	// Create a new context
+	// Create a new context, as a copy of the --from profile's effective settings if specified
 	ctxPtr := &cfg.Context{Name: contextName}
+	if from != "" {
+		fromCtx, err := cfg.GetContext(from)
+		if err != nil {
+			return fmt.Errorf("cannot copy profile: %w", err)
+		}
+		ctxPtr = cfg.CloneContext(fromCtx, contextName)
+	}
 
-	// Process core settings
+	// Process core settings (settings set to empty values override the inherited ones)
+	coreSettingNames := maps.Keys(coreArgs)
 	if err = updateCoreSettings(cmd, ctxPtr, coreArgs, false); err != nil {
-		log.Fatalf("Failed to set core settings: %v", err)
+		return fmt.Errorf("failed to set core settings: %w", err)
 	}
+	cfg.UpdateCleared(ctxPtr, coreSettingNames)
 
 	// process subsystem-specific settings
 	if err := processSubsystemSettings(ctxPtr, subsystemSettingArgs); err != nil {
-		log.Fatalf("Failed to set subsystem-specific settings: %v", err)
+		return fmt.Errorf("failed to set subsystem-specific settings: %w", err)
 	}
 
 	// -- Apply the new context
@@ -151,7 +182,7 @@ func configCreateContext(cmd *cobra.Command, args []string) {
 	// update config file
 	// TODO: avoid modifying the file if login fails
 	if err := cfg.UpsertContext(ctxPtr); err != nil {
-		log.Fatalf("%v", err)
+		return err
 	}
 
 	// init a generic output message (to be updated later with more specific status)
@@ -170,7 +201,7 @@ func configCreateContext(cmd *cobra.Command, args []string) {
 		message = fmt.Sprintf("Context %q created ok but not verified by logging in.", contextName)
 	}
 
-	output.PrintCmdOutput(cmd, message)
+	return output.PrintCmdOutput(cmd, message)
 }
 
 // parseCoreArgs separates the core fsoc settings from any subsystem-specific settings. It returns the two groups of settings and an error
@@ -211,17 +242,25 @@ func updateCoreSettings(cmd *cobra.Command, ctxPtr *cfg.Context, settings map[st
 
 	// process settings in the order they will be applied, allowing for non-patch to clear dependent fields before they are set (if set)
 
+	val, ok = settings["extends"] // first, so that the other settings apply over the inherited ones
+	if ok {
+		if err := cfg.SetExtends(ctxPtr, val); err != nil {
+			return err
+		}
+		delete(settings, "extends")
+	}
+
 	val, ok = settings["auth"]
 	if ok {
 		if !slices.Contains(GetAuthMethodsStringList(), val) {
-			log.Fatalf(`Invalid auth method %q; must be one of {"%v"}`, val, strings.Join(GetAuthMethodsStringList(), `", "`))
+			return fmt.Errorf(`invalid auth method %q; must be one of {"%v"}`, val, strings.Join(GetAuthMethodsStringList(), `", "`))
 		}
 		ctxPtr.AuthMethod = val
 		delete(settings, "auth")
 
 		// Clear All fields before setting other fields
 		if !patch {
-			clearFields([]string{"url", "server", "tenant", "user", "token", "refresh_token", "secret-file"}, ctxPtr)
+			clearFields([]string{"url", "server", "tenant", "user", "token", "refresh_token", "secret-file", "credentials"}, ctxPtr)
 		}
 	}
 
@@ -229,7 +268,7 @@ func updateCoreSettings(cmd *cobra.Command, ctxPtr *cfg.Context, settings map[st
 	if ok {
 		potentialEnvTypes := []string{"prod", "dev"}
 		if !slices.Contains(potentialEnvTypes, val) {
-			log.Fatalf("envtype can only take on one of the following values: %s", strings.Join(potentialEnvTypes, ", "))
+			return fmt.Errorf("envtype can only take on one of the following values: %s", strings.Join(potentialEnvTypes, ", "))
 		}
 		ctxPtr.EnvType = val
 		delete(settings, "envtype")
@@ -243,7 +282,7 @@ func updateCoreSettings(cmd *cobra.Command, ctxPtr *cfg.Context, settings map[st
 	if ok {
 		cleanedUrl, err := validateUrl(val)
 		if err != nil {
-			log.Fatal(err.Error())
+			return err
 		}
 		if settings["server"] != "" {
 			log.Warnf(`The "server" setting is now deprecated. In the future, please use the "url" setting instead. We will set the url to %q for you now`, cleanedUrl)
@@ -256,7 +295,7 @@ func updateCoreSettings(cmd *cobra.Command, ctxPtr *cfg.Context, settings map[st
 		if ctxPtr.EnvType == "" {
 			parsedUrl, err := url.Parse(cleanedUrl)
 			if err != nil {
-				log.Fatalf("Failed to parse url: %v", err)
+				return fmt.Errorf("failed to parse url: %w", err)
 			}
 			host := parsedUrl.Host
 			if !strings.HasSuffix(host, ".observe.appdynamics.com") {
@@ -275,7 +314,7 @@ func updateCoreSettings(cmd *cobra.Command, ctxPtr *cfg.Context, settings map[st
 		// reject if tenant is not an allowed setting for this authentication type
 		err := validateWriteReq(cmd, ctxPtr.AuthMethod, "tenant")
 		if err != nil {
-			log.Fatal(err.Error())
+			return err
 		}
 
 		// update tenant
@@ -293,7 +332,7 @@ func updateCoreSettings(cmd *cobra.Command, ctxPtr *cfg.Context, settings map[st
 		// reject if token is not an allowed setting for this authentication type
 		err := validateWriteReq(cmd, ctxPtr.AuthMethod, "token")
 		if err != nil {
-			log.Fatal(err.Error())
+			return err
 		}
 
 		// update token
@@ -304,6 +343,7 @@ func updateCoreSettings(cmd *cobra.Command, ctxPtr *cfg.Context, settings map[st
 		} else {
 			ctxPtr.Token = val
 		}
+		ctxPtr.TokenExpiry = 0 // determined from the new token when needed
 		delete(settings, "token")
 
 		// clear dependent fields if not patching
@@ -317,7 +357,7 @@ func updateCoreSettings(cmd *cobra.Command, ctxPtr *cfg.Context, settings map[st
 		// reject if secret-file is not an allowed setting for this authentication type
 		err := validateWriteReq(cmd, ctxPtr.AuthMethod, "secret-file")
 		if err != nil {
-			log.Fatal(err.Error())
+			return err
 		}
 
 		// canonicalize the path and update it into the context
@@ -335,6 +375,35 @@ func updateCoreSettings(cmd *cobra.Command, ctxPtr *cfg.Context, settings map[st
 		}
 	}
 
+	val, ok = settings["credentials"]
+	if ok {
+		// reject if credentials is not an allowed setting for this authentication type
+		err := validateWriteReq(cmd, ctxPtr.AuthMethod, "credentials")
+		if err != nil {
+			return err
+		}
+		if val != "" {
+			if _, _, err := cfg.ParseCredentialsSource(val); err != nil {
+				return err
+			}
+		}
+		ctxPtr.CredentialsSource = val
+		delete(settings, "credentials")
+
+		if !patch {
+			automatedFieldClearing(ctxPtr, "credentials")
+		}
+	}
+
+	val, ok = settings["oauth-flow"]
+	if ok {
+		if err := validateOAuthFlow(val); err != nil {
+			return err
+		}
+		ctxPtr.OAuthFlow = val
+		delete(settings, "oauth-flow")
+	}
+
 	// populate fields for local auth
 	if ctxPtr.AuthMethod == cfg.AuthMethodLocal {
 		val, ok = settings[cfg.AppdPid]
@@ -342,7 +411,7 @@ func updateCoreSettings(cmd *cobra.Command, ctxPtr *cfg.Context, settings map[st
 			// reject if appd-pid is not an allowed setting for this authentication type
 			err := validateWriteReq(cmd, ctxPtr.AuthMethod, cfg.AppdPid)
 			if err != nil {
-				log.Fatal(err.Error())
+				return err
 			}
 
 			// update value
@@ -357,7 +426,7 @@ func updateCoreSettings(cmd *cobra.Command, ctxPtr *cfg.Context, settings map[st
 			// reject if appd-pty is not an allowed setting for this authentication type
 			err := validateWriteReq(cmd, ctxPtr.AuthMethod, cfg.AppdPty)
 			if err != nil {
-				log.Fatal(err.Error())
+				return err
 			}
 
 			// update value
@@ -372,7 +441,7 @@ func updateCoreSettings(cmd *cobra.Command, ctxPtr *cfg.Context, settings map[st
 			// reject if appd-tid is not an allowed setting for this authentication type
 			err := validateWriteReq(cmd, ctxPtr.AuthMethod, cfg.AppdTid)
 			if err != nil {
-				log.Fatal(err.Error())
+				return err
 			}
 
 			// update value
@@ -384,6 +453,11 @@ func updateCoreSettings(cmd *cobra.Command, ctxPtr *cfg.Context, settings map[st
 		}
 	}
 
+	// network settings (not dependent on the auth method)
+	if err := updateNetworkSettings(ctxPtr, settings); err != nil {
+		return err
+	}
+
 	// upgrade config format from CsvFile to SecretFile, opportunistically using the update
 	if ctxPtr.SecretFile == "" && ctxPtr.CsvFile != "" {
 		ctxPtr.SecretFile = ctxPtr.CsvFile
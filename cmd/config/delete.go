@@ -17,7 +17,6 @@ package config
 import (
 	"fmt"
 
-	"github.com/apex/log"
 	"github.com/spf13/cobra"
 
 	cfg "github.com/cisco-open/fsoc/config"
@@ -32,16 +31,17 @@ func newCmdConfigDelete() *cobra.Command {
 		Long:              `Delete a context from the fsoc config file`,
 		Args:              cobra.ExactArgs(1),
 		ValidArgsFunction: validArgsAutocomplete,
-		Run:               configDeleteContext,
+		RunE:              configDeleteContext,
 	}
 
 	return cmd
 }
 
-func configDeleteContext(cmd *cobra.Command, args []string) {
+func configDeleteContext(cmd *cobra.Command, args []string) error {
 	profile := args[0]
 	if err := cfg.DeleteContext(profile); err != nil {
-		log.Fatalf("%v", err)
+		return err
 	}
 	output.PrintCmdStatus(cmd, fmt.Sprintf("Deleted profile %q\n", profile))
+	return nil
 }
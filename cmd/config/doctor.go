@@ -0,0 +1,74 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/output"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+func newCmdConfigDoctor() *cobra.Command {
+
+	var cmd = &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose the current context",
+		Long: `Diagnose problems with the current context (profile), checking it end to end:
+  - the settings required for the context's authentication method
+  - the URL, its DNS resolution and connecting to it (including TLS), using the context's network settings
+  - resolving the tenant ID from the URL, for "oauth" contexts without a tenant setting
+  - the presence and expiration of the access token
+  - an authenticated API call, logging in if needed (skipped if the login would be interactive)
+  - the subsystem-specific settings, including their validation
+
+Each check passes, fails, warns about a likely problem or is skipped if it doesn't apply. The command
+fails if any of the checks fails.`,
+		Example: `  fsoc config doctor
+  fsoc config doctor --profile prod -o json`,
+		Args: cobra.NoArgs,
+		RunE: configDoctor,
+		Annotations: map[string]string{
+			output.TableFieldsAnnotation: "check:.check, status:.status, message:.message",
+		},
+	}
+
+	return cmd
+}
+
+func configDoctor(cmd *cobra.Command, args []string) error {
+	results := api.Diagnose(cmd.Context())
+
+	failed := 0
+	for _, result := range results {
+		if result.Status == api.DiagnosticFail {
+			failed++
+		}
+	}
+
+	if err := output.PrintCmdOutput(cmd, struct {
+		Items []api.Diagnostic `json:"items"`
+		Total int              `json:"total"`
+	}{results, len(results)}); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"github.com/spf13/cobra"
+
+	cfg "github.com/cisco-open/fsoc/config"
+	"github.com/cisco-open/fsoc/output"
+)
+
+func newCmdConfigExport() *cobra.Command {
+
+	var cmd = &cobra.Command{
+		Use:   "export [CONTEXT_NAME...]",
+		Short: "Export contexts as a bundle for sharing",
+		Long: `Export contexts (profiles) from the fsoc config file as a YAML (or JSON, with -o json) bundle that
+can be shared, e.g., with new team members, and imported with "fsoc config import".
+
+The exported contexts don't include access or refresh tokens. Secrets that are part of a context's configuration,
+like the token of the "jwt" auth method and the path to the secret file of service and agent principals, are
+replaced with the "` + cfg.RedactedPlaceholder + `" placeholder and must be set after importing the context.
+Contexts that extend other contexts are exported with their effective settings, including the inherited ones.
+
+If no context is specified, all contexts are exported.`,
+		Example: `  fsoc config export > team-profiles.yaml
+  fsoc config export prod staging -o json > profiles.json`,
+		Args:              cobra.ArbitraryArgs,
+		ValidArgsFunction: validArgsAutocomplete,
+		RunE:              configExport,
+	}
+
+	return cmd
+}
+
+func configExport(cmd *cobra.Command, args []string) error {
+	profiles := args
+	if len(profiles) == 0 {
+		profiles = cfg.ListAllContexts()
+	}
+
+	bundle, err := cfg.ExportContexts(profiles)
+	if err != nil {
+		return err
+	}
+
+	return output.PrintCmdOutput(cmd, bundle)
+}
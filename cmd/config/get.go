@@ -15,15 +15,17 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"slices"
 	"strings"
+	"time"
 	"unicode"
 
-	"github.com/apex/log"
 	"github.com/spf13/cobra"
 	"golang.org/x/exp/maps"
 
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
 	cfg "github.com/cisco-open/fsoc/config"
 	"github.com/cisco-open/fsoc/output"
 )
@@ -35,7 +37,7 @@ func newCmdConfigGet() *cobra.Command {
 		Short: "Displays the selected context",
 		Long:  `Displays the selected context`,
 		Args:  cobra.NoArgs,
-		Run:   configGetContext,
+		RunE:  configGetContext,
 	}
 
 	cmd.Flags().Bool("unmask", false, "Unmask secrets in output")
@@ -43,17 +45,19 @@ func newCmdConfigGet() *cobra.Command {
 	return cmd
 }
 
-func configGetContext(cmd *cobra.Command, args []string) {
+func configGetContext(cmd *cobra.Command, args []string) error {
 	// get current context and mask secret values
-	ctx := cfg.GetCurrentContext()
-	if ctx == nil {
-		log.Fatalf("There is no current context, use `fsoc config set` to set up a context")
+	ctx, err := cfg.GetContext(cfg.GetCurrentProfileName())
+	if errors.Is(err, cfg.ErrProfileNotFound) {
+		return fmt.Errorf("there is no current context, use `fsoc config set` to set up a context")
+	} else if err != nil {
+		return clierror.Wrap(clierror.Validation, err)
 	}
 
-	outputContext(cmd, ctx, "")
+	return outputContext(cmd, ctx, "")
 }
 
-func outputContext(cmd *cobra.Command, context *cfg.Context, useIndicator string) {
+func outputContext(cmd *cobra.Command, context *cfg.Context, useIndicator string) error {
 	ctx := *context // shallow copy, to allow modifying top-level fields
 
 	// mask sensitive values (unless --unmask flag)
@@ -65,6 +69,9 @@ func outputContext(cmd *cobra.Command, context *cfg.Context, useIndicator string
 		if ctx.RefreshToken != "" {
 			ctx.RefreshToken = "(present)"
 		}
+		if ctx.ClientSecret != "" {
+			ctx.ClientSecret = "(present)"
+		}
 	}
 
 	// "upgrade" config schema if needed
@@ -82,15 +89,46 @@ func outputContext(cmd *cobra.Command, context *cfg.Context, useIndicator string
 			values = append(values, value)
 		}
 	}
-	appendIfPresent("Auth Method", ctx.AuthMethod)
-	appendIfPresent("URL", ctx.URL)
-	appendIfPresent("Tenant", ctx.Tenant)
+	// annotate values that don't come from the config file with their source
+	sources := cfg.SettingSources(context)
+	withSource := func(setting, value string) string {
+		if source, found := sources[setting]; found && value != "" {
+			return fmt.Sprintf("%v (%v)", value, source)
+		}
+		return value
+	}
+	if cfg.IsEnvContext(ctx.Name) {
+		appendIfPresent("Source", "environment variables")
+	}
+	appendIfPresent("Extends", ctx.Extends)
+	appendIfPresent("Auth Method", withSource("auth", ctx.AuthMethod))
+	appendIfPresent("URL", withSource("url", ctx.URL))
+	appendIfPresent("Tenant", withSource("tenant", ctx.Tenant))
 	appendIfPresent("User ID", ctx.User)
-	appendIfPresent("Token", ctx.Token)
-	appendIfPresent("Refresh Token", ctx.RefreshToken)
-	appendIfPresent("Secret File", ctx.SecretFile)
-	appendIfPresent("Environment", humanizeEnvType(ctx.EnvType))
-	appendIfPresent("Local Auth", ctx.LocalAuthOptions.String())
+	appendIfPresent("Token", withSource("token", ctx.Token))
+	appendIfPresent("Refresh Token", withSource("refresh-token", ctx.RefreshToken))
+	if ctx.TokenExpiry != 0 {
+		appendIfPresent("Token Expiry", time.Unix(ctx.TokenExpiry, 0).Local().Format(time.RFC3339))
+	}
+	appendIfPresent("OAuth Flow", withSource("oauth-flow", ctx.OAuthFlow))
+	appendIfPresent("Secret File", withSource("secret-file", ctx.SecretFile))
+	appendIfPresent("Credentials", withSource("credentials", ctx.CredentialsSource))
+	appendIfPresent("Client ID", withSource("client-id", ctx.ClientID))
+	appendIfPresent("Client Secret", withSource("client-secret", ctx.ClientSecret))
+	appendIfPresent("Secret Store", ctx.SecretStore)
+	appendIfPresent("Environment", withSource("envtype", humanizeEnvType(ctx.EnvType)))
+	appendIfPresent("Local Auth", withSource("local-auth", ctx.LocalAuthOptions.String()))
+	appendIfPresent("CA File", withSource("ca-file", ctx.CAFile))
+	if ctx.InsecureSkipVerify {
+		appendIfPresent("TLS Verify", withSource("insecure-skip-verify", "disabled (insecure)"))
+	}
+	appendIfPresent("Client Cert", withSource("client-cert", ctx.ClientCertFile))
+	appendIfPresent("Client Key", withSource("client-key", ctx.ClientKeyFile))
+	appendIfPresent("Proxy", withSource("proxy", ctx.ProxyURL))
+	appendIfPresent("No Proxy", withSource("no-proxy", ctx.NoProxy))
+	if ctx.DisableKeepAlive {
+		appendIfPresent("Keep-Alive", withSource("keep-alive", "disabled"))
+	}
 
 	if ctx.SubsystemConfigs != nil && len(ctx.SubsystemConfigs) > 0 {
 		// get sorted list of subsystems
@@ -116,12 +154,14 @@ func outputContext(cmd *cobra.Command, context *cfg.Context, useIndicator string
 			}
 
 			// produce single line config for the subsystem
-			values := formatSubsystemConfig(ctx.SubsystemConfigs[name])
+			values := formatSubsystemConfig(ctx.SubsystemConfigs[name], func(setting, value string) string {
+				return withSource(name+"."+setting, value)
+			})
 			appendIfPresent(fmt.Sprintf("\t%c %*s", graph, width, name), values) // tab indents unlike spaces
 		}
 	}
 
-	output.PrintCmdOutputCustom(cmd, ctx, &output.Table{
+	return output.PrintCmdOutputCustom(cmd, ctx, &output.Table{
 		Headers: headers,
 		Lines:   [][]string{values},
 		Detail:  true,
@@ -141,14 +181,14 @@ func humanizeEnvType(val string) string {
 	}
 }
 
-func formatSubsystemConfig(config map[string]any) string {
+func formatSubsystemConfig(config map[string]any, withSource func(setting, value string) string) string {
 	if len(config) == 0 {
 		return "(empty)" // shouldn't happen but provide for it if it does
 	}
 
 	params := []string{}
 	for name, value := range config {
-		params = append(params, fmt.Sprintf("%v=%v", name, subsystemValue(value)))
+		params = append(params, fmt.Sprintf("%v=%v", name, withSource(name, subsystemValue(value))))
 	}
 
 	// TODO: ellide if too long
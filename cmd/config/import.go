@@ -0,0 +1,198 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
+	cfg "github.com/cisco-open/fsoc/config"
+	"github.com/cisco-open/fsoc/output"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// importResult describes what was done with an imported context
+type importResult struct {
+	Name    string `json:"name"`
+	From    string `json:"from"`   // name of the context in the bundle
+	Status  string `json:"status"` // created, overwritten, renamed or skipped
+	Message string `json:"message,omitempty"`
+}
+
+func newCmdConfigImport() *cobra.Command {
+
+	var cmd = &cobra.Command{
+		Use:   "import FILE [--overwrite | --skip | --rename]",
+		Short: "Import contexts from a bundle",
+		Long: `Import contexts (profiles) from a bundle created with "fsoc config export" into the fsoc config file.
+Use "-" as the file name to read the bundle from the standard input.
+
+Each context is validated before any of them is imported: it must have the settings required for its
+authentication method and valid subsystem settings. If a context with the same name already exists, the
+import fails unless one of the following is specified:
+  --overwrite  replace the existing context with the imported one
+  --skip       keep the existing context and don't import the bundle's one
+  --rename     import the context with a new name, adding a numeric suffix (e.g., prod-2)
+
+Settings that were redacted on export (e.g., the secret file path) must be set after importing, as reported.
+
+Since bundles are often shared or downloaded, settings that could make fsoc run arbitrary commands or weaken
+the security of its connections are not imported, unless explicitly allowed; the dropped settings are reported:
+  --allow-exec-credentials  import credentials obtained by running a command (credentials=exec:COMMAND)
+  --allow-insecure          import the insecure-skip-verify, ca-file and proxy settings
+The secret store setting is never imported, as it is a local choice.`,
+		Example: `  fsoc config import team-profiles.yaml
+  fsoc config import team-profiles.yaml --rename
+  curl -s https://wiki.example.com/fsoc/profiles.yaml | fsoc config import - --skip`,
+		Args: cobra.ExactArgs(1),
+		RunE: configImport,
+		Annotations: map[string]string{
+			cfg.AnnotationForConfigBypass: "",
+			output.TableFieldsAnnotation:  "name:.name, from:.from, status:.status, message:(.message // \"\")",
+		},
+	}
+
+	cmd.Flags().Bool("overwrite", false, "Replace existing contexts with the imported ones")
+	cmd.Flags().Bool("skip", false, "Keep existing contexts, skipping the imported ones")
+	cmd.Flags().Bool("rename", false, "Import contexts that already exist with a new name")
+	cmd.MarkFlagsMutuallyExclusive("overwrite", "skip", "rename")
+	cmd.Flags().Bool("allow-exec-credentials", false, "Import credentials sources that run a command")
+	cmd.Flags().Bool("allow-insecure", false, "Import the insecure-skip-verify, ca-file and proxy settings")
+
+	return cmd
+}
+
+func configImport(cmd *cobra.Command, args []string) error {
+	overwrite, _ := cmd.Flags().GetBool("overwrite")
+	skip, _ := cmd.Flags().GetBool("skip")
+	rename, _ := cmd.Flags().GetBool("rename")
+	var allow cfg.ImportPermissions
+	allow.ExecCredentials, _ = cmd.Flags().GetBool("allow-exec-credentials")
+	allow.Insecure, _ = cmd.Flags().GetBool("allow-insecure")
+
+	// read & parse the bundle
+	var data []byte
+	var err error
+	if args[0] == "-" {
+		data, err = io.ReadAll(cmd.InOrStdin())
+	} else {
+		data, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read the profile bundle: %w", err)
+	}
+	bundle, err := cfg.ParseBundle(data)
+	if err != nil {
+		return clierror.Wrap(clierror.Validation, err)
+	}
+
+	// validate all contexts and resolve name conflicts before importing any of them
+	existing := map[string]bool{}
+	for _, name := range cfg.ListAllContexts() {
+		existing[name] = true
+	}
+	conflicts := []string{}
+	results := []*importResult{}
+	for i := range bundle.Contexts {
+		ctx := &bundle.Contexts[i]
+		dropped := cfg.DropUnsafeSettings(ctx, allow)
+		if err := validateImportedContext(ctx, existing); err != nil {
+			return clierror.Wrap(clierror.Validation, fmt.Errorf("context %q is not valid: %w", ctx.Name, err))
+		}
+
+		result := &importResult{Name: ctx.Name, From: ctx.Name, Status: "created"}
+		messages := []string{}
+		if len(dropped) > 0 {
+			messages = append(messages, fmt.Sprintf("not imported: %v", strings.Join(dropped, ", ")))
+		}
+		if redacted := cfg.RedactedSettings(ctx); len(redacted) > 0 {
+			messages = append(messages, fmt.Sprintf("set %v with: fsoc config set --profile %v ...", strings.Join(redacted, ", "), ctx.Name))
+		}
+		result.Message = strings.Join(messages, "; ")
+		if existing[ctx.Name] {
+			switch {
+			case overwrite:
+				result.Status = "overwritten"
+			case skip:
+				result.Status = "skipped"
+				result.Message = "a context with this name already exists"
+			case rename:
+				result.Name = uniqueContextName(ctx.Name, existing)
+				result.Status = "renamed"
+				result.Message = strings.ReplaceAll(result.Message, "--profile "+ctx.Name, "--profile "+result.Name)
+			default:
+				conflicts = append(conflicts, ctx.Name)
+			}
+		}
+		existing[result.Name] = true
+		results = append(results, result)
+	}
+	if len(conflicts) > 0 {
+		return clierror.New(clierror.Conflict, "context(s) %v already exist; use --overwrite, --skip or --rename", conflicts)
+	}
+
+	// import
+	for i, result := range results {
+		if result.Status == "skipped" {
+			continue
+		}
+		ctx := &bundle.Contexts[i]
+		ctx.Name = result.Name
+		cfg.ClearRedacted(ctx)
+		if err := cfg.UpsertContext(ctx); err != nil {
+			return fmt.Errorf("failed to import context %q: %w", result.From, err)
+		}
+	}
+
+	return output.PrintCmdOutput(cmd, struct {
+		Items []*importResult `json:"items"`
+		Total int             `json:"total"`
+	}{results, len(results)})
+}
+
+// validateImportedContext checks that the context is operable: it has the settings required for its
+// authentication method, valid subsystem settings and, if it extends a context, the context exists.
+// Redacted settings have placeholder values, so the settings are considered present.
+func validateImportedContext(ctx *cfg.Context, existing map[string]bool) error {
+	ctx.RefreshToken, ctx.TokenExpiry, ctx.User = "", 0, "" // never import sessions
+	if ctx.AuthMethod != cfg.AuthMethodJWT {
+		ctx.Token = ""
+	} else if ctx.Token == "" {
+		ctx.Token = cfg.RedactedPlaceholder
+	}
+	if err := api.CheckConfigForAuth(ctx); err != nil {
+		return err
+	}
+	if ctx.Extends != "" && !existing[ctx.Extends] {
+		return fmt.Errorf("it extends context %q, which doesn't exist", ctx.Extends)
+	}
+	// note: this parses the settings into the subsystems' configuration, which is not used by this command
+	return cfg.UpdateSubsystemConfigs(ctx)
+}
+
+// uniqueContextName returns the name with the smallest numeric suffix that is not in use
+func uniqueContextName(name string, existing map[string]bool) string {
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%v-%d", name, i)
+		if !existing[candidate] {
+			return candidate
+		}
+	}
+}
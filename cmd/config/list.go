@@ -63,7 +63,7 @@ func configListContexts(cmd *cobra.Command, args []string) error {
 	for _, name := range profiles {
 		context, err := cfg.GetContext(name)
 		if err != nil {
-			log.Warnf("(bug?) can't find listed context %q: %v; skipping", name, err)
+			log.Warnf("Skipping profile %q: %v", name, err)
 			continue
 		}
 
@@ -77,7 +77,9 @@ func configListContexts(cmd *cobra.Command, args []string) error {
 
 		// display detailed view using the "get" command displayer
 		if detailView {
-			outputContext(cmd, context, use) // adds extra line
+			if err := outputContext(cmd, context, use); err != nil { // adds extra line
+				return err
+			}
 			continue
 		}
 
@@ -95,13 +97,15 @@ func configListContexts(cmd *cobra.Command, args []string) error {
 
 	// output data (for all output formats except "detail" which is already displayed)
 	if !detailView {
-		output.PrintCmdOutput(cmd, struct {
+		if err := output.PrintCmdOutput(cmd, struct {
 			Items []map[string]any `json:"items"`
 			Total int              `json:"total"`
 		}{
 			contextList,
 			len(contextList),
-		})
+		}); err != nil {
+			return err
+		}
 	}
 
 	return nil
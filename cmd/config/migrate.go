@@ -0,0 +1,83 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
+	cfg "github.com/cisco-open/fsoc/config"
+	"github.com/cisco-open/fsoc/output"
+)
+
+func newCmdConfigMigrateSecrets() *cobra.Command {
+
+	var cmd = &cobra.Command{
+		Use:   "migrate-secrets [CONTEXT_NAME...] --to STORE",
+		Short: "Move the secrets of contexts to a different secret store",
+		Long: `Move the access tokens, refresh tokens and secret file paths of contexts to a different secret store.
+
+Supported secret stores:
+  plaintext    in the config file (default)
+  vault        in an encrypted vault file, next to the config file (or in the file set in FSOC_VAULT_FILE).
+               The encryption key is taken from the FSOC_VAULT_KEY environment variable (base64-encoded,
+               32 bytes) or derived from the passphrase in the FSOC_VAULT_PASSPHRASE environment variable.
+  helper:NAME  in an external credential helper, the fsoc-credential-NAME executable in the PATH,
+               invoked as "fsoc-credential-NAME get|store|erase" (similar to git and docker credential helpers)
+
+Once migrated, secrets are stored in and obtained from the context's new secret store automatically.
+If no context is specified, the current context is migrated.`,
+		Example: `  FSOC_VAULT_PASSPHRASE=... fsoc config migrate-secrets --to vault
+  fsoc config migrate-secrets --all --to helper:pass
+  fsoc config migrate-secrets ci --to plaintext`,
+		ValidArgsFunction: validArgsAutocomplete,
+		RunE:              configMigrateSecrets,
+	}
+
+	cmd.Flags().String("to", "", "Secret store to move the secrets to: plaintext, vault or helper:NAME")
+	_ = cmd.MarkFlagRequired("to")
+	cmd.Flags().Bool("all", false, "Migrate all contexts")
+
+	return cmd
+}
+
+func configMigrateSecrets(cmd *cobra.Command, args []string) error {
+	store, _ := cmd.Flags().GetString("to")
+	if _, err := cfg.GetSecretStore(store); err != nil {
+		return clierror.Wrap(clierror.Usage, err)
+	}
+
+	all, _ := cmd.Flags().GetBool("all")
+	profiles := args
+	if all {
+		if len(args) > 0 {
+			return clierror.New(clierror.Usage, "contexts cannot be specified together with --all")
+		}
+		profiles = cfg.ListAllContexts()
+	} else if len(profiles) == 0 {
+		profiles = []string{cfg.GetCurrentProfileName()}
+	}
+
+	for _, profile := range profiles {
+		if err := cfg.MigrateSecrets(profile, store); err != nil {
+			return fmt.Errorf("failed to migrate the secrets of context %q: %w", profile, err)
+		}
+	}
+
+	output.PrintCmdStatus(cmd, fmt.Sprintf("Migrated the secrets of %d context(s) to the %v secret store\n", len(profiles), store))
+	return nil
+}
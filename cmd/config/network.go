@@ -0,0 +1,145 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strconv"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+
+	cfg "github.com/cisco-open/fsoc/config"
+)
+
+// networkArgs are the settings that control how fsoc connects to the platform, independent
+// of the authentication method. Setting any of them to an empty value removes the setting.
+var networkArgs = []string{"ca-file", "insecure-skip-verify", "client-cert", "client-key", "proxy", "no-proxy", "keep-alive"}
+
+// addNetworkFlags adds hidden flags for the network settings, so that "config set" can transfer them
+// from arguments like the other core settings (they were never supported as command line flags)
+func addNetworkFlags(cmd *cobra.Command) {
+	for _, name := range networkArgs {
+		cmd.Flags().String(name, "", fmt.Sprintf(`Set the %v setting (use %v=VALUE instead)`, name, name))
+		_ = cmd.Flags().MarkHidden(name)
+	}
+}
+
+// changedNetworkFlags returns the network settings that were set on the command line of "config set"
+func changedNetworkFlags(cmd *cobra.Command) map[string]string {
+	settings := map[string]string{}
+	for _, name := range networkArgs {
+		if cmd.Flags().Changed(name) {
+			settings[name], _ = cmd.Flags().GetString(name)
+		}
+	}
+	return settings
+}
+
+// updateNetworkSettings sets the network settings on the context, removing the processed settings from the map.
+// It returns an error if any of the values is not valid.
+func updateNetworkSettings(ctxPtr *cfg.Context, settings map[string]string) error {
+	var err error
+
+	if val, ok := settings["ca-file"]; ok {
+		if ctxPtr.CAFile, err = absPathSetting(val); err != nil {
+			return err
+		}
+		delete(settings, "ca-file")
+	}
+
+	if val, ok := settings["insecure-skip-verify"]; ok {
+		if ctxPtr.InsecureSkipVerify, err = boolSetting("insecure-skip-verify", val, false); err != nil {
+			return err
+		}
+		if ctxPtr.InsecureSkipVerify {
+			log.Warn("TLS certificate verification is disabled for this profile; use only for development")
+		}
+		delete(settings, "insecure-skip-verify")
+	}
+
+	if val, ok := settings["client-cert"]; ok {
+		if ctxPtr.ClientCertFile, err = absPathSetting(val); err != nil {
+			return err
+		}
+		delete(settings, "client-cert")
+	}
+
+	if val, ok := settings["client-key"]; ok {
+		if ctxPtr.ClientKeyFile, err = absPathSetting(val); err != nil {
+			return err
+		}
+		delete(settings, "client-key")
+	}
+
+	if val, ok := settings["proxy"]; ok {
+		if val != "" {
+			proxyUrl, err := url.Parse(val)
+			if err != nil || proxyUrl.Host == "" {
+				return fmt.Errorf("the proxy setting %q must be a URL, e.g., http://proxy.example.com:8080", val)
+			}
+		}
+		ctxPtr.ProxyURL = val
+		delete(settings, "proxy")
+	}
+
+	if val, ok := settings["no-proxy"]; ok {
+		ctxPtr.NoProxy = val
+		delete(settings, "no-proxy")
+	}
+
+	if val, ok := settings["keep-alive"]; ok {
+		keepAlive, err := boolSetting("keep-alive", val, true)
+		if err != nil {
+			return err
+		}
+		ctxPtr.DisableKeepAlive = !keepAlive
+		delete(settings, "keep-alive")
+	}
+
+	if (ctxPtr.ClientCertFile == "") != (ctxPtr.ClientKeyFile == "") {
+		log.Warn("Both client-cert and client-key must be set in order to use a client certificate")
+	}
+
+	return nil
+}
+
+// absPathSetting canonicalizes a file path setting into an absolute path; an empty value remains empty
+func absPathSetting(val string) (string, error) {
+	if val == "" {
+		return "", nil
+	}
+	path := expandHomePath(val)
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		log.WithFields(log.Fields{"path": path, "error": err}).Warn("Failed to convert the file's path to an absolute path; using it as is")
+		return path, nil
+	}
+	return absPath, nil
+}
+
+// boolSetting parses a boolean setting value, returning the default value if empty
+func boolSetting(name string, val string, defaultValue bool) (bool, error) {
+	if val == "" {
+		return defaultValue, nil
+	}
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, fmt.Errorf("the %v setting must be true or false, found %q", name, val)
+	}
+	return b, nil
+}
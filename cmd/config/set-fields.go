@@ -32,6 +32,7 @@ func getAuthFieldWritePermissions() map[string]AuthFieldConfigRow {
 		cfg.AuthMethodNone: {
 			"client-ID":     ClearField,
 			"secret-file":   ClearField,
+			"credentials":   ClearField,
 			"token":         ClearField,
 			"tenant":        ClearField,
 			"url":           AllowField,
@@ -44,6 +45,7 @@ func getAuthFieldWritePermissions() map[string]AuthFieldConfigRow {
 		cfg.AuthMethodOAuth: {
 			"client-ID":     ClearField,
 			"secret-file":   ClearField,
+			"credentials":   ClearField,
 			"token":         ClearField,
 			"tenant":        ClearField,
 			"url":           AllowField,
@@ -56,6 +58,7 @@ func getAuthFieldWritePermissions() map[string]AuthFieldConfigRow {
 		cfg.AuthMethodJWT: {
 			"client-ID":     ClearField,
 			"secret-file":   ClearField,
+			"credentials":   ClearField,
 			"token":         AllowField,
 			"tenant":        AllowField,
 			"url":           AllowField,
@@ -68,6 +71,7 @@ func getAuthFieldWritePermissions() map[string]AuthFieldConfigRow {
 		cfg.AuthMethodServicePrincipal: {
 			"client-ID":     ClearField,
 			"secret-file":   AllowField,
+			"credentials":   AllowField,
 			"token":         ClearField,
 			"tenant":        AllowField,
 			"url":           AllowField,
@@ -80,6 +84,7 @@ func getAuthFieldWritePermissions() map[string]AuthFieldConfigRow {
 		cfg.AuthMethodAgentPrincipal: {
 			"client-ID":     ClearField,
 			"secret-file":   AllowField,
+			"credentials":   AllowField,
 			"token":         ClearField,
 			"tenant":        AllowField,
 			"url":           AllowField,
@@ -92,6 +97,7 @@ func getAuthFieldWritePermissions() map[string]AuthFieldConfigRow {
 		cfg.AuthMethodLocal: {
 			"client-ID":     ClearField,
 			"secret-file":   ClearField,
+			"credentials":   ClearField,
 			"token":         ClearField,
 			"tenant":        ClearField,
 			"url":           AllowField,
@@ -112,6 +118,7 @@ func getAuthFieldClearConfig() map[string]authClearFields {
 		cfg.AuthMethodNone: {
 			"client-ID":     {},
 			"secret-file":   {},
+			"credentials":   {},
 			"token":         {},
 			"tenant":        {},
 			"url":           {},
@@ -124,6 +131,7 @@ func getAuthFieldClearConfig() map[string]authClearFields {
 		cfg.AuthMethodOAuth: {
 			"client-ID":     {}, //NA
 			"secret-file":   {}, //NA
+			"credentials":   {}, //NA
 			"token":         {}, //NA
 			"tenant":        {}, //NA
 			"url":           {"tenant", "user", "token", "refresh-token", "secret-file"},
@@ -136,6 +144,7 @@ func getAuthFieldClearConfig() map[string]authClearFields {
 		cfg.AuthMethodJWT: {
 			"client-ID":     {},
 			"secret-file":   {},
+			"credentials":   {},
 			"token":         {},
 			"tenant":        {"token", "user"},
 			"url":           {"token", "tenant", "user"},
@@ -148,6 +157,7 @@ func getAuthFieldClearConfig() map[string]authClearFields {
 		cfg.AuthMethodServicePrincipal: {
 			"client-ID":     {},
 			"secret-file":   {"url", "tenant", "user", "token", "refresh-token"},
+			"credentials":   {"user", "token", "refresh-token"},
 			"token":         {},
 			"tenant":        {},
 			"url":           {"tenant", "user", "token", "refresh-token"},
@@ -160,6 +170,7 @@ func getAuthFieldClearConfig() map[string]authClearFields {
 		cfg.AuthMethodAgentPrincipal: {
 			"client-ID":     {},
 			"secret-file":   {"url", "tenant", "user", "token", "refresh-token"},
+			"credentials":   {"user", "token", "refresh-token"},
 			"token":         {},
 			"tenant":        {},
 			"url":           {"tenant", "user", "token", "refresh-token"},
@@ -172,6 +183,7 @@ func getAuthFieldClearConfig() map[string]authClearFields {
 		cfg.AuthMethodLocal: {
 			"client-ID":     {},
 			"secret-file":   {},
+			"credentials":   {},
 			"token":         {},
 			"tenant":        {},
 			"url":           {},
@@ -28,6 +28,7 @@ import (
 	"github.com/spf13/pflag"
 	"golang.org/x/exp/slices"
 
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
 	cfg "github.com/cisco-open/fsoc/config"
 	"github.com/cisco-open/fsoc/output"
 	"github.com/cisco-open/fsoc/platform/api"
@@ -61,8 +62,18 @@ Note that each authentication method requires a slightly different set of values
   fsoc config set auth=agent-principal secret-file=collectors-values.yaml
   fsoc config set auth=agent-principal secret-file=client-values.json tenant=123456 url=https://mytenant.observe.appdynamics.com
 
+  # Obtain service principal credentials from environment variables, the standard input or a command
+  fsoc config set auth=service-principal credentials=env tenant=123456 url=https://mytenant.observe.appdynamics.com
+  fsoc config set auth=service-principal credentials="exec:vault kv get -format=json -field=data secret/fsoc" tenant=123456 url=https://mytenant.observe.appdynamics.com
+
+  # Inherit the settings of the "prod" profile, overriding the tenant
+  fsoc config set --profile prod-eu extends=prod url=https://mytenant-eu.observe.appdynamics.com
+
   # Set local access
   fsoc config set auth=local url=http://localhost appd-pid=PID appd-tid=TID appd-pty=PTY
+
+  # Use a corporate proxy and CA bundle
+  fsoc config set proxy=http://proxy.example.com:8080 ca-file=~/certs/corporate-ca.pem
   
   # Set the token field on the "prod" context entry without touching other values
   fsoc config set profile prod token=top-secret --patch
@@ -75,17 +86,19 @@ Note that each authentication method requires a slightly different set of values
 // configArgs are the positional arguments of form <name>=<value> that can be set.
 // They also correspond to the --flags for the same, for backward compatibility (deprecated)
 // The order here is how the fields are displayed in `config show-help` topic
-var configArgs = []string{"auth", "url", "tenant", "secret-file", "envtype", "token", cfg.AppdTid, cfg.AppdPty, cfg.AppdPid, "server"}
+var configArgs = append(append([]string{"extends", "auth", "url", "tenant", "secret-file", "credentials", "envtype", "token", "oauth-flow", cfg.AppdTid, cfg.AppdPty, cfg.AppdPid},
+	networkArgs...), "server")
 
 func newCmdConfigSet() *cobra.Command {
 
 	var cmd = &cobra.Command{
-		Use:         "set [--config CONFIG_FILE] [--profile CONTEXT] [SETTING=VALUE]+",
-		Short:       "Create or modify a context entry in an fsoc config file",
-		Long:        setContextLong,
-		Example:     setContextExample,
-		Annotations: map[string]string{cfg.AnnotationForConfigBypass: ""},
-		Run:         configSetContext,
+		Use:               "set [--config CONFIG_FILE] [--profile CONTEXT] [SETTING=VALUE]+",
+		Short:             "Create or modify a context entry in an fsoc config file",
+		Long:              setContextLong,
+		Example:           setContextExample,
+		Annotations:       map[string]string{cfg.AnnotationForConfigBypass: ""},
+		RunE:              configSetContext,
+		ValidArgsFunction: settingArgsAutocomplete,
 	}
 
 	// real command flag(s)
@@ -115,11 +128,18 @@ func newCmdConfigSet() *cobra.Command {
 	_ = cmd.Flags().MarkDeprecated("secret-file", `please use non-flag argument in the form "secret-file=SECRET-TOKEN"`)
 	cmd.Flags().String("envtype", "", "envtype can be \"dev\", \"prod\", or \"\". When it is \"dev\", solution tags will always be set to stable")
 	_ = cmd.Flags().MarkDeprecated("envtype", `please use non-flag argument in the form "envtype=ENVTYPE"`)
+	cmd.Flags().String("oauth-flow", "", "Set the OAuth login flow (use oauth-flow=FLOW instead)")
+	_ = cmd.Flags().MarkHidden("oauth-flow")
+	cmd.Flags().String("credentials", "", "Set the source of principal credentials (use credentials=SOURCE instead)")
+	_ = cmd.Flags().MarkHidden("credentials")
+	cmd.Flags().String("extends", "", "Set the profile to inherit settings from (use extends=PROFILE instead)")
+	_ = cmd.Flags().MarkHidden("extends")
+	addNetworkFlags(cmd)
 
 	return cmd
 }
 
-func configSetContext(cmd *cobra.Command, args []string) {
+func configSetContext(cmd *cobra.Command, args []string) error {
 	var contextName string
 	var subsystemSettingArgs []string
 	var err error
@@ -132,7 +152,7 @@ func configSetContext(cmd *cobra.Command, args []string) {
 	// as subsystem-specific settings
 	subsystemSettingArgs, err = transferCoreArgs(cmd, args)
 	if err != nil {
-		log.Fatalf("%v", err)
+		return err
 	}
 
 	// Check that at least one config value is specified (including empty); either core or subsytem-specific setting satisfies this check
@@ -144,11 +164,14 @@ func configSetContext(cmd *cobra.Command, args []string) {
 		}
 	})
 	if !valid {
-		log.Fatalf("at least one of %v must be specified", strings.Join(configArgs, ", ")) // TODO expand the message to accommodate subsystem-specific settings
+		return fmt.Errorf("at least one of %v must be specified", strings.Join(configArgs, ", ")) // TODO expand the message to accommodate subsystem-specific settings
 	}
 
 	// Try to locate the named context, whether it exists or not
 	contextName = cfg.GetCurrentProfileName() // it may not exist
+	if cfg.IsEnvContext(contextName) {
+		return clierror.New(clierror.Usage, "profile %q is defined by environment variables and cannot be modified; unset %v to manage the config file's profiles", contextName, cfg.FSOC_URL_ENVVAR)
+	}
 	ctxPtr, err := cfg.GetContext(contextName)
 	if errors.Is(err, cfg.ErrProfileNotFound) {
 		log.Infof("Context %q doesn't exist, creating it", contextName)
@@ -164,16 +187,23 @@ func configSetContext(cmd *cobra.Command, args []string) {
 	// update only the fields for which flags were specified explicitly
 	// (and, force-clear dependent/auto-derived fields unless --patch)
 
+	if flags.Changed("extends") { // first, so that the other settings apply over the inherited ones
+		val, _ := flags.GetString("extends")
+		if err := cfg.SetExtends(ctxPtr, val); err != nil {
+			return err
+		}
+	}
+
 	if flags.Changed("auth") {
 		val, _ := flags.GetString("auth")
 		if val != "" && !slices.Contains(GetAuthMethodsStringList(), val) {
-			log.Fatalf(`Invalid --auth method %q; must be one of {"%v"}`, val, strings.Join(GetAuthMethodsStringList(), `", "`))
+			return fmt.Errorf(`invalid --auth method %q; must be one of {"%v"}`, val, strings.Join(GetAuthMethodsStringList(), `", "`))
 		}
 		ctxPtr.AuthMethod = val
 
 		// Clear All fields before setting other fields
 		if !patch {
-			clearFields([]string{"url", "server", "tenant", "user", "token", "refresh_token", "secret-file"}, ctxPtr)
+			clearFields([]string{"url", "server", "tenant", "user", "token", "refresh_token", "secret-file", "credentials"}, ctxPtr)
 		}
 	}
 
@@ -181,7 +211,7 @@ func configSetContext(cmd *cobra.Command, args []string) {
 		val, _ := flags.GetString("envtype")
 		potentialEnvTypes := []string{"prod", "dev"}
 		if !slices.Contains(potentialEnvTypes, val) {
-			log.Fatalf("envtype can only take on one of the following values: %s", strings.Join(potentialEnvTypes, ", "))
+			return fmt.Errorf("envtype can only take on one of the following values: %s", strings.Join(potentialEnvTypes, ", "))
 		}
 		ctxPtr.EnvType = val
 	}
@@ -194,7 +224,7 @@ func configSetContext(cmd *cobra.Command, args []string) {
 		}
 		cleanedUrl, err := validateUrl(providedUrl)
 		if err != nil {
-			log.Fatal(err.Error())
+			return err
 		}
 		if flags.Changed("server") {
 			log.Warnf("The --server option is now deprecated. In the future, please use --url instead. We will set the url to %q for you now", cleanedUrl)
@@ -205,7 +235,7 @@ func configSetContext(cmd *cobra.Command, args []string) {
 		// (note that ctxPtr.EnvType is already set if specified on the command line)
 		parsedUrl, err := url.Parse(cleanedUrl)
 		if err != nil {
-			log.Fatalf("Failed to parse url: %v", err)
+			return fmt.Errorf("failed to parse url: %w", err)
 		}
 		host := parsedUrl.Host
 		if !strings.HasSuffix(host, ".observe.appdynamics.com") && (ctxPtr.EnvType != "") {
@@ -221,7 +251,7 @@ func configSetContext(cmd *cobra.Command, args []string) {
 	if flags.Changed("tenant") {
 		err := validateWriteReq(cmd, ctxPtr.AuthMethod, "tenant")
 		if err != nil {
-			log.Fatal(err.Error())
+			return err
 		}
 		ctxPtr.Tenant, _ = flags.GetString("tenant")
 		if !patch {
@@ -232,7 +262,7 @@ func configSetContext(cmd *cobra.Command, args []string) {
 	if flags.Changed("token") {
 		err := validateWriteReq(cmd, ctxPtr.AuthMethod, "token")
 		if err != nil {
-			log.Fatal(err.Error())
+			return err
 		}
 		value, _ := flags.GetString("token")
 		if value == "-" { // token to come from stdin
@@ -242,6 +272,7 @@ func configSetContext(cmd *cobra.Command, args []string) {
 		} else {
 			ctxPtr.Token = value
 		}
+		ctxPtr.TokenExpiry = 0 // determined from the new token when needed
 		if !patch {
 			automatedFieldClearing(ctxPtr, "token")
 		}
@@ -250,7 +281,7 @@ func configSetContext(cmd *cobra.Command, args []string) {
 	if flags.Changed("secret-file") {
 		err := validateWriteReq(cmd, ctxPtr.AuthMethod, "secret-file")
 		if err != nil {
-			log.Fatal(err.Error())
+			return err
 		}
 		path, _ := flags.GetString("secret-file")
 		path = expandHomePath(path)
@@ -265,12 +296,37 @@ func configSetContext(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	if flags.Changed("credentials") {
+		err := validateWriteReq(cmd, ctxPtr.AuthMethod, "credentials")
+		if err != nil {
+			return err
+		}
+		val, _ := flags.GetString("credentials")
+		if val != "" {
+			if _, _, err := cfg.ParseCredentialsSource(val); err != nil {
+				return err
+			}
+		}
+		ctxPtr.CredentialsSource = val
+		if !patch {
+			automatedFieldClearing(ctxPtr, "credentials")
+		}
+	}
+
+	if flags.Changed("oauth-flow") {
+		val, _ := flags.GetString("oauth-flow")
+		if err := validateOAuthFlow(val); err != nil {
+			return err
+		}
+		ctxPtr.OAuthFlow = val
+	}
+
 	// populate fields for local auth
 	if ctxPtr.AuthMethod == cfg.AuthMethodLocal {
 		if flags.Changed(cfg.AppdPid) {
 			err := validateWriteReq(cmd, ctxPtr.AuthMethod, cfg.AppdPid)
 			if err != nil {
-				log.Fatal(err.Error())
+				return err
 			}
 			pid, _ := flags.GetString(cfg.AppdPid)
 			ctxPtr.LocalAuthOptions.AppdPid = pid
@@ -281,7 +337,7 @@ func configSetContext(cmd *cobra.Command, args []string) {
 		if flags.Changed(cfg.AppdPty) {
 			err := validateWriteReq(cmd, ctxPtr.AuthMethod, cfg.AppdPty)
 			if err != nil {
-				log.Fatal(err.Error())
+				return err
 			}
 			pty, _ := flags.GetString(cfg.AppdPty)
 			ctxPtr.LocalAuthOptions.AppdPty = pty
@@ -292,7 +348,7 @@ func configSetContext(cmd *cobra.Command, args []string) {
 		if flags.Changed(cfg.AppdTid) {
 			err := validateWriteReq(cmd, ctxPtr.AuthMethod, cfg.AppdTid)
 			if err != nil {
-				log.Fatal(err.Error())
+				return err
 			}
 			tid, _ := flags.GetString(cfg.AppdTid)
 			ctxPtr.LocalAuthOptions.AppdTid = tid
@@ -302,6 +358,11 @@ func configSetContext(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	// network settings (not dependent on the auth method)
+	if err := updateNetworkSettings(ctxPtr, changedNetworkFlags(cmd)); err != nil {
+		return err
+	}
+
 	// upgrade config format from CsvFile to SecretFile, opportunistically using the update
 	if ctxPtr.SecretFile == "" && ctxPtr.CsvFile != "" {
 		ctxPtr.SecretFile = ctxPtr.CsvFile
@@ -310,22 +371,42 @@ func configSetContext(cmd *cobra.Command, args []string) {
 
 	// process subsystem-specific settings
 	if err := processSubsystemSettings(ctxPtr, subsystemSettingArgs); err != nil {
-		log.Fatalf("Failed to set subsystem-specific settings: %v", err)
+		return fmt.Errorf("failed to set subsystem-specific settings: %w", err)
+	}
+
+	// settings set to empty values override the inherited ones
+	changedSettings := []string{}
+	for _, name := range configArgs {
+		if flags.Changed(name) {
+			changedSettings = append(changedSettings, name)
+		}
+	}
+	if flags.Changed("server") {
+		changedSettings = append(changedSettings, "url")
 	}
+	cfg.UpdateCleared(ctxPtr, changedSettings)
 
 	// update config file
 	if err := cfg.UpsertContext(ctxPtr); err != nil {
-		log.Fatalf("%v", err)
+		return err
 	}
 
 	// log into the updated context, if requested
 	if login && !noLogin {
 		if err := api.Login(); err != nil {
-			log.Fatalf("Failed to log into the updated context: %v; please update the settings and try again", err)
+			return fmt.Errorf("failed to log into the updated context: %w; please update the settings and try again", err)
 		}
 	}
 
-	output.PrintCmdOutput(cmd, fmt.Sprintf("Context %q updated", contextName))
+	return output.PrintCmdOutput(cmd, fmt.Sprintf("Context %q updated", contextName))
+}
+
+// validateOAuthFlow checks the oauth-flow setting value; empty selects the default flow
+func validateOAuthFlow(val string) error {
+	if val != "" && val != cfg.OAuthFlowBrowser && val != cfg.OAuthFlowDevice {
+		return fmt.Errorf("invalid oauth-flow %q; must be %q or %q", val, cfg.OAuthFlowBrowser, cfg.OAuthFlowDevice)
+	}
+	return nil
 }
 
 // expandHomePath replaces ~ in the path with the absolute home directory
@@ -372,6 +453,7 @@ func clearFields(fields []string, ctxPtr *cfg.Context) {
 	}
 	if slices.Contains(fields, "token") {
 		ctxPtr.Token = ""
+		ctxPtr.TokenExpiry = 0
 	}
 	if slices.Contains(fields, "refresh-token") {
 		ctxPtr.RefreshToken = ""
@@ -379,6 +461,9 @@ func clearFields(fields []string, ctxPtr *cfg.Context) {
 	if slices.Contains(fields, "secret-file") {
 		ctxPtr.SecretFile = ""
 	}
+	if slices.Contains(fields, "credentials") {
+		ctxPtr.CredentialsSource = ""
+	}
 }
 
 func automatedFieldClearing(ctxPtr *cfg.Context, field string) {
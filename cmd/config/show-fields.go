@@ -16,7 +16,6 @@ package config
 
 import (
 	"os"
-	"reflect"
 	"strings"
 
 	"github.com/apex/log"
@@ -48,16 +47,28 @@ The current setting values can be seen with the "config get" command.
 Settings:`
 
 var fieldHelp = map[string]string{
+	"extends":     `name of a profile whose settings this profile inherits, except for its tokens and secret store. The profile's own settings, including subsystem settings, override the inherited ones; setting an inherited core setting to an empty value (e.g., insecure-skip-verify=false) overrides it too.`,
 	"auth":        `authentication method, required. Must be one of "` + strings.Join(GetAuthMethodsStringList(), `", "`) + `".`,
 	"url":         `URL to the tenant, scheme and host/port only; required. For example, https://mytenant.observe.appdynamics.com`,
 	"tenant":      `tenant ID that is required only for auth methods that cannot automatically obtain it. Not needed for the "oauth", "service-principal" and "local" auth methods.`,
 	"secret-file": `file containing login credentials for "service-principal" and "agent-principal" auth methods. The file must remain available, as fsoc saves only the file's path.`,
+	"credentials": `source of the credentials for "service-principal" and "agent-principal" auth methods: "file" (default) reads the secret-file; "env" uses the FSOC_CLIENT_ID and FSOC_CLIENT_SECRET environment variables; "stdin" reads the credentials JSON (same format as the secret file) from the standard input; "exec:COMMAND [ARGS...]" runs the command (arguments are separated by spaces, without shell quoting), which outputs the credentials JSON.`,
 	"envtype":     `platform environment type, optional. Used only for special development/test environments. If specified, can be "dev" or "prod".`,
 	"token":       `authentication token needed only for the "token" auth method.`,
+	"oauth-flow":  `login flow for the "oauth" auth method: "browser" (default) opens a browser on this machine; "device" displays a URL and code to complete the login on any device, for machines without a browser (e.g., over SSH).`,
 	cfg.AppdTid:   `value of ` + cfg.AppdPid + ` to use with the "local" auth method.`,
 	cfg.AppdPty:   `value of ` + cfg.AppdPid + ` to use with the "local" auth method.`,
 	cfg.AppdPid:   `value of ` + cfg.AppdPid + ` to use with the "local" auth method.`,
 	"server":      `synonym for the "url" setting. Deprecated.`,
+
+	// network settings
+	"ca-file":              `file with additional CA certificates (PEM) to trust when connecting to the platform, e.g., a corporate CA bundle.`,
+	"insecure-skip-verify": `set to "true" to skip verifying the platform's TLS certificate. Insecure; use only in development environments.`,
+	"client-cert":          `file with a client certificate (PEM) for mutual TLS; requires client-key.`,
+	"client-key":           `file with the private key (PEM) for the client certificate.`,
+	"proxy":                `URL of the HTTP(S) proxy to use, e.g., http://proxy.example.com:8080. Overrides the HTTPS_PROXY and HTTP_PROXY environment variables.`,
+	"no-proxy":             `comma-separated list of hosts/domains to access without the proxy, in the same format as NO_PROXY (which it overrides).`,
+	"keep-alive":           `set to "false" to disable reusing connections across requests. The default is "true".`,
 }
 
 func configShowFields(cmd *cobra.Command, args []string) {
@@ -78,33 +89,20 @@ func configShowFields(cmd *cobra.Command, args []string) {
 
 	// add subsystem-specific configuration fields
 	for _, subsystemName := range cfg.GetRegisteredSubsystems() {
-		// get config template structure for the subsystem
-		template, err := cfg.GetSubsytemConfigTemplate(subsystemName)
+		settings, err := cfg.GetSubsystemSettings(subsystemName)
 		if err != nil {
 			log.Warnf("Could not obtain config settings for subsysem %q: %v; skipping subsystem", subsystemName, err)
 			continue
 		}
 
 		// collect field names and helps
-		typ := reflect.TypeOf(template).Elem()
-		for i := 0; i < typ.NumField(); i++ {
-			// get info for the field
-			structField := typ.Field(i)
-			mapstructTag := structField.Tag.Get("mapstructure")
-			fsochelpTag := structField.Tag.Get("fsoc-help")
-			mapstructElems := strings.SplitN(mapstructTag, ",", 2)
-			fieldName := mapstructElems[0]
-			if fieldName == "" {
-				log.Warnf("(bug) cannot find name config field %v; skipping", structField.Name)
-				continue
-			}
-			if fsochelpTag == "" {
-				fsochelpTag = "(no description available)"
+		for _, setting := range settings {
+			help := setting.Help
+			if help == "" {
+				help = "(no description available)"
 			}
-
-			// append info
-			fields = append(fields, subsystemName+"."+fieldName) // subsystem.setting
-			helps = append(helps, fsochelpTag)
+			fields = append(fields, subsystemName+"."+setting.Name) // subsystem.setting
+			helps = append(helps, help)
 		}
 	}
 	formatAndDisplayFields(cmd, fields, helps)
@@ -32,13 +32,13 @@ func newCmdConfigUse() *cobra.Command {
 		Long:              `Set the current context in an fsoc config file`,
 		Args:              cobra.MaximumNArgs(1),
 		ValidArgsFunction: validArgsAutocomplete,
-		Run:               configUseContext,
+		RunE:              configUseContext,
 	}
 
 	return cmd
 }
 
-func configUseContext(cmd *cobra.Command, args []string) {
+func configUseContext(cmd *cobra.Command, args []string) error {
 	var newContext string
 
 	// determine which profile to use (supporting --profile for backward compatibility)
@@ -46,7 +46,7 @@ func configUseContext(cmd *cobra.Command, args []string) {
 		newContext, _ = cmd.Flags().GetString("profile")
 		if len(args) > 0 {
 			_ = cmd.Usage()
-			log.Fatalf("The context can be specified either as an argument or as a flag but not as both")
+			return fmt.Errorf("the context can be specified either as an argument or as a flag but not as both")
 		} else {
 			log.Warn("using the --profile flag for this command is deprecated; please, use just the profile name as an argument")
 		}
@@ -56,12 +56,13 @@ func configUseContext(cmd *cobra.Command, args []string) {
 	}
 	if newContext == "" { // also handles empty string argument
 		_ = cmd.Usage()
-		log.Fatalf("Missing the context name argument")
+		return fmt.Errorf("missing the context name argument")
 	}
 
 	if err := cfg.SetDefaultContextName(newContext); err != nil {
-		log.Fatalf("%v", err)
+		return err
 	}
 
 	output.PrintCmdStatus(cmd, fmt.Sprintf("Switched to context %q\n", newContext))
+	return nil
 }
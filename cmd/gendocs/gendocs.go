@@ -52,7 +52,7 @@ commands include relative hyperlinks to individual commands.
 The directory should either be empty or not exist.`,
 	Example:          `  fsoc gendocs /tmp/docs`,
 	Args:             cobra.ExactArgs(1),
-	Run:              genDocs,
+	RunE:             genDocs,
 	TraverseChildren: true,
 	Annotations:      map[string]string{config.AnnotationForConfigBypass: ""},
 }
@@ -65,40 +65,40 @@ func NewSubCmd() *cobra.Command {
 	return gendocsCmd
 }
 
-func genDocs(cmd *cobra.Command, args []string) {
+func genDocs(cmd *cobra.Command, args []string) error {
 	// check path validity
 	path := args[0]
 	if path == "" {
-		log.Fatal(`The path to target directory cannot be empty. Try "fsoc gendocs ./docs".`)
+		return fmt.Errorf(`the path to target directory cannot be empty. Try "fsoc gendocs ./docs".`)
 	}
 
 	// ensure directory is empty (create if needed)
 	fs := &afero.Afero{Fs: afero.NewOsFs()}
 	isExisting, err := fs.Exists(path)
 	if err != nil {
-		log.Fatalf(`Invalid target path %q: %v. Correct it or try "fsoc gendocs ./docs".`, path, err)
+		return fmt.Errorf(`invalid target path %q: %w. Correct it or try "fsoc gendocs ./docs".`, path, err)
 	}
 	if isExisting {
 		// fail if the existing path is not a directory or it is not empty
 		isDir, err := fs.IsDir(path)
 		if err != nil {
-			log.Fatalf(`Invalid target path %q: %v. Correct it or try "fsoc gendocs ./docs".`, path, err)
+			return fmt.Errorf(`invalid target path %q: %w. Correct it or try "fsoc gendocs ./docs".`, path, err)
 		}
 		if !isDir {
-			log.Fatalf(`Target path %q is not a directory. Try a different name, e.g., "./docs".`, path)
+			return fmt.Errorf(`target path %q is not a directory. Try a different name, e.g., "./docs".`, path)
 		}
 		isEmpty, err := fs.IsEmpty(path)
 		if err != nil {
-			log.Fatalf(`Invalid target path %q: %v. Correct it or try "fsoc gendocs ./docs".`, path, err)
+			return fmt.Errorf(`invalid target path %q: %w. Correct it or try "fsoc gendocs ./docs".`, path, err)
 		}
 		if !isEmpty {
-			log.Fatalf(`Target directory %q is not empty. Either delete the files or use try a different name.`, path)
+			return fmt.Errorf(`target directory %q is not empty. Either delete the files or use try a different name.`, path)
 		}
 	} else {
 		// create directory, including intermediate paths
 		err := fs.MkdirAll(path, 0755) // u=rwx,go=rx
 		if err != nil {
-			log.Fatalf("Failed to create target directory %q: %v", path, err)
+			return fmt.Errorf("failed to create target directory %q: %w", path, err)
 		}
 	}
 
@@ -106,14 +106,14 @@ func genDocs(cmd *cobra.Command, args []string) {
 	output.PrintCmdStatus(cmd, "Generating documentation\n")
 	err = doc.GenMarkdownTree(cmd.Parent(), path)
 	if err != nil {
-		log.Fatalf("Error generating fsoc docs: %v", err)
+		return fmt.Errorf("error generating fsoc docs: %w", err)
 	}
 
 	// generate table of contents
 	output.PrintCmdStatus(cmd, "Generating table of contents\n")
 	err = genTableOfContents(cmd, path, fs)
 	if err != nil {
-		log.Fatalf("Error generating fsoc docs table of contents: %v", err)
+		return fmt.Errorf("error generating fsoc docs table of contents: %w", err)
 	}
 
 	flagH1, _ := cmd.Flags().GetBool("h1")
@@ -130,12 +130,13 @@ func genDocs(cmd *cobra.Command, args []string) {
 
 			err := processFile(file, flagH1, flagRelLinks)
 			if err != nil {
-				log.Fatalf(err.Error())
+				return err
 			}
 		}
 	}
 
 	output.PrintCmdStatus(cmd, "Documentation generated successfully.\n")
+	return nil
 }
 
 type tocEntry struct {
@@ -35,7 +35,7 @@ The json/yaml output include the actions and resources for each permissions; the
   fsoc role permissions spacefleet:commandingOfficer
   fsoc role permissions iam:agent -o json`,
 	Args: cobra.ExactArgs(1),
-	Run:  listPermissions,
+	RunE: listPermissions,
 	Annotations: map[string]string{
 		output.TableFieldsAnnotation: "id:.id, name:.data.displayName, description:.data.description",
 	},
@@ -46,6 +46,6 @@ func newCmdRolePermissions() *cobra.Command {
 	return iamRolePermissionsCmd
 }
 
-func listPermissions(cmd *cobra.Command, args []string) {
-	cmdkit.FetchAndPrint(cmd, getIamRoleUrl(args[0], "permissions"), &cmdkit.FetchAndPrintOptions{IsCollection: true})
+func listPermissions(cmd *cobra.Command, args []string) error {
+	return cmdkit.FetchAndPrint(cmd, getIamRoleUrl(args[0], "permissions"), &cmdkit.FetchAndPrintOptions{IsCollection: true})
 }
@@ -15,7 +15,6 @@
 package iamrole
 
 import (
-	"github.com/apex/log"
 	"github.com/spf13/cobra"
 
 	"github.com/cisco-open/fsoc/output"
@@ -35,7 +34,7 @@ This command requires a principal with tenant administrator access.`,
   fsoc iam-role principals spacefleet:commandingOfficer
   fsoc role principals iam:agent -o json`,
 	Args: cobra.ExactArgs(1),
-	Run:  listPrincipals,
+	RunE: listPrincipals,
 	Annotations: map[string]string{
 		output.TableFieldsAnnotation: "id:.id, type:.type",
 	},
@@ -61,15 +60,15 @@ type principalsCollection struct {
 	Items []principalEntry `json:"items"`
 }
 
-func listPrincipals(cmd *cobra.Command, args []string) {
+func listPrincipals(cmd *cobra.Command, args []string) error {
 	// note: the API is not compliant with collections/pagination, so collect as a single request
 	var out principalsResponse
 	err := api.JSONGet(getIamRoleUrl(args[0], "principals"), &out, nil)
 	if err != nil {
-		log.Fatal(err.Error())
+		return err
 	}
 
 	// reflow into a collection structure
 	data := principalsCollection{Total: out.Total, Items: out.Principals}
-	output.PrintCmdOutput(cmd, data)
+	return output.PrintCmdOutput(cmd, data)
 }
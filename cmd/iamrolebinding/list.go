@@ -15,7 +15,6 @@
 package iamrolebinding
 
 import (
-	"github.com/apex/log"
 	"github.com/spf13/cobra"
 
 	"github.com/cisco-open/fsoc/output"
@@ -38,7 +37,7 @@ This command requires a principal with tenant administrator access.`,
   fsoc rb list john@example.com -o json
   fsoc rb list john@example.com -o detail`,
 	Args: cobra.ExactArgs(1),
-	Run:  listRoles,
+	RunE: listRoles,
 	Annotations: map[string]string{
 		output.TableFieldsAnnotation:  "id:.id, name:.data.displayName, description:.data.description",
 		output.DetailFieldsAnnotation: "id:.id, name:.data.displayName, description:.data.description, permissions:(reduce .data.permissions[].id as $o ([]; . + [$o])), scopes:.data.scopes",
@@ -50,14 +49,14 @@ func newCmdRbList() *cobra.Command {
 	return iamRbListCmd
 }
 
-func listRoles(cmd *cobra.Command, args []string) {
+func listRoles(cmd *cobra.Command, args []string) error {
 	// get data
 	var out any
 	requestParams := PrincipalParameter{ID: args[0]}
 	if err := api.JSONPost(getIamRoleBindingsUrl(), requestParams, &out, nil); err != nil {
-		log.Fatal(err.Error())
+		return err
 	}
 
 	// display with formatting
-	output.PrintCmdOutput(cmd, out)
+	return output.PrintCmdOutput(cmd, out)
 }
@@ -0,0 +1,213 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package knowledge
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
+	"github.com/cisco-open/fsoc/config"
+	"github.com/cisco-open/fsoc/output"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// copyResult describes the outcome of copying one knowledge object
+type copyResult struct {
+	ID      string `json:"id"`
+	Status  string `json:"status"` // created, replaced or failed
+	Message string `json:"message,omitempty"`
+}
+
+func newCopyObjectCmd() *cobra.Command {
+	var ltFlag layerType
+
+	cmd := &cobra.Command{
+		Use:   "copy",
+		Short: "Copy knowledge objects from one tenant to another",
+		Long: `Copy knowledge objects from the tenant of one profile to the tenant of another profile, e.g., to promote
+configuration from a staging tenant to a production tenant. The objects are read using the --profile-from profile
+and created using the --profile-to profile (by default, the current profile); fsoc logs in to each as needed.
+
+Either a single object (--object-id) or all objects of the type at the layer (optionally selected with --filter)
+are copied. The objects' data is copied; their IDs are determined by the target tenant the same way as for
+"knowledge create". Objects that already exist in the target tenant are reported as failed, unless --overwrite
+is specified, in which case they are replaced.
+
+The layer ID defaults to each profile's own value for the layer type (e.g., the profile's tenant ID for the TENANT
+layer); use --layer-id and --to-layer-id to specify them explicitly.`,
+		Example: `  # Copy a single object from the staging tenant to the current profile's tenant
+  fsoc knowledge copy --profile-from staging --type=preferences:theme --object-id=dark --layer-type=TENANT
+
+  # Copy all objects of a type between two profiles, replacing the ones that exist
+  fsoc knowledge copy --profile-from staging --profile-to prod --type=preferences:theme --layer-type=TENANT --overwrite`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return copyObjects(cmd, ltFlag)
+		},
+		Annotations: map[string]string{
+			output.TableFieldsAnnotation: "id:.id, status:.status, message:(.message // \"\")",
+		},
+		TraverseChildren: true,
+	}
+
+	cmd.Flags().String("profile-from", "", "Profile of the tenant to copy the objects from")
+	_ = cmd.MarkFlagRequired("profile-from")
+	cmd.Flags().String("profile-to", "", "Profile of the tenant to copy the objects to (default: the current profile)")
+	for _, flag := range []string{"profile-from", "profile-to"} {
+		_ = cmd.RegisterFlagCompletionFunc(flag, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return config.ListContexts(toComplete), cobra.ShellCompDirectiveNoFileComp
+		})
+	}
+
+	cmd.Flags().String("type", "", "Fully qualified type name of the knowledge objects to copy (e.g., extensibility:solution)")
+	_ = cmd.MarkFlagRequired("type")
+	_ = cmd.RegisterFlagCompletionFunc("type", typeCompletionFunc)
+	cmd.Flags().String("object-id", "", "ID of the knowledge object to copy (default: all objects of the type at the layer)")
+	cmd.Flags().String("filter", "", "Filter condition in SCIM filter format for selecting the objects to copy, when --object-id is not specified")
+	cmd.MarkFlagsMutuallyExclusive("object-id", "filter")
+	cmd.Flags().Var(&ltFlag, "layer-type", fmt.Sprintf("Layer type of the objects.  Valid values: %q, %q, %q, %q, %q", solution, account, globalUser, tenant, localUser))
+	_ = cmd.MarkFlagRequired("layer-type")
+	_ = cmd.RegisterFlagCompletionFunc("layer-type", layerTypeCompletionFunc)
+	cmd.Flags().String("layer-id", "", "Layer ID of the objects in the source tenant")
+	cmd.Flags().String("to-layer-id", "", "Layer ID for the objects in the target tenant")
+	cmd.Flags().Bool("overwrite", false, "Replace objects that already exist in the target tenant")
+
+	return cmd
+}
+
+func copyObjects(cmd *cobra.Command, ltFlag layerType) error {
+	fqtn, _ := cmd.Flags().GetString("type")
+	objID, _ := cmd.Flags().GetString("object-id")
+	filter, _ := cmd.Flags().GetString("filter")
+	overwrite, _ := cmd.Flags().GetBool("overwrite")
+	layerType := ltFlag.String()
+
+	// determine the profiles & layers
+	fromProfile, _ := cmd.Flags().GetString("profile-from")
+	toProfile, _ := cmd.Flags().GetString("profile-to")
+	if toProfile == "" {
+		toProfile = config.GetCurrentProfileName()
+	}
+	if fromProfile == toProfile {
+		return clierror.New(clierror.Usage, "the source and target profiles must be different, found %q for both", fromProfile)
+	}
+	fromLayerID, err := copyLayerID(cmd, "layer-id", fromProfile, layerType, fqtn)
+	if err != nil {
+		return err
+	}
+	toLayerID, err := copyLayerID(cmd, "to-layer-id", toProfile, layerType, fqtn)
+	if err != nil {
+		return err
+	}
+	fromOptions := &api.Options{Profile: fromProfile, Headers: map[string]string{"layer-type": layerType, "layer-id": fromLayerID}}
+	toOptions := &api.Options{Profile: toProfile, Headers: map[string]string{"layer-type": layerType, "layer-id": toLayerID}}
+
+	// read the objects from the source tenant
+	objects := []map[string]any{}
+	if objID != "" {
+		var object map[string]any
+		if err := api.JSONGet(getObjectUrl(fqtn, objID), &object, fromOptions); err != nil {
+			return fmt.Errorf("failed to get knowledge object %q from profile %q: %w", objID, fromProfile, err)
+		}
+		objects = append(objects, object)
+	} else {
+		path := getObjectListUrl(fqtn)
+		if filter != "" {
+			path += "?filter=" + url.QueryEscape(filter)
+		}
+		err := api.ForEachItem(path, fromOptions, func(object map[string]any) error {
+			objects = append(objects, object)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get knowledge objects from profile %q: %w", fromProfile, err)
+		}
+	}
+	log.WithFields(log.Fields{"count": len(objects), "from": fromProfile, "to": toProfile}).Info("Copying knowledge objects")
+
+	// write them to the target tenant
+	results := []copyResult{}
+	failed := 0
+	for _, object := range objects {
+		result := copyObject(fqtn, object, toOptions, overwrite)
+		if result.Status == "failed" {
+			failed++
+		}
+		results = append(results, result)
+	}
+
+	if err := output.PrintCmdOutput(cmd, struct {
+		Items []copyResult `json:"items"`
+		Total int          `json:"total"`
+	}{results, len(results)}); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to copy %d of %d knowledge object(s)", failed, len(results))
+	}
+	return nil
+}
+
+// copyObject creates (or replaces) a knowledge object in the target tenant
+func copyObject(fqtn string, object map[string]any, options *api.Options, overwrite bool) copyResult {
+	id, _ := object["id"].(string)
+	data, ok := object["data"]
+	if !ok {
+		return copyResult{ID: id, Status: "failed", Message: "the object has no data"}
+	}
+
+	createOptions := *options
+	createOptions.ExpectedErrors = []int{http.StatusConflict}
+	var res any
+	err := api.JSONPost(getObjStoreObjectUrl()+"/"+fqtn, data, &res, &createOptions)
+	if err == nil {
+		return copyResult{ID: id, Status: "created"}
+	}
+
+	var statusErr *api.HttpStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusConflict {
+		return copyResult{ID: id, Status: "failed", Message: err.Error()}
+	}
+	if !overwrite || id == "" {
+		return copyResult{ID: id, Status: "failed", Message: "the object already exists in the target tenant; use --overwrite to replace it"}
+	}
+	if err := api.JSONPut(getObjectUrl(fqtn, id), data, &res, options); err != nil {
+		return copyResult{ID: id, Status: "failed", Message: err.Error()}
+	}
+	return copyResult{ID: id, Status: "replaced"}
+}
+
+// copyLayerID returns the layer ID from the flag, if specified, or the profile's default for the layer type
+func copyLayerID(cmd *cobra.Command, flag string, profile string, layerType string, fqtn string) (string, error) {
+	if layerID, _ := cmd.Flags().GetString(flag); layerID != "" {
+		return layerID, nil
+	}
+	cfg, err := config.GetContext(profile)
+	if err != nil {
+		return "", err
+	}
+	layerID := getProfileLayerID(cfg, layerType, fqtn)
+	if layerID == "" {
+		return "", clierror.New(clierror.Usage, "unable to determine the %v layer ID for profile %q; log in to it with \"fsoc login --profile %v\" or specify --%v", layerType, profile, profile, flag)
+	}
+	return layerID, nil
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/apex/log"
 	"github.com/spf13/cobra"
 
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
 	"github.com/cisco-open/fsoc/output"
 	"github.com/cisco-open/fsoc/platform/api"
 )
@@ -38,7 +39,7 @@ Example:
 `,
 
 	Args:             cobra.ExactArgs(0),
-	Run:              insertObject,
+	RunE:             insertObject,
 	TraverseChildren: true,
 }
 
@@ -64,13 +65,13 @@ func getCreateObjectCmd() *cobra.Command {
 
 }
 
-func insertObject(cmd *cobra.Command, args []string) {
+func insertObject(cmd *cobra.Command, args []string) error {
 	objType, _ := cmd.Flags().GetString("type")
 
 	objJsonFilePath, _ := cmd.Flags().GetString("object-file")
 	objectFile, err := os.Open(objJsonFilePath)
 	if err != nil {
-		log.Fatalf("Can't find the knowledge object definition file named %q", objJsonFilePath)
+		return fmt.Errorf("can't find the knowledge object definition file named %q", objJsonFilePath)
 	}
 	defer objectFile.Close()
 
@@ -78,7 +79,7 @@ func insertObject(cmd *cobra.Command, args []string) {
 	var objectStruct map[string]interface{}
 	err = json.Unmarshal(objectBytes, &objectStruct)
 	if err != nil {
-		log.Fatalf("Failed to parse knowledge object data from file %q: %v. Make sure the knowledge object definition has all the required field and is valid according to the type definition.", objJsonFilePath, err)
+		return fmt.Errorf("failed to parse knowledge object data from file %q: %w. Make sure the knowledge object definition has all the required field and is valid according to the type definition.", objJsonFilePath, err)
 	}
 
 	layerType, _ := cmd.Flags().GetString("layer-type")
@@ -86,11 +87,11 @@ func insertObject(cmd *cobra.Command, args []string) {
 
 	if layerID == "" {
 		if !cmd.Flags().Changed("layer-id") {
-			log.Fatal("Unable to set layer-id flag from given context. Please specify a unique layer-id value with the --layer-id flag")
+			return fmt.Errorf("unable to set layer-id flag from given context. Please specify a unique layer-id value with the --layer-id flag")
 		}
 		layerID, err = cmd.Flags().GetString("layer-id")
 		if err != nil {
-			log.Fatalf("error trying to get %q flag value: %w", "layer-id", err)
+			return fmt.Errorf("error trying to get %q flag value: %w", "layer-id", err)
 		}
 	}
 
@@ -103,10 +104,11 @@ func insertObject(cmd *cobra.Command, args []string) {
 	// objJsonStr, err := json.Marshal(objectStruct)
 	err = api.JSONPost(getObjStoreObjectUrl()+"/"+objType, objectStruct, &res, &api.Options{Headers: headers})
 	if err != nil {
-		log.Fatalf("Failed to create knowledge object: %v", err)
+		return fmt.Errorf("failed to create knowledge object: %w", err)
 	} else {
 		log.Infof("Successfully created a knowledge object of type: %q", objType)
 	}
+	return nil
 }
 
 func getObjStoreObjectUrl() string {
@@ -123,7 +125,7 @@ Example:
   fsoc knowledge create-patch --type<fully-qualified-typename> --object-file=<fully-qualified-path> --target-layer-type=<valid-layer-type> --target-object-id=<valid-object-id>`,
 
 	Args:             cobra.ExactArgs(0),
-	Run:              insertPatchObject,
+	RunE:             insertPatchObject,
 	TraverseChildren: true,
 }
 
@@ -176,7 +178,7 @@ func getCreatePatchObjectCmd() *cobra.Command {
 	return objStoreInsertPatchedObjectCmd
 }
 
-func insertPatchObject(cmd *cobra.Command, args []string) {
+func insertPatchObject(cmd *cobra.Command, args []string) error {
 	objType, _ := cmd.Flags().GetString("type")
 	parentObjId, _ := cmd.Flags().GetString("target-object-id")
 
@@ -184,15 +186,13 @@ func insertPatchObject(cmd *cobra.Command, args []string) {
 	useJsonMergePatch, _ := cmd.Flags().GetBool("json-merge-patch")
 
 	if useJsonPatch && useJsonMergePatch {
-		log.Fatalf("Both --json-patch and --json-merge-patch specified, please only specify one of them")
-		return
+		return clierror.New(clierror.Usage, "both --json-patch and --json-merge-patch specified, please only specify one of them")
 	}
 
 	objJsonFilePath, _ := cmd.Flags().GetString("object-file")
 	objectFile, err := os.Open(objJsonFilePath)
 	if err != nil {
-		log.Fatalf("Can't find the knowledge object definition file %q", objJsonFilePath)
-		return
+		return fmt.Errorf("can't find the knowledge object definition file %q", objJsonFilePath)
 	}
 	defer objectFile.Close()
 
@@ -223,9 +223,11 @@ func insertPatchObject(cmd *cobra.Command, args []string) {
 	var res any
 	err = api.JSONPatch(getObjStoreObjectUrl()+"/"+objType+"/"+parentObjId, objectBytes, &res, &api.Options{Headers: headers})
 	if err != nil {
-		log.Fatalf("Failed to create knowledge object: %v", err)
-		return
+		return fmt.Errorf("failed to create knowledge object: %w", err)
 	} else {
-		output.PrintCmdOutput(cmd, fmt.Sprintf("Successfully created a patched knowledge object of type: %q the %s layer.\n", objType, layerType))
+		if err := output.PrintCmdOutput(cmd, fmt.Sprintf("Successfully created a patched knowledge object of type: %q the %s layer.\n", objType, layerType)); err != nil {
+			return err
+		}
 	}
+	return nil
 }
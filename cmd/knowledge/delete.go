@@ -17,7 +17,6 @@ package knowledge
 import (
 	"fmt"
 
-	"github.com/apex/log"
 	"github.com/spf13/cobra"
 
 	"github.com/cisco-open/fsoc/output"
@@ -38,7 +37,7 @@ Usage:
 `,
 
 	Args:             cobra.ExactArgs(0),
-	Run:              deleteObject,
+	RunE:             deleteObject,
 	TraverseChildren: true,
 }
 
@@ -65,7 +64,7 @@ func getDeleteObjectCmd() *cobra.Command {
 
 }
 
-func deleteObject(cmd *cobra.Command, args []string) {
+func deleteObject(cmd *cobra.Command, args []string) error {
 	var err error
 
 	objType, _ := cmd.Flags().GetString("type")
@@ -75,11 +74,11 @@ func deleteObject(cmd *cobra.Command, args []string) {
 
 	if layerID == "" {
 		if !cmd.Flags().Changed("layer-id") {
-			log.Fatalf("Unable to set layer-id flag from given context. Please specify a unique layer-id value with the --layer-id flag")
+			return fmt.Errorf("unable to set layer-id flag from given context. Please specify a unique layer-id value with the --layer-id flag")
 		}
 		layerID, err = cmd.Flags().GetString("layer-id")
 		if err != nil {
-			log.Fatalf("error trying to get %q flag value: %w", "layer-id", err)
+			return fmt.Errorf("error trying to get %q flag value: %w", "layer-id", err)
 		}
 	}
 
@@ -96,7 +95,8 @@ func deleteObject(cmd *cobra.Command, args []string) {
 	output.PrintCmdStatus(cmd, (fmt.Sprintf("Deleting  knowledge object %q of type %q\n", objId, objType)))
 	err = api.JSONDelete(objectUrl, &res, &api.Options{Headers: headers})
 	if err != nil {
-		log.Fatalf("Failed to delete knowledge object: %v", err)
+		return fmt.Errorf("failed to delete knowledge object: %w", err)
 	}
 	output.PrintCmdStatus(cmd, "knowledge object was successfully deleted.\n")
+	return nil
 }
@@ -36,7 +36,7 @@ func editObjectCmd() *cobra.Command {
 	environment variable, or fall back to 'vi' for Linux/MacOS or 'notepad' for Windows.`,
 
 		Args:             cobra.NoArgs,
-		Run:              editObject,
+		RunE:             editObject,
 		TraverseChildren: true,
 	}
 
@@ -62,12 +62,12 @@ func editObjectCmd() *cobra.Command {
 
 }
 
-func editObject(cmd *cobra.Command, args []string) {
+func editObject(cmd *cobra.Command, args []string) error {
 	log.Info("Fetching object...")
 
 	fqtn, objID, layerID, layerType, err := parseObjectInfo(cmd)
 	if err != nil {
-		log.Fatal(err.Error())
+		return err
 	}
 
 	headers := map[string]string{
@@ -80,14 +80,14 @@ func editObject(cmd *cobra.Command, args []string) {
 	var res KSObject
 	err = api.JSONGet(url, &res, httpOptions)
 	if err != nil {
-		log.Fatalf("Failed to fetch object: %v", err)
+		return fmt.Errorf("failed to fetch object: %w", err)
 	}
 
 	log.Infof("Object data %vn", res.Data)
 
 	etagHeader := httpOptions.ResponseHeaders["Etag"]
 	if len(etagHeader) != 1 || etagHeader[0] == "" {
-		log.Fatalf("etag not found in response headers")
+		return fmt.Errorf("etag not found in response headers")
 	}
 	etag := etagHeader[0]
 	log.Infof("Object Etag: %s", etag)
@@ -97,19 +97,19 @@ func editObject(cmd *cobra.Command, args []string) {
 	encoder.SetIndent("", "  ")
 	err = encoder.Encode(res.Data)
 	if err != nil {
-		log.Fatalf("Failed to JSON encode object data before editting: %v", err)
+		return fmt.Errorf("failed to JSON encode object data before editting: %w", err)
 	}
 
 	edited, err := editor.Run(buf)
 	if err != nil {
-		log.Fatalf("Failed to run editor: %v", err)
+		return fmt.Errorf("failed to run editor: %w", err)
 	}
 
 	// Parse edited to make sure it is valid json
 	var editedData map[string]interface{}
 	err = json.Unmarshal(edited, &editedData)
 	if err != nil {
-		log.Fatalf("Edited data is not valid json: %v", err)
+		return fmt.Errorf("edited data is not valid json: %w", err)
 	}
 
 	// Send update to server, with etag
@@ -121,11 +121,11 @@ func editObject(cmd *cobra.Command, args []string) {
 	var resPut any
 	err = api.JSONPut(url, editedData, &resPut, &api.Options{Headers: headersPut})
 	if err != nil {
-		log.Fatalf("Knowledge object update failed: %v", err)
+		return fmt.Errorf("knowledge object update failed: %w", err)
 	}
 
 	// TODO: If there is an error, open the editor again with the error message
 
 	log.Infof("Successfully updated object, got output %v\n", resPut)
-
+	return nil
 }
@@ -116,8 +116,7 @@ func getType(cmd *cobra.Command, args []string) error {
 	}
 
 	// execute command and print result
-	cmdkit.FetchAndPrint(cmd, getTypeUrl(fqtn), nil)
-	return nil
+	return cmdkit.FetchAndPrint(cmd, getTypeUrl(fqtn), nil)
 }
 
 func getObject(cmd *cobra.Command, args []string, ltFlag layerType) error {
@@ -199,8 +198,7 @@ func getObject(cmd *cobra.Command, args []string, ltFlag layerType) error {
 		objStoreUrl = getObjectListUrl(fqtn)
 	}
 
-	cmdkit.FetchAndPrint(cmd, objStoreUrl, &cmdkit.FetchAndPrintOptions{Headers: headers, IsCollection: isCollection})
-	return nil
+	return cmdkit.FetchAndPrint(cmd, objStoreUrl, &cmdkit.FetchAndPrintOptions{Headers: headers, IsCollection: isCollection})
 }
 
 func getTypeUrl(fqtn string) string {
@@ -59,7 +59,10 @@ To see the exact API call that fsoc makes, use the --curl flag with the desired
   fsoc knowledge create --type=<fully-qualified-typename> --object-file=<fully-qualified-path> --layer-type=SOLUTION|ACCOUNT|GLOBALUSER|TENANT|LOCALUSER [--layer-id=<layer-id>]
 
   # Delete object
-  fsoc knowledge delete --type=<fully-qualified-typename> --object-id=<object-id> --layer-type=SOLUTION|ACCOUNT|GLOBALUSER|TENANT|LOCALUSER [--layer-id=<layer-id>]`,
+  fsoc knowledge delete --type=<fully-qualified-typename> --object-id=<object-id> --layer-type=SOLUTION|ACCOUNT|GLOBALUSER|TENANT|LOCALUSER [--layer-id=<layer-id>]
+
+  # Copy objects from the tenant of another profile
+  fsoc knowledge copy --profile-from=<profile> --type=<fully-qualified-typename> --layer-type=TENANT [--object-id=<object-id>]`,
 		TraverseChildren: true,
 	}
 
@@ -70,6 +73,7 @@ To see the exact API call that fsoc makes, use the --curl flag with the desired
 	knowledgeStoreCmd.AddCommand(getDeleteObjectCmd())
 	knowledgeStoreCmd.AddCommand(getCreatePatchObjectCmd())
 	knowledgeStoreCmd.AddCommand(editObjectCmd())
+	knowledgeStoreCmd.AddCommand(newCopyObjectCmd())
 
 	return knowledgeStoreCmd
 }
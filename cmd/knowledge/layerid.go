@@ -21,7 +21,11 @@ import (
 )
 
 func getCorrectLayerID(layerType string, fqtn string) string {
-	cfg := config.GetCurrentContext()
+	return getProfileLayerID(config.GetCurrentContext(), layerType, fqtn)
+}
+
+// getProfileLayerID determines the default layer ID for the layer type in the given profile
+func getProfileLayerID(cfg *config.Context, layerType string, fqtn string) string {
 	var layerID string
 
 	if layerType == "TENANT" {
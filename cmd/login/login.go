@@ -15,9 +15,10 @@
 package login
 
 import (
-	"github.com/apex/log"
+	"fmt"
 	"github.com/spf13/cobra"
 
+	"github.com/cisco-open/fsoc/config"
 	"github.com/cisco-open/fsoc/output"
 	"github.com/cisco-open/fsoc/platform/api"
 )
@@ -29,9 +30,14 @@ var loginCmd = &cobra.Command{
 	Long: `This command logs in the principal specified in the profile, obtaining a temporary JWT token
 that will be automatically used by other commands.
 
+On machines without a browser (e.g., over SSH, in containers or on jump hosts), use the --device
+flag (or the profile's oauth-flow=device setting) to log in to an "oauth" profile using a URL and a code
+that can be entered on any device with a browser.
+
 Usage:
-	fsoc login`,
-	Run:              login,
+	fsoc login
+	fsoc login --device`,
+	RunE:             login,
 	TraverseChildren: true,
 }
 
@@ -48,12 +54,18 @@ func init() {
 }
 
 func NewSubCmd() *cobra.Command {
+	loginCmd.Flags().Bool("device", false, "Log in using a code entered on another device (oauth profiles only), for machines without a browser")
 	return loginCmd
 }
 
-func login(cmd *cobra.Command, args []string) {
-	if err := api.Login(); err != nil {
-		log.Fatalf("Login failed: %v", err)
+func login(cmd *cobra.Command, args []string) error {
+	var opts api.LoginOptions
+	if device, _ := cmd.Flags().GetBool("device"); device {
+		opts.OAuthFlow = config.OAuthFlowDevice
+	}
+	if err := api.LoginWithOptions(opts); err != nil {
+		return fmt.Errorf("login failed: %w", err)
 	}
 	output.PrintCmdStatus(cmd, "Login completed successfully.\n")
+	return nil
 }
@@ -0,0 +1,123 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logout
+
+import (
+	"fmt"
+	"path"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
+	"github.com/cisco-open/fsoc/config"
+	"github.com/cisco-open/fsoc/output"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// logoutCmd represents the logout command
+var logoutCmd = &cobra.Command{
+	Use:   "logout [PROFILE_PATTERN...]",
+	Short: "Log out of profiles, revoking and clearing their tokens",
+	Long: `This command ends the session of the current profile, the specified profiles or all profiles.
+For profiles using the "oauth" authentication method, the refresh token is revoked by the platform,
+which also invalidates the access tokens issued with it. For all profiles, the access and refresh tokens
+are cleared from the fsoc config, so that the next command logs in again.
+
+Profiles can be specified by name or by a glob pattern (e.g., "prod-*"). The command reports, for each
+profile, whether the tokens were revoked by the platform or only cleared locally.`,
+	Example: `  fsoc logout
+  fsoc logout ci 'prod-*'
+  fsoc logout --all`,
+	RunE:              logout,
+	ValidArgsFunction: validArgsAutocomplete,
+	Annotations: map[string]string{
+		output.TableFieldsAnnotation: "profile:.profile, status:.status, message:(.message // \"\")",
+	},
+}
+
+func NewSubCmd() *cobra.Command {
+	logoutCmd.Flags().Bool("all", false, "Log out of all profiles")
+	return logoutCmd
+}
+
+func logout(cmd *cobra.Command, args []string) error {
+	profiles, err := selectProfiles(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	results := []*api.LogoutResult{}
+	var failed []string
+	for _, profile := range profiles {
+		result, err := api.Logout(profile)
+		if err != nil {
+			failed = append(failed, profile)
+			result = &api.LogoutResult{Profile: profile, Status: "failed", Message: err.Error()}
+		}
+		results = append(results, result)
+	}
+
+	if err := output.PrintCmdOutput(cmd, struct {
+		Items []*api.LogoutResult `json:"items"`
+		Total int                 `json:"total"`
+	}{results, len(results)}); err != nil {
+		return err
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to log out of %d profile(s): %v", len(failed), failed)
+	}
+	return nil
+}
+
+// selectProfiles determines the profiles to log out of: all, those matching the patterns or the current one
+func selectProfiles(cmd *cobra.Command, args []string) ([]string, error) {
+	all, _ := cmd.Flags().GetBool("all")
+	if all {
+		if len(args) > 0 {
+			return nil, clierror.New(clierror.Usage, "profiles cannot be specified together with --all")
+		}
+		return config.ListAllContexts(), nil
+	}
+	if len(args) == 0 {
+		return []string{config.GetCurrentProfileName()}, nil
+	}
+
+	allProfiles := config.ListAllContexts()
+	profiles := []string{}
+	for _, pattern := range args {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, clierror.New(clierror.Usage, "invalid profile pattern %q: %v", pattern, err)
+		}
+		matched := false
+		for _, profile := range allProfiles {
+			if ok, _ := path.Match(pattern, profile); ok {
+				matched = true
+				if !slices.Contains(profiles, profile) {
+					profiles = append(profiles, profile)
+				}
+			}
+		}
+		if !matched {
+			return nil, clierror.New(clierror.NotFound, "no profile matches %q", pattern)
+		}
+	}
+	return profiles, nil
+}
+
+func validArgsAutocomplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return config.ListContexts(toComplete), cobra.ShellCompDirectiveNoFileComp
+}
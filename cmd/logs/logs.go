@@ -15,18 +15,17 @@
 package logs
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"os/signal"
 	"regexp"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/apex/log"
 	"github.com/spf13/cobra"
 
 	"github.com/cisco-open/fsoc/cmd/uql"
+	"github.com/cisco-open/fsoc/output"
 )
 
 var (
@@ -63,6 +62,8 @@ Specifying message filters
 
 Formatting output
 - to change the output format specify the --format/-t flag
+- alternatively, use the --output/-o flag to display the logs as a table, csv, tsv, jsonl, json, yaml or with
+  a template applied to all logs (fields: timestamp, severity, message, entityId, spanId, traceId)
 - available fields: Timestamp, Severity, Message, EntityId, SpanId, TraceId
 - to color a field in your template write the name of a color before the field e.g. {{yellow Timestamp}}
 - available colors: red, green, yellow, blue, purple, cyan, gray, white`,
@@ -81,6 +82,9 @@ Coloring output
 		Args:             cobra.MaximumNArgs(1),
 		RunE:             fetchLogs,
 		TraverseChildren: true,
+		Annotations: map[string]string{
+			output.TableFieldsAnnotation: "timestamp: .timestamp, severity: .severity, entityId: .entityId, message: .message",
+		},
 	}
 	// followSince controls from how far in the past to start following logs
 	followSince = "-2m"
@@ -139,40 +143,75 @@ func fetchLogs(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// display the logs using the row template or, if specified, the output format
+	printFn := func(resp *uql.Response) error {
+		printLogs(resp, formatter, cmd)
+		return nil
+	}
+	if cmd.Flags().Changed("output") {
+		printFn, err = outputFormatPrinter(cmd, follow)
+		if err != nil {
+			return err
+		}
+	}
+
 	query = prettifyUqlQuery(query)
 
 	resp, err := queryLogs(query)
 	if err != nil {
-		log.Fatal(err.Error())
+		return err
 	}
 
-	printLogs(resp, formatter, cmd)
+	if err := printFn(resp); err != nil {
+		return err
+	}
 
 	if follow {
-		return followLogs(resp, formatter, variables.Count, cmd)
+		return followLogs(cmd.Context(), resp, printFn, variables.Count)
 	}
 
 	return nil
 }
 
+// outputFormatPrinter returns a function that displays the logs in the format selected with the --output flag
+// (instead of the row template). Only formats that can be streamed can be used when following the logs.
+func outputFormatPrinter(cmd *cobra.Command, follow bool) (func(*uql.Response) error, error) {
+	if printer, ok := output.NewStreamPrinter(cmd); ok {
+		return func(resp *uql.Response) error {
+			return printer.PrintItems(logItems(resp))
+		}, nil
+	}
+	if follow {
+		if sortBy, _ := cmd.Flags().GetString("sort-by"); sortBy != "" {
+			return nil, fmt.Errorf("--sort-by cannot be used with --follow, since the logs are displayed as they arrive")
+		}
+		format, _ := cmd.Flags().GetString("output")
+		return nil, fmt.Errorf("the %q output format cannot be used with --follow; use table, csv, tsv or jsonl instead", format)
+	}
+	return func(resp *uql.Response) error {
+		items := logItems(resp)
+		return output.PrintCmdOutput(cmd, map[string]any{"items": items, "total": len(items)})
+	}, nil
+}
+
 type eventResult struct {
 	data *uql.DataSet
 	err  error
 }
 
-func followLogs(initialResponse *uql.Response, formatter rowFormatter, limit int, p printer) error {
-	interrupt := make(chan os.Signal, 1)
-	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+// followLogs keeps fetching and printing new log entries until the context is done
+// (e.g., interrupted with Ctrl-C or timed out)
+func followLogs(ctx context.Context, initialResponse *uql.Response, printFn func(*uql.Response) error, limit int) error {
 	eventResults := make(chan eventResult, 1)
 	eventResults <- eventResult{data: extractEventDataSet(initialResponse)}
 
 	for {
 		select {
-		case <-interrupt:
+		case <-ctx.Done():
 			return nil
 		case followResult := <-eventResults:
 			if followResult.err != nil {
-				log.Fatal(followResult.err.Error())
+				return followResult.err
 			}
 
 			go func() {
@@ -182,7 +221,10 @@ func followLogs(initialResponse *uql.Response, formatter rowFormatter, limit int
 					return
 				}
 
-				printLogs(resp, formatter, p)
+				if err := printFn(resp); err != nil {
+					eventResults <- eventResult{err: err}
+					return
+				}
 
 				eventsDataSet := extractEventDataSet(resp)
 
@@ -192,8 +234,11 @@ func followLogs(initialResponse *uql.Response, formatter rowFormatter, limit int
 				} else {
 					// wait a while since there probably is not enough data
 					go func() {
-						time.Sleep(followTimer)
-						eventResults <- eventResult{data: eventsDataSet}
+						select {
+						case <-time.After(followTimer):
+							eventResults <- eventResult{data: eventsDataSet}
+						case <-ctx.Done():
+						}
 					}()
 				}
 			}()
@@ -235,6 +280,17 @@ func printLogs(resp *uql.Response, formatter rowFormatter, p printer) {
 	}
 }
 
+// logItems returns the log records in the response, oldest first, in the form used by the output package
+func logItems(resp *uql.Response) []any {
+	rawLogRecords := extractEventDataSet(resp).Values()
+
+	items := make([]any, 0, len(rawLogRecords))
+	for i := len(rawLogRecords) - 1; i >= 0; i-- {
+		items = append(items, makeRow(rawLogRecords[i]).item())
+	}
+	return items
+}
+
 func extractEventDataSet(resp *uql.Response) *uql.DataSet {
 	return resp.Main().Values()[0][0].(*uql.DataSet)
 }
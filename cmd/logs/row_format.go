@@ -49,8 +49,8 @@ type printer interface {
 	Printf(format string, i ...any)
 }
 
-func printRow(rowValues []any, formatter rowFormatter, p printer) {
-	row := &row{
+func makeRow(rowValues []any) *row {
+	return &row{
 		Timestamp: (rowValues[0]).(time.Time).Format(time.RFC3339),
 		Message:   rowValues[1],
 		Severity:  rowValues[2],
@@ -58,6 +58,22 @@ func printRow(rowValues []any, formatter rowFormatter, p printer) {
 		SpanId:    rowValues[4],
 		TraceId:   rowValues[5],
 	}
+}
+
+// item returns the row in the generic form used by the output package (see the --output flag)
+func (r *row) item() map[string]any {
+	return map[string]any{
+		"timestamp": r.Timestamp,
+		"severity":  r.Severity,
+		"message":   r.Message,
+		"entityId":  r.EntityId,
+		"spanId":    r.SpanId,
+		"traceId":   r.TraceId,
+	}
+}
+
+func printRow(rowValues []any, formatter rowFormatter, p printer) {
+	row := makeRow(rowValues)
 	formattedRow, err := formatter(row)
 	if err != nil {
 		p.Printf("cannot format: %s\n", row)
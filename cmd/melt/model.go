@@ -6,7 +6,6 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/apex/log"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"golang.org/x/exp/maps"
@@ -23,7 +22,7 @@ var meltModelCmd = &cobra.Command{
 	Short:            "Generates a fsoc telemetry data model .yaml file based on your solution domain model",
 	Long:             `This command converts your fmm domain models as a fsoc telemetry data model .yaml file so you can generate mock telemetry data for your solutioin.`,
 	TraverseChildren: true,
-	Run:              meltModel,
+	RunE:             meltModel,
 }
 
 func init() {
@@ -34,62 +33,88 @@ func init() {
 	meltCmd.AddCommand(meltModelCmd)
 }
 
-func meltModel(cmd *cobra.Command, args []string) {
+func meltModel(cmd *cobra.Command, args []string) error {
 	manifest, err := sol.GetManifest(".")
 	if err != nil {
-		log.Fatalf("Failed to get manifest: %v", err)
+		return fmt.Errorf("failed to get manifest: %w", err)
 	}
 	if manifest.HasPseudoIsolation() {
 		// determine tag
-		tag, envFile := sol.DetermineTagEnvFile(cmd, ".") // --tag, --stable, --env-file, FSOC_SOLUTION_TAG env var, .tag file
+		tag, envFile, err := sol.DetermineTagEnvFile(cmd, ".") // --tag, --stable, --env-file, FSOC_SOLUTION_TAG env var, .tag file
+		if err != nil {
+			return err
+		}
 		if tag == "" && envFile == "" {
-			log.Fatal("A tag must be specified for modeling in pseudo-isolated solutions")
+			return fmt.Errorf("a tag must be specified for modeling in pseudo-isolated solutions")
 		}
 		if tag != "" {
 			if !sol.IsValidSolutionTag(tag) {
-				log.Fatalf("Invalid tag %q", tag)
+				return fmt.Errorf("invalid tag %q", tag)
 			}
 		}
 		envVars, err := sol.LoadEnvVars(cmd, tag, envFile) // the tag now remains in the envVars (whether read from file or synthesized)
 		if err != nil {
-			log.Fatalf("Failed to define isolation environment: %v", err)
+			return fmt.Errorf("failed to define isolation environment: %w", err)
 		}
 
 		currentDirectory, err := filepath.Abs(".")
 		if err != nil {
-			log.Fatalf("Error getting current directory: %v", err)
+			return fmt.Errorf("error getting current directory: %w", err)
 		}
 		fileSystemRoot := afero.NewBasePathFs(afero.NewOsFs(), currentDirectory)
 
-		isolateNamespace := fmt.Sprintf("%s%s", strings.Split(manifest.Name, "$")[0], sol.GetPseudoIsolationTag(envVars))
+		tag, err = sol.GetPseudoIsolationTag(envVars)
+		if err != nil {
+			return err
+		}
+		isolateNamespace := fmt.Sprintf("%s%s", strings.Split(manifest.Name, "$")[0], tag)
 		fileName := fmt.Sprintf("%s-%s-melt.yaml", isolateNamespace, manifest.SolutionVersion)
 
-		fsocData := getFsocDataModel(cmd, manifest, isolateNamespace)
+		fsocData, err := getFsocDataModel(cmd, manifest, isolateNamespace)
+		if err != nil {
+			return err
+		}
 		output.PrintCmdStatus(cmd, fmt.Sprintf("Generating %s\n", fileName))
-		writeDataFile(fsocData, fileName)
+		if err := writeDataFile(fsocData, fileName); err != nil {
+			return err
+		}
 
 		err = sol.ReplaceStringInFile(fileSystemRoot, fileName, "${sys.solutionId}", isolateNamespace)
 		if err != nil {
-			log.Fatalf("Error isolating melt model file: %v", err)
+			return fmt.Errorf("error isolating melt model file: %w", err)
 		}
 	} else {
 		fileName := fmt.Sprintf("%s-%s-melt.yaml", manifest.Name, manifest.SolutionVersion)
-		fsocData := getFsocDataModel(cmd, manifest, "")
+		fsocData, err := getFsocDataModel(cmd, manifest, "")
+		if err != nil {
+			return err
+		}
 		output.PrintCmdStatus(cmd, fmt.Sprintf("Generating %s\n", fileName))
-		writeDataFile(fsocData, fileName)
+		if err := writeDataFile(fsocData, fileName); err != nil {
+			return err
+		}
 	}
-
+	return nil
 }
 
-func getFsocDataModel(cmd *cobra.Command, manifest *sol.Manifest, isolationNamespace string) *melt.FsocData {
+func getFsocDataModel(cmd *cobra.Command, manifest *sol.Manifest, isolationNamespace string) (*melt.FsocData, error) {
 	fsocData := &melt.FsocData{}
-	fmmEntities := manifest.GetFmmEntities()
+	fmmEntities, err := manifest.GetFmmEntities()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the solution's entity types: %w", err)
+	}
 	output.PrintCmdStatus(cmd, fmt.Sprintf("Adding %v entities to the fsoc data model\n", len(fmmEntities)))
 
-	fmmMetrics := manifest.GetFmmMetrics()
+	fmmMetrics, err := manifest.GetFmmMetrics()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the solution's metric types: %w", err)
+	}
 	output.PrintCmdStatus(cmd, fmt.Sprintf("Adding %v metrics to the fsoc data model\n", len(fmmMetrics)))
 
-	fmmEvents := manifest.GetFmmEvents()
+	fmmEvents, err := manifest.GetFmmEvents()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the solution's event types: %w", err)
+	}
 	output.PrintCmdStatus(cmd, fmt.Sprintf("Adding %v events to the fsoc data model\n", len(fmmEvents)))
 
 	if isolationNamespace != "" {
@@ -129,7 +154,7 @@ func getFsocDataModel(cmd *cobra.Command, manifest *sol.Manifest, isolationNames
 	fsocEntities := GetFsocEntities(fmmEntities, fsocMetrics, fsocEvents)
 	fsocData.Melt = fsocEntities
 
-	return fsocData
+	return fsocData, nil
 }
 
 func GetIsolatedRefs(fmmTypeRefs []string, manifest *sol.Manifest, isolationNamespace string) []string {
@@ -227,17 +252,19 @@ func GetFsocEntities(fmmEntities []*sol.FmmEntity, fsocMetrics []*melt.Metric, f
 	return fsocEntities
 }
 
-func writeDataFile(fsoData *melt.FsocData, fileName string) {
+func writeDataFile(fsoData *melt.FsocData, fileName string) error {
 	fsoDataYamlFile, err := os.Create(fileName)
 	if err != nil {
-		log.Fatalf("Failed to create FsoData yaml file %q: %v", fileName, err)
+		return fmt.Errorf("failed to create FsoData yaml file %q: %w", fileName, err)
 	}
 	defer fsoDataYamlFile.Close()
 
 	svcJson, _ := yaml.Marshal(fsoData)
 
-	_, _ = fsoDataYamlFile.WriteString(string(svcJson))
-	fsoDataYamlFile.Close()
+	if _, err := fsoDataYamlFile.WriteString(string(svcJson)); err != nil {
+		return fmt.Errorf("failed to write FsoData yaml file %q: %w", fileName, err)
+	}
+	return nil
 }
 
 func getDefaultValue(td *sol.FmmAttributeTypeDef) interface{} {
@@ -13,6 +13,7 @@ import (
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
 
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
 	"github.com/cisco-open/fsoc/output"
 	"github.com/cisco-open/fsoc/platform/melt"
 )
@@ -65,11 +66,10 @@ func meltSendWithUsageCheck(cmd *cobra.Command, args []string) error {
 	}
 
 	// process command
-	meltSend(cmd, args)
-	return nil
+	return meltSend(cmd, args)
 }
 
-func meltSend(cmd *cobra.Command, args []string) {
+func meltSend(cmd *cobra.Command, args []string) error {
 	// Make this tolerate empty arg list, in which case it should use stdin
 	var dataFileName string
 	if len(args) > 0 {
@@ -77,17 +77,16 @@ func meltSend(cmd *cobra.Command, args []string) {
 	} else {
 		output.PrintCmdStatus(cmd, "Reading MELT data from STDIN\n")
 	}
-	sendDataFromFile(cmd, dataFileName)
+	return sendDataFromFile(cmd, dataFileName)
 }
 
-func sendDataFromFile(cmd *cobra.Command, dataFileName string) {
+func sendDataFromFile(cmd *cobra.Command, dataFileName string) error {
 	fsoData, err := loadDataFile(dataFileName)
 	if err != nil {
-		log.Fatalf("Can't open data file %q: %v", dataFileName, err)
+		return err
 	}
 	if fsoData == nil {
-		log.Fatalf("Failed to load data from file %q: empty file", dataFileName)
-		panic("unreachable") // unreachable, keep glanci-lint happy for using fsoData below
+		return clierror.New(clierror.Validation, "failed to load data from file %q: empty file", dataFileName)
 	}
 
 	for _, entity := range fsoData.Melt {
@@ -192,14 +191,14 @@ func sendDataFromFile(cmd *cobra.Command, dataFileName string) {
 		}
 	}
 
-	exportMeltStraight(cmd, fsoData)
+	return exportMeltStraight(cmd, fsoData)
 }
 
-func exportMeltStraight(cmd *cobra.Command, fsoData *melt.FsocData) {
-	exportMelt(cmd, *fsoData)
+func exportMeltStraight(cmd *cobra.Command, fsoData *melt.FsocData) error {
+	return exportMelt(cmd, *fsoData)
 }
 
-func exportMelt(cmd *cobra.Command, fsoData melt.FsocData) {
+func exportMelt(cmd *cobra.Command, fsoData melt.FsocData) error {
 	// construct the exporter with options from the command line
 	exp := &melt.Exporter{}
 	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
@@ -231,24 +230,25 @@ func exportMelt(cmd *cobra.Command, fsoData melt.FsocData) {
 	output.PrintCmdStatus(cmd, formatSection("Metrics", format))
 	err := exp.ExportMetrics(fsoData.Melt)
 	if err != nil {
-		log.Fatalf("Error exporting metrics: %s", err)
+		return fmt.Errorf("error exporting metrics: %w", err)
 	}
 
 	output.PrintCmdStatus(cmd, formatSection("Logs", format))
 	err = exp.ExportLogs(fsoData.Melt)
 	if err != nil {
-		log.Fatalf("Error exporting logs: %s", err)
+		return fmt.Errorf("error exporting logs: %w", err)
 	}
 
 	output.PrintCmdStatus(cmd, formatSection("Spans", format))
 	err = exp.ExportSpans(fsoData.Melt)
 	if err != nil {
-		log.Fatalf("Error exporting spans: %s", err)
+		return fmt.Errorf("error exporting spans: %w", err)
 	}
 
 	if !dump {
 		output.PrintCmdStatus(cmd, "\nMELT data sent (see log for traceresponse ID)\n")
 	}
+	return nil
 }
 
 func loadDataFile(fileName string) (*melt.FsocData, error) {
@@ -261,19 +261,19 @@ func loadDataFile(fileName string) (*melt.FsocData, error) {
 		var err error
 		dataFile, err = os.Open(fileName)
 		if err != nil {
-			log.Fatalf("Can't open the file named %q: %v", fileName, err)
+			return nil, fmt.Errorf("can't open data file %q: %w", fileName, err)
 		}
 		defer dataFile.Close()
 	}
 
 	dataBytes, err := io.ReadAll(dataFile)
 	if err != nil {
-		log.Fatalf("Can't read the file %q: %v", fileName, err)
+		return nil, fmt.Errorf("can't read data file %q: %w", fileName, err)
 	}
 
 	err = yaml.Unmarshal(dataBytes, &fsoData)
 	if err != nil {
-		log.Fatalf("Failed to parse fsoc telemetry model file: %v", err)
+		return nil, clierror.New(clierror.Validation, "failed to parse fsoc telemetry model file %q: %v", fileName, err)
 	}
 
 	return fsoData, nil
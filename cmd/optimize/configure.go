@@ -364,11 +364,13 @@ FROM entities(k8s:deployment)[attributes("k8s.cluster.name") = "{{.Cluster}}" &&
 				}
 			}
 
-			output.PrintCmdOutputCustom(cmd, blockers, &output.Table{
+			if err := output.PrintCmdOutputCustom(cmd, blockers, &output.Table{
 				Headers: headers,
 				Lines:   blockersOutput,
 				Detail:  true,
-			})
+			}); err != nil {
+				return err
+			}
 
 			// Ascertain overrideability of blockers
 			if flags.overrideSoftBlockers || flags.overrideHardBlockers {
@@ -319,10 +319,13 @@ func fetchAndPrintEvents(
 	eventsChan <- eventRows
 	if !flags.interactive {
 		// Do the output in the same go routine as the fetch, so that the output does not get messed up
-		output.PrintCmdOutputCustom(cmd, struct {
+		if err := output.PrintCmdOutputCustom(cmd, struct {
 			Items []EventsRow `json:"items"`
 			Total int         `json:"total"`
-		}{Items: eventRows, Total: len(eventRows)}, tableSettings)
+		}{Items: eventRows, Total: len(eventRows)}, tableSettings); err != nil {
+			errorChan <- err
+			return
+		}
 	}
 
 	// handle follow
@@ -349,10 +352,13 @@ func fetchAndPrintEvents(
 				eventsChan <- newRows
 				cursorExhausted = false
 				if !flags.interactive {
-					output.PrintCmdOutputCustom(cmd, struct {
+					if err := output.PrintCmdOutputCustom(cmd, struct {
 						Items []EventsRow `json:"items"`
 						Total int         `json:"total"`
-					}{Items: newRows, Total: len(newRows)}, tableSettings)
+					}{Items: newRows, Total: len(newRows)}, tableSettings); err != nil {
+						errorChan <- err
+						return
+					}
 				}
 
 			} else {
@@ -688,10 +694,12 @@ func listRecommendations(flags *recommendationsCmdFlags) func(*cobra.Command, []
 			recommendationRowsWithBlockers = append(recommendationRowsWithBlockers, recommendationWithBlockers)
 		}
 
-		output.PrintCmdOutput(cmd, struct {
+		if err := output.PrintCmdOutput(cmd, struct {
 			Items []recommendationRow `json:"items"`
 			Total int                 `json:"total"`
-		}{Items: recommendationRowsWithBlockers, Total: len(recommendationRowsWithBlockers)})
+		}{Items: recommendationRowsWithBlockers, Total: len(recommendationRowsWithBlockers)}); err != nil {
+			return err
+		}
 
 		return nil
 	}
@@ -173,10 +173,12 @@ func listReports(flags *reportFlags) func(cmd *cobra.Command, args []string) err
 			return nil
 		}
 
-		output.PrintCmdOutput(cmd, struct {
+		if err := output.PrintCmdOutput(cmd, struct {
 			Items []reportRow `json:"items"`
 			Total int         `json:"total"`
-		}{Items: reportRows, Total: len(reportRows)})
+		}{Items: reportRows, Total: len(reportRows)}); err != nil {
+			return err
+		}
 
 		return nil
 	}
@@ -70,14 +70,13 @@ func workloadReport(cmd *cobra.Command, args []string) error {
 	} else if byType == "name" {
 		workloadId, err = getWorkloadId(targetWorkload)
 		if err != nil {
-			log.Fatalf("error retrieving workload ID: %v", err)
+			return fmt.Errorf("error retrieving workload ID: %w", err)
 		}
 	}
 	encodedWorkloadId := base32.StdEncoding.EncodeToString([]byte(*workloadId))
 
 	// fetch data and display
-	cmdkit.FetchAndPrint(cmd, "/ignite/v1beta/reports/workloads/"+encodedWorkloadId, nil)
-	return nil
+	return cmdkit.FetchAndPrint(cmd, "/ignite/v1beta/reports/workloads/"+encodedWorkloadId, nil)
 }
 
 func getWorkloadId(workloadName string) (*string, error) {
@@ -30,13 +30,13 @@ Tenant lookup doesn't require valid authentication (auth=none) but any configure
 		Example: `  fsoc provisioning lookup MYTENANT.observe.appdynamics.com
   fsoc tep lookup MYTENANT.observe.appdynamics.com`,
 		Args:             cobra.ExactArgs(1),
-		Run:              lookup,
+		RunE:             lookup,
 		TraverseChildren: true,
 	}
 	return lookupCmd
 }
 
-func lookup(cmd *cobra.Command, args []string) {
+func lookup(cmd *cobra.Command, args []string) error {
 	vanityUrl := args[0]
-	cmdkit.FetchAndPrint(cmd, getTenantLookupUrl(vanityUrl), nil)
+	return cmdkit.FetchAndPrint(cmd, getTenantLookupUrl(vanityUrl), nil)
 }
@@ -15,6 +15,7 @@
 package proxy
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/apex/log"
@@ -41,7 +42,7 @@ fsoc does not interpret the flags as its own. Otherwise, the separator is option
 	Example: `  fsoc proxy -p 8000
   fsoc proxy -q -- curl -fsSL http://localhost:8080/knowledge-store/v1/objects/extensibility:solution/k8sprofiler
   fsoc proxy -p 8000 mytest.sh 8000`,
-	Run: proxy,
+	RunE: proxy,
 }
 
 func NewSubCmd() *cobra.Command {
@@ -50,7 +51,7 @@ func NewSubCmd() *cobra.Command {
 	return proxyCmd
 }
 
-func proxy(cmd *cobra.Command, args []string) {
+func proxy(cmd *cobra.Command, args []string) error {
 	// setup status printer, suppressing output if quiet flag is set
 	quiet, _ := cmd.Flags().GetBool("quiet")
 	statusPrinter := func(s string) {
@@ -62,7 +63,7 @@ func proxy(cmd *cobra.Command, args []string) {
 
 	// ensure profile is logged in before we start the proxy
 	if err := api.Login(); err != nil {
-		log.Fatalf("Login failed: %v", err)
+		return fmt.Errorf("login failed: %w", err)
 	}
 	statusPrinter("Login completed successfully")
 
@@ -70,11 +71,12 @@ func proxy(cmd *cobra.Command, args []string) {
 	port, _ := cmd.Flags().GetInt("port")
 	var exitCode int
 	if err := api.RunProxyServer(port, args, statusPrinter, &exitCode); err != nil {
-		log.Fatalf("Proxy server failed: %v", err)
+		return fmt.Errorf("proxy server failed: %w", err)
 	}
 
 	// pass exit code back to caller (if we executed a command)
 	if len(args) > 0 && exitCode != 0 {
 		os.Exit(exitCode)
 	}
+	return nil
 }
@@ -17,6 +17,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path"
@@ -33,8 +34,11 @@ import (
 	"golang.org/x/exp/maps"
 
 	"github.com/cisco-open/fsoc/cmd/version"
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
+	"github.com/cisco-open/fsoc/cmdkit/interrupt"
 	"github.com/cisco-open/fsoc/config"
 	"github.com/cisco-open/fsoc/logfilter"
+	"github.com/cisco-open/fsoc/output"
 	"github.com/cisco-open/fsoc/platform/api"
 )
 
@@ -51,6 +55,10 @@ const (
 
 var updateChannel chan *semver.Version
 
+var cancelTimeout context.CancelFunc // releases the --timeout deadline, if one is set
+
+var executedCmd *cobra.Command // the command that was executed, for displaying its error
+
 // rootCmd represents the base command when called without any subcommands
 // TODO: replace github link "for more info" with Cisco DevNet link for fsoc once published
 var rootCmd = &cobra.Command{
@@ -67,9 +75,27 @@ You can use the --config and --profile flags to select authentication credential
 environment variables FSOC_CONFIG and FSOC_PROFILE, respectively. The command line flags take precedence.
 If a profile is not specified otherwise, the current profile from the config file is used.
 
+In environments like CI, fsoc can be used without a config file: setting the FSOC_URL environment variable
+defines the "env" profile from FSOC_URL, FSOC_AUTH_METHOD, FSOC_TENANT and the credentials in FSOC_TOKEN,
+FSOC_SECRET_FILE or FSOC_CLIENT_ID and FSOC_CLIENT_SECRET. This profile is used unless a profile is selected
+with --profile or FSOC_PROFILE; it is never saved, and tokens obtained by logging in are kept only in memory.
+
 fsoc checks once a day if a newer version is available on github and warns if not running the latest stable version.
 You can use the --no-version-check flag or the FSOC_NO_VERSION_CHECK=1 environment variable to suppress the check.
 
+Use the --timeout flag to limit the time a command may run (e.g., --timeout=5m). Pressing Ctrl-C cancels any
+platform API calls in progress and ends the command; press it again to terminate fsoc immediately.
+
+Use the --record flag to save the platform API calls made by a command into a directory and the --replay flag
+to serve them back later without accessing the platform, e.g., for testing scripts. Auth tokens and secrets
+are removed from the recordings.
+
+When a command fails, fsoc exits with a code that indicates the kind of failure: 1 (general), 2 (invalid
+command line), 3 (authentication/authorization), 4 (not found), 5 (validation), 6 (conflict), 7 (throttled),
+8 (network), 9 (timeout) or 130 (interrupted). If JSON output is selected (-o json), the error is also
+displayed on stderr as a JSON object with the kind, exit code, message and, for platform API failures,
+the status, problem type, title, detail and trace response.
+
 fsoc logs its execution details into a log file. By default, fsoc shows only warning- and error-level log messages on 
 the output. You can use the --verbose flag to show all log messages and/or the --log flag to set a desired location
 for saving the log file.
@@ -86,16 +112,42 @@ NOTE: fsoc is in alpha; breaking changes may occur.`,
   fsoc solution list -o json
   FSOC_CONFIG=tenant5-config.yaml fsoc solution subscribe spacefleet --profile admin`,
 
-	PersistentPreRun:  preExecHook,
+	PersistentPreRunE: preExecHook,
 	PersistentPostRun: postExecHook,
 	TraverseChildren:  true,
 	DisableAutoGenTag: true,
+	SilenceErrors:     true, // errors are displayed by PrintError
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute(ctx context.Context) error {
-	return rootCmd.ExecuteContext(ctx)
+	// cancel the command's context on Ctrl-C/termination signal, so that in-progress
+	// platform API calls and wait loops can stop cleanly
+	ctx, stop := interrupt.NotifyContext(ctx)
+	defer stop()
+
+	var err error
+	executedCmd, err = rootCmd.ExecuteContextC(ctx)
+	return err
+}
+
+// PrintError displays the error that failed the command: as a JSON object on stderr
+// if JSON output is selected (so that scripts can process it) or as a log message otherwise
+func PrintError(err error) {
+	format := outputFormat
+	if executedCmd != nil {
+		// some commands define their own --output flag
+		if cmdFormat, err := executedCmd.Flags().GetString("output"); err == nil {
+			format = cmdFormat
+		}
+	}
+	if format == "json" || format == "jsonl" {
+		if jsonErr := clierror.WriteJSON(os.Stderr, err); jsonErr == nil {
+			return
+		}
+	}
+	log.WithFields(log.Fields{"error": err}).Error("command failed")
 }
 
 func init() {
@@ -107,12 +159,23 @@ func init() {
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", fmt.Sprintf("config file (default is %s). May be .yaml or .json", config.DefaultConfigFile))
 	rootCmd.PersistentFlags().StringVar(&cfgProfile, "profile", "", "access profile (default is current or \"default\")")
-	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "auto", "output format (auto, table, detail, json, yaml)")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "auto", "output format (auto, table, detail, json, jsonl, yaml, csv, tsv, template=TEMPLATE, template-file=FILE)")
 	rootCmd.PersistentFlags().String("fields", "", "perform specified fields transform/extract JQ expression")
+	rootCmd.PersistentFlags().String("sort-by", "", "sort table output by the specified column, optionally in descending order, e.g., --sort-by name,desc")
+	rootCmd.PersistentFlags().StringArray("filter-rows", nil, "display only table rows whose column matches a glob pattern, e.g., --filter-rows 'name=*prod*' (may be repeated)")
+	rootCmd.PersistentFlags().String("columns", "", "display only the specified table columns, in the specified order, e.g., --columns name,url")
+	rootCmd.PersistentFlags().Bool("no-headers", false, "don't display headers in table output")
+	rootCmd.PersistentFlags().Bool("wide", false, "don't wrap long values in table output")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "enable detailed output")
 	rootCmd.PersistentFlags().Bool("curl", false, "log curl equivalent for platform API calls (implies --verbose)")
 	rootCmd.PersistentFlags().String("log", path.Join(os.TempDir(), "fsoc.log"), "set a location and name for the fsoc log file")
 	rootCmd.PersistentFlags().Bool("no-version-check", false, "skip the daily check for new versions of fsoc")
+	rootCmd.PersistentFlags().String("record", "", "record platform API calls into the specified directory, for later use with --replay")
+	rootCmd.PersistentFlags().String("replay", "", "serve platform API calls from recordings in the specified directory (see --record), without accessing the platform")
+	rootCmd.PersistentFlags().Duration("timeout", 0, "abort the command if it doesn't complete within the specified time, e.g., 30s or 5m (default is no timeout)")
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return clierror.Wrap(clierror.Usage, err)
+	})
 	rootCmd.SetOut(os.Stdout)
 	rootCmd.SetErr(os.Stderr)
 	rootCmd.SetIn(os.Stdin)
@@ -181,7 +244,10 @@ func helperFlagFormatter(fs *pflag.FlagSet) string {
 
 // preExecHook is executed after the command line is parsed but
 // before the command's handler is executed
-func preExecHook(cmd *cobra.Command, args []string) {
+func preExecHook(cmd *cobra.Command, args []string) error {
+	// the command line has been parsed successfully; don't display usage if the command fails
+	cmd.SilenceUsage = true
+
 	logLocation, _ := cmd.Flags().GetString("log")
 	var file *os.File
 	var cliHandler log.Handler
@@ -217,14 +283,52 @@ func preExecHook(cmd *cobra.Command, args []string) {
 		"flags":     helperFlagFormatter(cmd.Flags())}).
 		Info("fsoc command line")
 
+	// check the table output flags before the command has any side effects
+	if err := output.ValidateTableFlags(cmd); err != nil {
+		return err
+	}
+
+	// set up the command's Go context, applying the --timeout deadline, and use it for platform API calls
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if timeout, _ := cmd.Flags().GetDuration("timeout"); timeout > 0 {
+		ctx, cancelTimeout = context.WithTimeoutCause(ctx, timeout, clierror.New(clierror.Timeout, "command timed out after %v (see --timeout)", timeout))
+		cmd.SetContext(ctx)
+	}
+	api.SetBaseContext(ctx)
+
+	// set up recording or replay of platform API calls, if requested
+	recordDir, _ := cmd.Flags().GetString("record")
+	replayDir, _ := cmd.Flags().GetString("replay")
+	if recordDir != "" && replayDir != "" {
+		return clierror.New(clierror.Usage, "the --record and --replay flags cannot be used together")
+	}
+	if recordDir != "" {
+		if err := api.EnableRecording(recordDir); err != nil {
+			return fmt.Errorf("failed to start recording: %w", err)
+		}
+	}
+	if replayDir != "" {
+		if err := api.EnableReplay(replayDir); err != nil {
+			return fmt.Errorf("failed to start replay: %w", err)
+		}
+	}
+
 	// Determine if a configured profile is required for this command
 	// (bypassed only for commands that must work or can safely work without it)
 	bypass := bypassConfig(cmd) || cmd.Name() == "help" || isCompletionCommand(cmd)
 
+	// load the profile defined by environment variables, if any; it doesn't require a config file
+	if err := config.LoadEnvContext(); err != nil {
+		return clierror.Wrap(clierror.Usage, err)
+	}
+
 	// try to read the config file.and profile
 	err = viper.ReadInConfig()
-	if err != nil && !bypass {
-		log.Fatal(`fsoc is not configured, please use "fsoc config create" to configure an initial context`)
+	if err != nil && !bypass && !config.HasEnvContext() {
+		return fmt.Errorf(`fsoc is not configured, please use "fsoc config create" to configure an initial context`)
 	}
 
 	// override the config file's current profile from cmd line or env var
@@ -233,18 +337,24 @@ func preExecHook(cmd *cobra.Command, args []string) {
 		log.Infof("Unable to read config file (%v), proceeding without a config", err)
 	} else { // err == nil
 		profile := config.GetCurrentProfileName() // may not exist, so don't try from cfg
-		cfg := config.GetCurrentContext()         // nil if profile does not exist
-		exists := cfg != nil
+		cfg, err := config.GetContext(profile)
+		exists := !errors.Is(err, config.ErrProfileNotFound)
+		if err != nil && !errors.Is(err, config.ErrProfileNotFound) {
+			if !bypass && !isConfigCommand(cmd) {
+				return clierror.Wrap(clierror.Validation, err)
+			}
+			log.Warnf("%v", err) // let the command, e.g., config doctor or set, report or fix it
+		}
 		if !exists && !bypass {
-			log.Fatalf(`fsoc is not fully configured: missing profile %q; please use "fsoc config create" to configure it`, profile)
+			return fmt.Errorf(`fsoc is not fully configured: missing profile %q; please use "fsoc config create" to configure it`, profile)
 		}
 		customSubsysConfigs := []string{}
-		if exists {
+		if cfg != nil {
 			err := config.UpdateSubsystemConfigs(cfg)
 			if err != nil {
 				// note: UpdateSubsystemConfig prints log.warnings for each error with enough context
 				// more details can be provided, e.g., log.Fatalf("Subsystem configuration %q in profile %q is not among recognized subsystems %v", name, profileName, maps.Keys(subsystemConfigs))
-				log.Fatalf("Failed to parse subsystem configurations in profile %q of config file %q: %v", profile, viper.ConfigFileUsed(), err)
+				return fmt.Errorf("failed to parse subsystem configurations in profile %q of config file %q: %w", profile, viper.ConfigFileUsed(), err)
 			}
 			customSubsysConfigs = maps.Keys(cfg.SubsystemConfigs)
 		}
@@ -263,9 +373,15 @@ func preExecHook(cmd *cobra.Command, args []string) {
 			updateChannel <- version.CheckForUpdate()
 		}()
 	}
+
+	return nil
 }
 
 func postExecHook(cmd *cobra.Command, args []string) {
+	if cancelTimeout != nil {
+		cancelTimeout()
+	}
+
 	latestVersion := completeVersionCheck()
 	if versionCheckEnabled(cmd) {
 		reportNewVersionAvailable(latestVersion)
@@ -294,6 +410,13 @@ func isCompletionCommand(cmd *cobra.Command) bool {
 	return (p != nil && p.Name() == "completion")
 }
 
+// isConfigCommand returns true for the profile management commands, which must work even if
+// the current profile is invalid, e.g., to diagnose, fix or delete it
+func isConfigCommand(cmd *cobra.Command) bool {
+	p := cmd.Parent()
+	return (p != nil && p.Name() == "config")
+}
+
 func versionCheckEnabled(cmd *cobra.Command) bool {
 	noVerCheck, _ := cmd.Flags().GetBool("no-version-check")
 	if noVerCheck {
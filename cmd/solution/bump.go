@@ -18,7 +18,6 @@ import (
 	"fmt"
 
 	"github.com/Masterminds/semver/v3"
-	"github.com/apex/log"
 	"github.com/spf13/cobra"
 
 	"github.com/cisco-open/fsoc/config"
@@ -32,7 +31,7 @@ var solutionBumpCmd = &cobra.Command{
 it for validation or push.`,
 	Example:          `  fsoc solution bump`,
 	Args:             cobra.ExactArgs(0),
-	Run:              bumpSolutionVersion,
+	RunE:             bumpSolutionVersion,
 	Annotations:      map[string]string{config.AnnotationForConfigBypass: ""},
 	TraverseChildren: true,
 }
@@ -43,25 +42,26 @@ func getSolutionBumpCmd() *cobra.Command {
 	return solutionBumpCmd
 }
 
-func bumpSolutionVersion(cmd *cobra.Command, args []string) {
+func bumpSolutionVersion(cmd *cobra.Command, args []string) error {
 	manifestDir := "."
 
 	manifest, err := getSolutionManifest(manifestDir)
 	if err != nil {
-		log.Fatalf("Failed to read solution manifest: %v", err)
+		return fmt.Errorf("failed to read solution manifest: %w", err)
 	}
 	oldVer := manifest.SolutionVersion
 
 	if err = bumpManifestPatchVersion(manifest); err != nil {
-		log.Fatalf(err.Error())
+		return err
 	}
 	newVer := manifest.SolutionVersion
 
 	if err = saveSolutionManifest(manifestDir, manifest); err != nil {
-		log.Fatalf("Failed to update solution manifest: %v", err)
+		return fmt.Errorf("failed to update solution manifest: %w", err)
 	}
 
 	output.PrintCmdStatus(cmd, fmt.Sprintf("Successfully bumped solution version from %v to %v\n", oldVer, newVer))
+	return nil
 }
 
 func bumpManifestPatchVersion(m *Manifest) error {
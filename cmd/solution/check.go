@@ -17,159 +17,364 @@ package solution
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 
-	"github.com/apex/log"
 	"github.com/spf13/cobra"
 	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v3"
 
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
 	"github.com/cisco-open/fsoc/config"
 	"github.com/cisco-open/fsoc/output"
-	"github.com/cisco-open/fsoc/platform/api"
 )
 
 var solutionCheckCmd = &cobra.Command{
-	Use:              "check",
-	Args:             cobra.ExactArgs(0),
-	Short:            "Validate your solution component definitions",
-	Long:             `This command allows the current tenant specified in the profile to check whether or not each FMM Knowledge Object inside their solution is valid.`,
-	Example:          `  fsoc solution check --entities --metrics`,
-	Run:              checkSolution,
+	Use:   "check",
+	Args:  cobra.ExactArgs(0),
+	Short: "Validate your solution component definitions",
+	Long: `This command validates the solution's knowledge types and objects against the JSON schemas of their types,
+without access to the platform. All objects listed in the manifest (JSON or YAML, in files and directories) are checked.
+
+Schemas are taken from the solution's own knowledge types, the schema bundle specified with --schemas (a directory
+or a zip file with a NAMESPACE/TYPE.json file for each type, e.g., fmm/entity.json), and the local schema cache.
+Use --fetch-schemas to fetch the schemas that are not available locally from the platform, using the current profile;
+they are added to the cache so that subsequent checks can run offline. Objects of types with no schema are reported
+as errors, since they cannot be validated; use --allow-missing-schemas to report them as warnings instead.
+
+Problems are reported with the file, line and JSON pointer of the invalid value. The command fails (exit code 5) if
+any errors are found, or warnings with --strict, so that it can be used to gate CI pipelines.`,
+	Example: `  fsoc solution check
+  fsoc solution check -d mysolution --schemas vendor/schemas --strict -o json
+  fsoc solution check --fetch-schemas
+  fsoc solution check --allow-missing-schemas
+  fsoc solution check --entities --metrics`,
+	RunE:             checkSolution,
 	TraverseChildren: true,
+	Annotations: map[string]string{
+		config.AnnotationForConfigBypass: "", // works offline, without a profile
+		output.TableFieldsAnnotation:     "file:.file, line:(.line // \"\"), pointer:(.pointer // \"\"), severity:.severity, message:.message",
+	},
 }
 
 func getSolutionCheckCmd() *cobra.Command {
 	solutionCheckCmd.Flags().
-		Bool("entities", false, "Validate all the entities and associations components defined in this solution")
+		StringP("directory", "d", "", "Path to the solution root directory (defaults to current dir)")
+
+	solutionCheckCmd.Flags().
+		String("schemas", "", "Path to a schema bundle (directory or zip file) with the schemas of the types used by the solution")
+
+	solutionCheckCmd.Flags().
+		Bool("fetch-schemas", false, "Fetch schemas that are not available locally from the platform and cache them")
+
+	solutionCheckCmd.Flags().
+		Bool("allow-missing-schemas", false, "Report objects of types with no schema available as warnings rather than errors")
+
+	solutionCheckCmd.Flags().
+		Bool("strict", false, "Fail on warnings, e.g., objects of types with no schema available when --allow-missing-schemas is used")
+
+	solutionCheckCmd.Flags().
+		Bool("entities", false, "Validate only the entities and associations components defined in this solution")
 
 	solutionCheckCmd.Flags().
-		Bool("metrics", false, "Validate all the metrics, metricmappings and metricaggregations components defined in this solution")
+		Bool("metrics", false, "Validate only the metrics, metricmappings and metricaggregations components defined in this solution")
 
 	solutionCheckCmd.Flags().
-		Bool("all", false, "Validate all the fmm components defined in this solution")
+		Bool("all", false, "Validate all components defined in this solution (default)")
+	_ = solutionCheckCmd.Flags().MarkDeprecated("all", "all components are validated by default.")
 
 	return solutionCheckCmd
 }
 
-func checkSolution(cmd *cobra.Command, args []string) {
-	var err error
+// check issue severities
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+)
 
-	manifestFile := openFile("manifest.json")
-	defer manifestFile.Close()
+// checkIssue is a problem found in a solution file
+type checkIssue struct {
+	File     string `json:"file"`              // path relative to the solution root
+	Line     int    `json:"line,omitempty"`    // 1-based, 0 if not known
+	Pointer  string `json:"pointer,omitempty"` // JSON pointer to the invalid value within the file
+	Type     string `json:"type,omitempty"`    // knowledge type of the object(s) in the file
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
 
-	manifestBytes, err := io.ReadAll(manifestFile)
-	if err != nil {
-		log.Fatalf("Failed to read manifest.json: %v", err)
+// checkReport is the result of checking a solution
+type checkReport struct {
+	Items    []checkIssue `json:"items"`
+	Total    int          `json:"total"`
+	Files    int          `json:"files"`   // number of files checked
+	Objects  int          `json:"objects"` // number of objects checked
+	Errors   int          `json:"errors"`
+	Warnings int          `json:"warnings"`
+}
+
+func (r *checkReport) add(issue checkIssue) {
+	r.Items = append(r.Items, issue)
+	r.Total++
+	if issue.Severity == severityError {
+		r.Errors++
+	} else {
+		r.Warnings++
 	}
+}
+
+var entityTypes = []string{"fmm:entity", "fmm:resourceMapping", "fmm:associationDeclaration", "fmm:associationDerivation"}
+var metricTypes = []string{"fmm:metric", "fmm:metricMapping", "fmm:metricAggregation"}
 
-	var manifest *Manifest
-	err = json.Unmarshal(manifestBytes, &manifest)
+func checkSolution(cmd *cobra.Command, args []string) error {
+	directory, _ := cmd.Flags().GetString("directory")
+	if directory == "" {
+		directory = "."
+	}
+	bundlePath, _ := cmd.Flags().GetString("schemas")
+	fetch, _ := cmd.Flags().GetBool("fetch-schemas")
+	strict, _ := cmd.Flags().GetBool("strict")
+	allowMissing, _ := cmd.Flags().GetBool("allow-missing-schemas")
+
+	// select the types to check (all by default)
+	var selectedTypes []string
+	if entities, _ := cmd.Flags().GetBool("entities"); entities {
+		selectedTypes = append(selectedTypes, entityTypes...)
+	}
+	if metrics, _ := cmd.Flags().GetBool("metrics"); metrics {
+		selectedTypes = append(selectedTypes, metricTypes...)
+	}
+
+	contents, err := NewSolutionDirectoryContentsFromDisk(directory)
+	if err != nil {
+		return clierror.Wrap(clierror.Validation, err)
+	}
+	store, err := newSchemaStore(bundlePath, defaultSchemaCacheDir(), fetch)
 	if err != nil {
-		log.Fatalf("Failed to parse manifest.json: %v", err)
+		return err
 	}
+	report := checkSolutionContents(contents, store, selectedTypes, allowMissing)
 
-	cfg := config.GetCurrentContext()
+	if err := output.PrintCmdOutput(cmd, report); err != nil {
+		return err
+	}
 
-	objDefs := manifest.Objects
-	for _, objDef := range objDefs {
-		typeSplit := strings.Split(objDef.Type, ":")
-		namespace := typeSplit[0]
-		typeName := typeSplit[1]
-		if namespace == "fmm" {
-			if cmd.Flags().Changed("all") {
-				checkComponentDef(cmd, objDef, cfg)
-			}
-			if cmd.Flags().Changed("entities") {
-				if typeName == "entity" || typeName == "resourceMapping" || typeName == "associationDeclaration" || typeName == "associationDerivation" {
-					checkComponentDef(cmd, objDef, cfg)
-				}
-			}
-			if cmd.Flags().Changed("metrics") {
-				if typeName == "metric" || typeName == "metricMapping" || typeName == "metricAggregation" {
-					checkComponentDef(cmd, objDef, cfg)
-				}
-			}
-		}
+	summary := fmt.Sprintf("Checked %d object(s) in %d file(s): %d error(s), %d warning(s)\n", report.Objects, report.Files, report.Errors, report.Warnings)
+	if report.Errors > 0 || (strict && report.Warnings > 0) {
+		return clierror.New(clierror.Validation, "solution check failed. %v", strings.TrimSpace(summary))
 	}
+	output.PrintCmdStatus(cmd, summary)
+	return nil
 }
 
-func getTypeUrl(fqtn string) string {
-	return fmt.Sprintf("knowledge-store/v1/types/%s", fqtn)
+// checkSolutionContents validates the solution's knowledge types and objects. If selectedTypes
+// is not empty, only objects of these types are checked. Objects of types with no schema are
+// reported as errors, or as warnings if allowMissing is true.
+func checkSolutionContents(s *SolutionDirectoryContents, store *schemaStore, selectedTypes []string, allowMissing bool) *checkReport {
+	report := &checkReport{Items: []checkIssue{}}
+
+	// knowledge types first, so that their schemas are available for the objects
+	_ = s.WalkFiles(func(file *SolutionFile, dir *SolutionSubDirectory) error {
+		if file.FileKind == KindKnowledgeType {
+			checkTypeFile(s, solutionFilePath(file, dir), file, store, report)
+		}
+		return nil
+	})
+
+	_ = s.WalkFiles(func(file *SolutionFile, dir *SolutionSubDirectory) error {
+		if file.FileKind != KindObjectType || file.ObjectType == "" {
+			return nil
+		}
+		if len(selectedTypes) > 0 && !slices.Contains(selectedTypes, file.ObjectType) {
+			return nil
+		}
+		checkObjectFile(solutionFilePath(file, dir), file, store, allowMissing, report)
+		return nil
+	})
+
+	return report
 }
 
-func Fetch(path string, httpOptions *api.Options) map[string]interface{} {
-	// finalize override fields
-	var res map[string]interface{}
-	// fetch data
-	if err := api.JSONGet(path, &res, httpOptions); err != nil {
-		log.Fatalf("Platform API call failed: %v", err)
+func solutionFilePath(file *SolutionFile, dir *SolutionSubDirectory) string {
+	if dir == nil {
+		return file.Name
 	}
-	return res
+	return filepath.ToSlash(filepath.Join(dir.Name, file.Name))
 }
 
-func checkComponentDef(cmd *cobra.Command, compDef ComponentDef, cfg *config.Context) {
-	layerID := cfg.Tenant
-
-	headers := map[string]string{
-		"layer-type": "TENANT",
-		"layer-id":   layerID,
+// checkTypeFile checks a knowledge type definition and registers its schema with the store
+func checkTypeFile(s *SolutionDirectoryContents, path string, file *SolutionFile, store *schemaStore, report *checkReport) {
+	report.Files++
+	doc, node, issue := parseSolutionFile(path, file)
+	if issue != nil {
+		report.add(*issue)
+		return
 	}
 
-	objStoreUrl := getTypeUrl(compDef.Type)
-	typeDef := Fetch(objStoreUrl, &api.Options{Headers: headers})
-
-	w := new(bytes.Buffer)
-	err := output.WriteJson(typeDef["jsonSchema"], w)
-	if err != nil {
-		log.Errorf("Couldn't marshal schema to json: %v", err)
+	typeDef, ok := doc.(map[string]any)
+	name, _ := typeDef["name"].(string)
+	schema, hasSchema := typeDef["jsonSchema"]
+	switch {
+	case !ok:
+		report.add(checkIssue{File: path, Line: nodeLine(node, nil), Severity: severityError, Message: "a knowledge type definition must be an object"})
+	case name == "":
+		report.add(checkIssue{File: path, Line: nodeLine(node, nil), Pointer: "/name", Severity: severityError, Message: "the knowledge type has no name"})
+	case !hasSchema:
+		report.add(checkIssue{File: path, Line: nodeLine(node, nil), Pointer: "/jsonSchema", Severity: severityError, Message: "the knowledge type has no JSON schema"})
+	default:
+		fqtn := s.Manifest.Name + ":" + name
+		store.addSolutionType(fqtn, schema)
+		if _, err := store.get(fqtn); err != nil {
+			report.add(checkIssue{File: path, Line: nodeLine(node, []string{"jsonSchema"}), Pointer: "/jsonSchema", Type: fqtn, Severity: severityError, Message: err.Error()})
+		}
 	}
-	schemaLoader := gojsonschema.NewStringLoader(w.String())
+}
 
-	compDefFile := openFile(compDef.ObjectsFile)
-	defer compDefFile.Close()
+// checkObjectFile validates the object(s) in a file against the schema of their type
+func checkObjectFile(path string, file *SolutionFile, store *schemaStore, allowMissing bool, report *checkReport) {
+	report.Files++
+	fqtn := file.ObjectType
+	doc, node, issue := parseSolutionFile(path, file)
+	if issue != nil {
+		issue.Type = fqtn
+		report.add(*issue)
+		return
+	}
 
-	compDefBytes, _ := io.ReadAll(compDefFile)
+	// a file contains either a single object or an array of objects
+	objects := []any{doc}
+	prefixes := [][]string{nil}
+	if array, ok := doc.([]any); ok {
+		objects = array
+		prefixes = make([][]string, len(array))
+		for i := range array {
+			prefixes[i] = []string{strconv.Itoa(i)}
+		}
+	}
+	report.Objects += len(objects)
 
-	x := bytes.TrimLeft(compDefBytes, " \t\r\n")
+	schema, err := store.get(fqtn)
+	if err != nil {
+		report.add(checkIssue{File: path, Type: fqtn, Severity: severityError, Message: err.Error()})
+		return
+	}
+	if schema == nil {
+		message := fmt.Sprintf("no schema available for type %q; use --schemas or --fetch-schemas to validate its objects", fqtn)
+		if allowMissing {
+			report.add(checkIssue{File: path, Type: fqtn, Severity: severityWarning, Message: message})
+		} else {
+			report.add(checkIssue{File: path, Type: fqtn, Severity: severityError, Message: message + ", or --allow-missing-schemas to skip them"})
+		}
+		return
+	}
 
-	isArray := len(x) > 0 && x[0] == '['
-	// isObject := len(x) > 0 && x[0] == '{'
-	if isArray {
-		var jsonArray []map[string]interface{}
-		err = json.Unmarshal(compDefBytes, &jsonArray)
+	for i, object := range objects {
+		result, err := schema.Validate(gojsonschema.NewGoLoader(object))
 		if err != nil {
-			log.Errorf("Failed to parse component: %v", err)
+			report.add(checkIssue{File: path, Line: nodeLine(node, prefixes[i]), Pointer: jsonPointer(prefixes[i]), Type: fqtn, Severity: severityError, Message: err.Error()})
+			continue
 		}
-		for _, object := range jsonArray {
-			w := new(bytes.Buffer)
-			err = output.WriteJson(object, w)
-			if err != nil {
-				log.Errorf("Couldn't marshal object to json: %v", err)
+		for _, resultErr := range result.Errors() {
+			tokens := append(slices.Clone(prefixes[i]), contextTokens(resultErr.Context())...)
+			report.add(checkIssue{
+				File:     path,
+				Line:     nodeLine(node, tokens),
+				Pointer:  jsonPointer(tokens),
+				Type:     fqtn,
+				Severity: severityError,
+				Message:  resultErr.Description(),
+			})
+		}
+	}
+}
+
+// parseSolutionFile parses a JSON or YAML solution file, returning its data and its YAML
+// node tree (YAML being a superset of JSON), which provides the line numbers for values
+func parseSolutionFile(path string, file *SolutionFile) (any, *yaml.Node, *checkIssue) {
+	data := file.Contents.Bytes()
+	var doc any
+	switch file.Encoding {
+	case EncodingJSON:
+		if err := json.Unmarshal(data, &doc); err != nil {
+			issue := &checkIssue{File: path, Severity: severityError, Message: fmt.Sprintf("invalid JSON: %v", err)}
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				issue.Line = bytes.Count(data[:syntaxErr.Offset], []byte("\n")) + 1
 			}
-			documentLoader := gojsonschema.NewStringLoader(w.String())
-			validate(cmd, schemaLoader, documentLoader, compDef)
+			return nil, nil, issue
 		}
-	} else {
-		documentLoader := gojsonschema.NewStringLoader(string(compDefBytes))
-		validate(cmd, schemaLoader, documentLoader, compDef)
+	case EncodingYAML:
+		// parsed from the node tree below
+	default:
+		return nil, nil, &checkIssue{File: path, Severity: severityError, Message: "unsupported file format; objects must be in JSON (.json) or YAML (.yaml, .yml) files"}
 	}
 
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, nil, &checkIssue{File: path, Severity: severityError, Message: fmt.Sprintf("invalid YAML: %v", err)}
+	}
+	if file.Encoding == EncodingYAML {
+		if err := node.Decode(&doc); err != nil {
+			return nil, nil, &checkIssue{File: path, Line: node.Line, Severity: severityError, Message: fmt.Sprintf("invalid YAML: %v", err)}
+		}
+	}
+	return doc, &node, nil
 }
 
-func validate(cmd *cobra.Command, schemaLoader, documentLoader gojsonschema.JSONLoader, compDef ComponentDef) {
-	result, err := gojsonschema.Validate(schemaLoader, documentLoader)
-	if err != nil {
-		log.Fatalf("Schema validation failed: %v", err)
+// contextTokens converts a schema validation error's context, e.g., "(root).a.0", to reference tokens.
+// Note that property names containing "." cannot be distinguished from nested properties.
+func contextTokens(context *gojsonschema.JsonContext) []string {
+	if context == nil {
+		return nil
 	}
+	tokens := strings.Split(context.String("\x00"), "\x00")
+	return tokens[1:] // skip "(root)"
+}
 
-	if result.Valid() {
-		output.PrintCmdStatus(cmd, fmt.Sprintf("The components defined in the file %q are valid definitions of type %q \n", compDef.ObjectsFile, compDef.Type))
-	} else {
-		output.PrintCmdStatus(cmd, fmt.Sprintf("The components defined in the file %q are invalid definitions of type %q ! \n", compDef.ObjectsFile, compDef.Type))
-		for _, desc := range result.Errors() {
-			output.PrintCmdStatus(cmd, fmt.Sprintf("- %s\n", desc))
+// jsonPointer creates a JSON pointer (RFC 6901) from reference tokens
+func jsonPointer(tokens []string) string {
+	escaper := strings.NewReplacer("~", "~0", "/", "~1")
+	var sb strings.Builder
+	for _, token := range tokens {
+		sb.WriteString("/" + escaper.Replace(token))
+	}
+	return sb.String()
+}
+
+// nodeLine returns the line of the value at the reference tokens within the node tree or, if the
+// value doesn't exist (e.g., a missing property), of its closest existing ancestor; 0 if not known
+func nodeLine(node *yaml.Node, tokens []string) int {
+	if node == nil {
+		return 0
+	}
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	line := node.Line
+	for _, token := range tokens {
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == token {
+					next = node.Content[i+1]
+					line = node.Content[i].Line // the property's line rather than its (possibly multi-line) value's
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			if index, err := strconv.Atoi(token); err == nil && index >= 0 && index < len(node.Content) {
+				next = node.Content[index]
+				line = next.Line
+			}
+		}
+		if next == nil {
+			break
 		}
+		node = next
 	}
+	return line
 }
@@ -0,0 +1,117 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solution
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+func TestCheckSolutionContents(t *testing.T) {
+	solutionDir := t.TempDir()
+	writeTestFiles(t, solutionDir, map[string]string{
+		"manifest.yaml": `manifestVersion: "1.0.0"
+name: mysol
+solutionVersion: 1.0.0
+dependencies: [fmm]
+types:
+  - types/widget.json
+objects:
+  - type: mysol:widget
+    objectsDir: objects
+  - type: fmm:metric
+    objectsFile: metrics.json
+`,
+		"types/widget.json": `{
+  "name": "widget",
+  "identifyingProperties": ["/name"],
+  "jsonSchema": {
+    "type": "object",
+    "required": ["name", "size"],
+    "properties": {"name": {"type": "string"}, "size": {"type": "integer", "minimum": 1}}
+  }
+}`,
+		"objects/a.json": `[
+  {"name": "a", "size": 2},
+  {
+    "name": "b",
+    "size": 0
+  }
+]`,
+		"objects/b.yaml": "name: b\nsize: big\n",
+		"objects/c.json": `{"name": }`,
+		"metrics.json":   `{"name": "requests"}`,
+	})
+
+	s, err := NewSolutionDirectoryContentsFromDisk(solutionDir)
+	require.NoError(t, err)
+	store, err := newSchemaStore("", t.TempDir(), false)
+	require.NoError(t, err)
+
+	report := checkSolutionContents(s, store, nil, true)
+	issues := map[string]checkIssue{}
+	for _, issue := range report.Items {
+		issues[issue.File] = issue
+	}
+
+	assert.Equal(t, 3, report.Errors)
+	assert.Equal(t, 1, report.Warnings)
+	assert.Equal(t, checkIssue{File: "objects/a.json", Line: 5, Pointer: "/1/size", Type: "mysol:widget", Severity: "error", Message: issues["objects/a.json"].Message}, issues["objects/a.json"])
+	assert.Equal(t, 2, issues["objects/b.yaml"].Line)
+	assert.Equal(t, "/size", issues["objects/b.yaml"].Pointer)
+	assert.Contains(t, issues["objects/c.json"].Message, "invalid JSON")
+	assert.Equal(t, "warning", issues["metrics.json"].Severity)
+
+	// without a schema bundle or cache, objects of platform types fail the check by default
+	report = checkSolutionContents(s, store, []string{"fmm:metric"}, false)
+	require.Len(t, report.Items, 1)
+	assert.Equal(t, "error", report.Items[0].Severity)
+	assert.Equal(t, "metrics.json", report.Items[0].File)
+	assert.Contains(t, report.Items[0].Message, "no schema available")
+	assert.Equal(t, 1, report.Errors)
+	assert.Equal(t, 0, report.Warnings)
+
+	// a schema bundle provides the schemas of platform types
+	bundleDir := t.TempDir()
+	writeTestFiles(t, bundleDir, map[string]string{
+		"fmm/metric.json": `{"type": "object", "required": ["name", "category"]}`,
+	})
+	store, err = newSchemaStore(bundleDir, t.TempDir(), false)
+	require.NoError(t, err)
+
+	report = checkSolutionContents(s, store, []string{"fmm:metric"}, false)
+	require.Len(t, report.Items, 1)
+	assert.Equal(t, "error", report.Items[0].Severity)
+	assert.Equal(t, "metrics.json", report.Items[0].File)
+	assert.NotContains(t, report.Items[0].Message, "no schema available")
+	assert.Equal(t, 0, report.Warnings)
+}
+
+func TestJsonPointer(t *testing.T) {
+	assert.Equal(t, "", jsonPointer(nil))
+	assert.Equal(t, "/a~1b/m~0n/0", jsonPointer([]string{"a/b", "m~n", "0"}))
+}
@@ -15,14 +15,15 @@
 package solution
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"reflect"
 	"time"
 
-	"github.com/apex/log"
 	"github.com/spf13/cobra"
 
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
 	"github.com/cisco-open/fsoc/config"
 	"github.com/cisco-open/fsoc/output"
 	"github.com/cisco-open/fsoc/platform/api"
@@ -57,7 +58,7 @@ Please note you must terminate all active subscriptions to the solution before i
 Please also note this is an asynchronous operation and thus it may take some time for the status to reflect properly.
 If you issue this command while an active deletion is in progress, it will simply wait for that deletion to finish.`,
 	Example:          `  fsoc solution delete mysolution --tag custom --wait 45 --yes`,
-	Run:              deleteSolution,
+	RunE:             deleteSolution,
 	TraverseChildren: true,
 }
 
@@ -82,7 +83,7 @@ func getSolutionDeleteCommand() *cobra.Command {
 	return solutionDeleteCmd
 }
 
-func deleteSolution(cmd *cobra.Command, args []string) {
+func deleteSolution(cmd *cobra.Command, args []string) error {
 	var confirmationAnswer string
 	var solutionName string
 	var solutionTag string
@@ -94,7 +95,11 @@ func deleteSolution(cmd *cobra.Command, args []string) {
 	waitForDeletionDuration, _ := cmd.Flags().GetInt("wait")
 	noWait, _ := cmd.Flags().GetBool("no-wait")
 
-	solutionName = getSolutionNameFromArgs(cmd, args, "")
+	var err error
+	solutionName, err = getSolutionNameFromArgs(cmd, args, "")
+	if err != nil {
+		return err
+	}
 
 	headers := map[string]string{
 		"tag": solutionTag,
@@ -105,11 +110,14 @@ func deleteSolution(cmd *cobra.Command, args []string) {
 		fmt.Scanln(&confirmationAnswer)
 
 		if confirmationAnswer != solutionName {
-			log.Fatal("Solution delete not confirmed, exiting command")
+			return fmt.Errorf("solution delete not confirmed, exiting command")
 		}
 	}
 
-	existingDeletionObj := getSolutionDeletionObject(solutionTag, solutionName)
+	existingDeletionObj, err := getSolutionDeletionObject(solutionTag, solutionName)
+	if err != nil {
+		return err
+	}
 
 	if !existingDeletionObj.IsEmpty() {
 		existingSolutionDeletionObjectId = existingDeletionObj.ID
@@ -124,7 +132,7 @@ func deleteSolution(cmd *cobra.Command, args []string) {
 		var res any
 		err := api.JSONDelete(solutionDeleteUrl, &res, &api.Options{Headers: headers})
 		if err != nil {
-			log.Fatalf("Solution delete command failed: %v", err)
+			return fmt.Errorf("solution delete command failed: %w", err)
 		}
 	}
 
@@ -138,12 +146,19 @@ func deleteSolution(cmd *cobra.Command, args []string) {
 		for (newDeletionObjectId == existingSolutionDeletionObjectId && !existingSolutionDeletionInProgress) || deletionObjData.IsEmpty() || deletionObjData.Status == "inProgress" {
 			output.PrintCmdStatus(cmd, fmt.Sprintf("Waited %f seconds for solution with name: %s and tag: %s to be marked as deleted\n", time.Since(waitStartTime).Seconds(), solutionName, solutionTag))
 			if time.Since(waitStartTime).Seconds() > float64(waitForDeletionDuration) {
-				log.Fatalf("Timed out waiting for solution with name %s and tag: %s to be deleted. Deletion continues, please check status for outcome.", solutionName, solutionTag)
+				return clierror.New(clierror.Timeout, "timed out waiting for solution with name %s and tag: %s to be deleted. Deletion continues, please check status for outcome.", solutionName, solutionTag)
+			}
+			deletionObj, err := getSolutionDeletionObject(solutionTag, solutionName)
+			if err != nil {
+				return err
 			}
-			deletionObj := getSolutionDeletionObject(solutionTag, solutionName)
 			deletionObjData = deletionObj.DeletionData
 			newDeletionObjectId = deletionObj.ID
-			time.Sleep(3 * time.Second)
+			select {
+			case <-time.After(3 * time.Second):
+			case <-cmd.Context().Done():
+				return fmt.Errorf("stopped waiting for solution with name %s and tag: %s to be deleted: %w. Deletion continues, please check status for outcome.", solutionName, solutionTag, context.Cause(cmd.Context()))
+			}
 		}
 
 		if deletionObjData.Status == "successful" {
@@ -152,6 +167,7 @@ func deleteSolution(cmd *cobra.Command, args []string) {
 			output.PrintCmdStatus(cmd, fmt.Sprintf("Failed to delete solution with name: %s and tag %s.  Error message: %s", solutionName, solutionTag, deletionObjData.DeleteMessage))
 		}
 	}
+	return nil
 }
 
 func getSolutionDeleteUrl() string {
@@ -170,7 +186,7 @@ func (s SolutionDeletionRecord) IsEmpty() bool {
 	return reflect.DeepEqual(s, SolutionDeletionRecord{})
 }
 
-func getSolutionDeletionObject(solutionTag string, solutionName string) SolutionDeletionRecord {
+func getSolutionDeletionObject(solutionTag string, solutionName string) (SolutionDeletionRecord, error) {
 	var res SolutionDeletionResponseBlob
 	var emptyData SolutionDeletionRecord
 
@@ -189,12 +205,12 @@ func getSolutionDeletionObject(solutionTag string, solutionName string) Solution
 	err := api.JSONGet(url, &res, &api.Options{Headers: headers})
 
 	if err != nil {
-		log.Fatalf("Error fetching solution deletion object %q: %v", url, err)
+		return emptyData, fmt.Errorf("error fetching solution deletion object %q: %w", url, err)
 	}
 
 	if len(res.Items) > 0 {
-		return res.Items[0]
+		return res.Items[0], nil
 	} else {
-		return emptyData
+		return emptyData, nil
 	}
 }
@@ -15,6 +15,7 @@
 package solution
 
 import (
+	"fmt"
 	"github.com/apex/log"
 	"github.com/spf13/cobra"
 
@@ -29,7 +30,7 @@ var solutionDescribeCmd = &cobra.Command{
 	Short:   "Describe solution",
 	Long:    `Obtain metadata about a solution`,
 	Example: `  fsoc solution describe spacefleet`,
-	Run:     solutionDescribe,
+	RunE:    solutionDescribe,
 	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		config.SetActiveProfile(cmd, args, false)
 		return getSolutionNames(toComplete), cobra.ShellCompDirectiveDefault
@@ -44,8 +45,11 @@ func getSolutionDescribeCmd() *cobra.Command {
 	return solutionDescribeCmd
 }
 
-func solutionDescribe(cmd *cobra.Command, args []string) {
-	solution := getSolutionNameFromArgs(cmd, args, "solution")
+func solutionDescribe(cmd *cobra.Command, args []string) error {
+	solution, err := getSolutionNameFromArgs(cmd, args, "solution")
+	if err != nil {
+		return err
+	}
 
 	cfg := config.GetCurrentContext()
 	layerID := cfg.Tenant
@@ -57,9 +61,9 @@ func solutionDescribe(cmd *cobra.Command, args []string) {
 
 	log.WithField("solution", solution).Info("Getting solution details")
 	var res Solution
-	err := api.JSONGet(getSolutionObjectUrl(solution), &res, &api.Options{Headers: headers})
+	err = api.JSONGet(getSolutionObjectUrl(solution), &res, &api.Options{Headers: headers})
 	if err != nil {
-		log.Fatalf("Cannot get solution details: %v", err)
+		return fmt.Errorf("cannot get solution details: %w", err)
 	}
-	output.PrintCmdOutput(cmd, res)
+	return output.PrintCmdOutput(cmd, res)
 }
@@ -0,0 +1,390 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solution
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
+	"github.com/cisco-open/fsoc/cmdkit/interrupt"
+	"github.com/cisco-open/fsoc/output"
+)
+
+var solutionDevCmd = &cobra.Command{
+	Use:   "dev",
+	Args:  cobra.ExactArgs(0),
+	Short: "Rebuild, validate and push the solution whenever it changes",
+	Long: `This command watches the solution directory and, whenever its files change, packages the solution,
+validates it, and pushes it to the current profile's tenant, showing the installation status as it progresses.
+Changes are picked up once the files have stopped changing for the --debounce period, so that saving several
+files at once results in a single push.
+
+Before each push, the patch version of the solution is incremented in the manifest (unless --no-bump is
+specified); a version that fails validation is not incremented again, so that fixing the errors pushes it.
+Files excluded from the solution package (see .fsocignore) don't trigger a push.
+
+The tag is determined as for "solution push" (--tag flag, FSOC_SOLUTION_TAG environment variable or the .tag
+file), defaulting to "dev". The stable tag is not allowed; use "solution push" to release a solution.
+
+The command runs until interrupted with Ctrl-C; use --once to build and push the solution only once.`,
+	Example: `  fsoc solution dev
+  fsoc solution dev -d mysolution --tag=mydev
+  fsoc solution dev --debounce=5s --wait=600
+  fsoc solution dev --once --no-bump`,
+	RunE:             devSolution,
+	TraverseChildren: true,
+}
+
+// defaultDevTag is the tag used by solution dev when no tag is specified
+const defaultDevTag = "dev"
+
+// devPollInterval is how often solution dev checks the solution directory for changes
+const devPollInterval = 500 * time.Millisecond
+
+func getSolutionDevCmd() *cobra.Command {
+	solutionDevCmd.Flags().
+		StringP("directory", "d", "", "Path to the solution root directory (defaults to current dir)")
+
+	solutionDevCmd.Flags().
+		String("tag", "", fmt.Sprintf("Tag to push the solution with (defaults to %q)", defaultDevTag))
+
+	solutionDevCmd.Flags().
+		Duration("debounce", time.Second, "Time without further changes to wait for before rebuilding the solution")
+
+	solutionDevCmd.Flags().
+		IntP("wait", "w", 300, "Time (in seconds) to wait for each version to be installed (0 to wait indefinitely, -1 to not wait)")
+
+	solutionDevCmd.Flags().
+		Bool("no-bump", false, "Don't increment the patch version before pushing the solution")
+
+	solutionDevCmd.Flags().
+		Bool("no-isolate", false, "Disable fsoc-supported solution pseudo-isolation")
+
+	solutionDevCmd.Flags().
+		Bool("once", false, "Build and push the solution once, without watching for changes")
+
+	return solutionDevCmd
+}
+
+// devSession is the state of a solution dev command across rebuilds
+type devSession struct {
+	cmd           *cobra.Command
+	dir           string // absolute path to the solution root directory
+	wait          int    // seconds to wait for installation (0 indefinitely, negative to not wait)
+	bump          bool
+	status        *devStatus
+	fingerprint   string // fingerprint of the solution directory, as of the last build
+	bumped        bool   // whether the version has been bumped in this session
+	pushedVersion string // the last solution version pushed, if any
+}
+
+func devSolution(cmd *cobra.Command, args []string) error {
+	dir, _ := cmd.Flags().GetString("directory")
+	if dir == "" {
+		dir = "."
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	if !isSolutionPackageRoot(dir) {
+		return clierror.New(clierror.Usage, "no solution manifest found in %q; please use the -d flag", dir)
+	}
+
+	// determine the tag, defaulting to "dev" instead of failing, and pin it for isolation
+	if !cmd.Flags().Changed("tag") && os.Getenv("FSOC_SOLUTION_TAG") == "" {
+		if _, err := os.Stat(filepath.Join(dir, TagFileName)); errors.Is(err, os.ErrNotExist) {
+			_ = cmd.Flags().Set("tag", defaultDevTag)
+		}
+	}
+	tag, err := getEmbeddedTag(cmd, dir)
+	if err != nil {
+		return clierror.Wrap(clierror.Usage, err)
+	}
+	if tag == "stable" {
+		return clierror.New(clierror.Usage, `the stable tag cannot be used for development; please use "solution push" to release the solution`)
+	}
+	_ = cmd.Flags().Set("tag", tag)
+
+	debounce, _ := cmd.Flags().GetDuration("debounce")
+	once, _ := cmd.Flags().GetBool("once")
+	noBump, _ := cmd.Flags().GetBool("no-bump")
+	wait, _ := cmd.Flags().GetInt("wait")
+	s := &devSession{
+		cmd:    cmd,
+		dir:    dir,
+		wait:   wait,
+		bump:   !noBump,
+		status: newDevStatus(cmd),
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for {
+		if s.fingerprint, err = fingerprintSolution(dir); err != nil {
+			return err
+		}
+		err = s.build()
+		if ctx.Err() != nil {
+			return s.stop(ctx)
+		}
+		if once {
+			return err
+		}
+		if err != nil {
+			s.status.done("Failed: %v", err)
+		}
+
+		s.status.update("Watching %s for changes (Ctrl-C to stop)", dir)
+		if _, err := waitForSolutionChange(ctx, dir, s.fingerprint, devPollInterval, debounce); err != nil {
+			if ctx.Err() != nil {
+				return s.stop(ctx)
+			}
+			return err
+		}
+	}
+}
+
+// stop ends the session once the command's context is done, treating Ctrl-C as a normal exit
+func (s *devSession) stop(ctx context.Context) error {
+	s.status.done("Stopped")
+	if cause := context.Cause(ctx); !errors.Is(cause, interrupt.ErrInterrupted) {
+		return cause
+	}
+	return nil
+}
+
+// build packages, validates and pushes the solution, and waits for it to be installed
+func (s *devSession) build() error {
+	started := time.Now()
+
+	// bump the version, unless the current version has not been pushed yet (e.g., it failed validation)
+	manifest, err := getSolutionManifest(s.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read the solution manifest from %q: %w", s.dir, err)
+	}
+	if s.bump && (!s.bumped || manifest.SolutionVersion == s.pushedVersion) {
+		if err := bumpManifestPatchVersion(manifest); err != nil {
+			return err
+		}
+		if err := saveSolutionManifest(s.dir, manifest); err != nil {
+			return fmt.Errorf("failed to update solution manifest in %q after version bump: %w", s.dir, err)
+		}
+		s.bumped = true
+		if s.fingerprint, err = fingerprintSolution(s.dir); err != nil { // don't treat the bump as a change
+			return err
+		}
+	}
+	version := manifest.SolutionVersion
+	s.status.update("Building %s %s", manifest.Name, version)
+
+	// isolate and package quietly, to keep the summary compact
+	quiet := &cobra.Command{}
+	quiet.Flags().AddFlagSet(s.cmd.Flags())
+	quiet.SetOut(io.Discard)
+	quiet.SetErr(io.Discard)
+	buildDir, tag, err := embeddedConditionalIsolate(quiet, s.dir)
+	if err != nil {
+		return fmt.Errorf("failed to isolate solution with tag: %w", err)
+	}
+	apiTag := tag
+	if buildDir != s.dir { // pseudo-isolated
+		defer os.RemoveAll(buildDir)
+		if manifest, err = getSolutionManifest(buildDir); err != nil {
+			return fmt.Errorf("failed to read the solution manifest from %q: %w", buildDir, err)
+		}
+		apiTag = pseudoIsolationApiTag(tag)
+	}
+	archive, err := createSolutionZip(quiet, buildDir, "")
+	if err != nil {
+		return fmt.Errorf("failed to package the solution: %w", err)
+	}
+	defer os.Remove(archive.Name())
+	display := fmt.Sprintf("%s %s (tag %s)", manifest.Name, manifest.SolutionVersion, tag)
+	log.WithFields(log.Fields{
+		"name":       manifest.Name,
+		"version":    manifest.SolutionVersion,
+		"tag":        tag,
+		"header_tag": apiTag,
+		"zip_file":   archive.Name(),
+	}).Info("Solution details")
+
+	// validate and push
+	s.status.update("Validating %s", display)
+	res, err := postSolutionArchive(archive.Name(), apiTag, false)
+	if err != nil {
+		return err
+	}
+	if !res.Valid {
+		s.status.done("Validation failed for %s", display)
+		output.PrintCmdStatus(s.cmd, getSolutionValidationErrorsString(res.Errors.Total, res.Errors))
+		return clierror.New(clierror.Validation, "%d error(s) found while validating the solution", res.Errors.Total)
+	}
+	s.status.update("Pushing %s", display)
+	if _, err := postSolutionArchive(archive.Name(), apiTag, true); err != nil {
+		return err
+	}
+	s.pushedVersion = version
+	if s.wait < 0 {
+		s.status.done("Pushed %s", display)
+		return nil
+	}
+
+	// stream the installation status
+	installStarted := time.Now()
+	statusData, err := waitForSolutionInstall(s.cmd, manifest.Name, manifest.SolutionVersion, apiTag, s.wait, func(StatusData) {
+		s.status.progress(installStarted, "Installing %s", display)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to validate %s was installed: %w", display, err)
+	}
+	if !statusData.SuccessfulInstall {
+		return fmt.Errorf("failed to install %s: %s", display, statusData.InstallMessage)
+	}
+	s.status.done("Installed %s in %v", display, time.Since(started).Round(time.Second))
+	return nil
+}
+
+// fingerprintSolution summarizes the paths, sizes and modification times of the files that
+// would be packaged from the solution directory, so that changes can be detected cheaply
+func fingerprintSolution(dir string) (string, error) {
+	ignored, err := loadIgnoreList(dir)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) { // removed while walking, will be picked up next time
+				return nil
+			}
+			return err
+		}
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil || relPath == "." {
+			return err
+		}
+		if !isAllowedPath(relPath, info) || ignored.isIgnored(relPath, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.IsDir() {
+			fmt.Fprintf(hash, "%s\x00%d\x00%d\n", filepath.ToSlash(relPath), info.Size(), info.ModTime().UnixNano())
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to scan solution directory %q: %w", dir, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// waitForSolutionChange polls the solution directory until its fingerprint differs from the given
+// one and then remains unchanged for the debounce period. It returns the new fingerprint.
+func waitForSolutionChange(ctx context.Context, dir, fingerprint string, interval, debounce time.Duration) (string, error) {
+	current := fingerprint
+	var changedAt time.Time
+	for {
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return "", context.Cause(ctx)
+		}
+
+		latest, err := fingerprintSolution(dir)
+		if err != nil {
+			return "", err
+		}
+		if latest != current { // still changing
+			current = latest
+			changedAt = time.Now()
+			continue
+		}
+		if current != fingerprint && time.Since(changedAt) >= debounce {
+			return current, nil
+		}
+	}
+}
+
+// devStatus displays a compact summary of the solution dev progress. On a terminal, the
+// current step is shown on a single line that is updated in place; otherwise, each
+// step is printed on its own line.
+type devStatus struct {
+	cmd      *cobra.Command
+	live     bool
+	lastLine string // last line displayed, without the timestamp
+}
+
+func newDevStatus(cmd *cobra.Command) *devStatus {
+	f, ok := cmd.OutOrStderr().(*os.File)
+	return &devStatus{
+		cmd:  cmd,
+		live: ok && term.IsTerminal(int(f.Fd())),
+	}
+}
+
+// update displays the current step
+func (s *devStatus) update(format string, a ...any) {
+	s.show(fmt.Sprintf(format, a...))
+}
+
+// progress displays the current step along with the time elapsed since it started (on a terminal only,
+// so that repeated updates don't produce repeated lines)
+func (s *devStatus) progress(started time.Time, format string, a ...any) {
+	line := fmt.Sprintf(format, a...)
+	if s.live {
+		line += fmt.Sprintf(" (%v)", time.Since(started).Round(time.Second))
+	}
+	s.show(line)
+}
+
+// done displays the outcome of a step, keeping it visible on a terminal
+func (s *devStatus) done(format string, a ...any) {
+	s.show(fmt.Sprintf(format, a...))
+	if s.live {
+		output.PrintCmdStatus(s.cmd, "\n")
+	}
+	s.lastLine = ""
+}
+
+func (s *devStatus) show(line string) {
+	if !s.live && line == s.lastLine {
+		return
+	}
+	s.lastLine = line
+	line = fmt.Sprintf("[%s] %s", time.Now().Format(time.TimeOnly), line)
+	if s.live {
+		output.PrintCmdStatus(s.cmd, "\r\033[K"+line)
+	} else {
+		output.PrintCmdStatus(s.cmd, line+"\n")
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solution
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprintSolution(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"manifest.json":     `{"name": "mysol"}`,
+		"objects/a.json":    `{}`,
+		IgnoreFileName:      "*.log\n",
+		".git/HEAD":         "ref: refs/heads/main\n",
+		"objects/.DS_Store": "",
+	})
+	initial, err := fingerprintSolution(dir)
+	require.NoError(t, err)
+
+	// files that are not packaged don't change the fingerprint
+	writeTestFiles(t, dir, map[string]string{
+		"build.log": "building",
+		".git/HEAD": "ref: refs/heads/other\n",
+		TagFileName: "mytag",
+	})
+	fingerprint, err := fingerprintSolution(dir)
+	require.NoError(t, err)
+	assert.Equal(t, initial, fingerprint)
+
+	// adding, changing or removing a packaged file does
+	writeTestFiles(t, dir, map[string]string{"objects/b.json": `{}`})
+	added, err := fingerprintSolution(dir)
+	require.NoError(t, err)
+	assert.NotEqual(t, initial, added)
+
+	writeTestFiles(t, dir, map[string]string{"objects/b.json": `{"changed": true}`})
+	changed, err := fingerprintSolution(dir)
+	require.NoError(t, err)
+	assert.NotEqual(t, added, changed)
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "objects", "b.json")))
+	removed, err := fingerprintSolution(dir)
+	require.NoError(t, err)
+	assert.Equal(t, initial, removed)
+}
+
+func TestWaitForSolutionChange(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{"manifest.json": `{"name": "mysol"}`, "objects/a.json": `{}`})
+	fingerprint, err := fingerprintSolution(dir)
+	require.NoError(t, err)
+
+	// no change: waits until the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = waitForSolutionChange(ctx, dir, fingerprint, 10*time.Millisecond, 0)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// a series of changes results in a single return, after they stop for the debounce period
+	go func() {
+		for i := 0; i < 3; i++ {
+			time.Sleep(20 * time.Millisecond)
+			_ = os.WriteFile(filepath.Join(dir, "objects", "a.json"), []byte(strings.Repeat(" ", i)+`{}`), 0o644)
+		}
+	}()
+	started := time.Now()
+	changed, err := waitForSolutionChange(context.Background(), dir, fingerprint, 10*time.Millisecond, 200*time.Millisecond)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(started), 200*time.Millisecond)
+	latest, err := fingerprintSolution(dir)
+	require.NoError(t, err)
+	assert.Equal(t, latest, changed)
+}
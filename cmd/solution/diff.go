@@ -0,0 +1,522 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solution
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
+	"github.com/cisco-open/fsoc/config"
+	"github.com/cisco-open/fsoc/output"
+)
+
+var solutionDiffCmd = &cobra.Command{
+	Use:   "diff [FROM] TO",
+	Args:  cobra.RangeArgs(1, 2),
+	Short: "Show the differences between two versions of a solution",
+	Long: `This command compares two versions of a solution, component by component: objects are matched by
+their type and identifying properties (rather than by file), and the changes of modified objects are shown
+as JSON pointers to the changed values.
+
+Each version can be:
+  DIRECTORY                        a local solution directory
+  FILE.zip                         a solution package
+  deployed:NAME[@TAG[/VERSION]]    the solution deployed in the tenant (tag defaults to stable); if a
+                                   version is specified, it must match the deployed version
+
+A local directory is compared as it would be packaged by a push: files excluded by the .fsocignore file (and
+fsoc's own files, such as .fsoclint.yaml) are left out and, if the solution uses pseudo-isolation, it is
+isolated with the tag from the --tag, --stable or --env-file flag, the FSOC_SOLUTION_TAG env var, or the
+solution's .tag or env.json file.
+
+If only one version is specified, it is compared to the solution in the current directory, e.g., to see what
+a push would change.`,
+	Example: `  fsoc solution diff deployed:mysolution
+  fsoc solution diff deployed:mysolution@dev mysolution --tag dev
+  fsoc solution diff mysolution-1.0.0.zip mysolution -o json
+  fsoc solution diff deployed:mysolution@stable/1.2.3 deployed:mysolution@dev`,
+	RunE:             diffSolution,
+	TraverseChildren: true,
+	Annotations: map[string]string{
+		config.AnnotationForConfigBypass: "", // only deployed solutions need a profile
+		output.TableFieldsAnnotation:     `change:.change, type:.type, object:.object, changes:((.changes // []) | map(.path) | join(" "))`,
+	},
+}
+
+func getSolutionDiffCmd() *cobra.Command {
+	addTagFlags(solutionDiffCmd) // --tag and --stable
+
+	solutionDiffCmd.Flags().
+		String("env-file", "", "Path to the env vars json file with pseudo-isolation tag and, optionally, dependency tags (DEPRECATED)")
+
+	solutionDiffCmd.MarkFlagsMutuallyExclusive("tag", "stable", "env-file")
+
+	return solutionDiffCmd
+}
+
+// deployedSolutionPrefix marks a diff argument that refers to a solution deployed in the tenant
+const deployedSolutionPrefix = "deployed:"
+
+// defaultIdentifyingProperties identify the objects of types not defined by the solution,
+// using those of the properties the object has
+var defaultIdentifyingProperties = []string{"/namespace/name", "/kind", "/name", "/id"}
+
+// diffChange is a change of a value within an object
+type diffChange struct {
+	Path string `json:"path"` // JSON pointer to the value
+	Old  any    `json:"old,omitempty"`
+	New  any    `json:"new,omitempty"`
+}
+
+// diffItem is an added, removed or modified solution component
+type diffItem struct {
+	Change  string       `json:"change"` // added, removed or modified
+	Type    string       `json:"type"`   // object type, "knowledge type", "manifest" or "file"
+	Object  string       `json:"object"` // identifying properties of the object, type name or file path
+	File    string       `json:"file"`   // file in the TO version (FROM for removed components)
+	Changes []diffChange `json:"changes,omitempty"`
+}
+
+// diffReport is the result of comparing two versions of a solution
+type diffReport struct {
+	From     string     `json:"from"`
+	To       string     `json:"to"`
+	Items    []diffItem `json:"items"`
+	Total    int        `json:"total"`
+	Added    int        `json:"added"`
+	Removed  int        `json:"removed"`
+	Modified int        `json:"modified"`
+}
+
+// diffComponent is a component of a solution version, the unit of comparison
+type diffComponent struct {
+	Type   string
+	Object string
+	File   string
+	Value  any // generic JSON value
+}
+
+func diffSolution(cmd *cobra.Command, args []string) error {
+	if len(args) == 1 {
+		args = []string{args[0], "."}
+	}
+
+	from, cleanupFrom, err := loadDiffSolution(cmd, args[0])
+	defer cleanupFrom()
+	if err != nil {
+		return err
+	}
+	to, cleanupTo, err := loadDiffSolution(cmd, args[1])
+	defer cleanupTo()
+	if err != nil {
+		return err
+	}
+
+	report, err := diffSolutionContents(from, to)
+	if err != nil {
+		return err
+	}
+	report.From = args[0]
+	report.To = args[1]
+
+	if err := output.PrintCmdOutput(cmd, report); err != nil {
+		return err
+	}
+	output.PrintCmdStatus(cmd, fmt.Sprintf("%d added, %d removed, %d modified\n", report.Added, report.Removed, report.Modified))
+	return nil
+}
+
+// loadDiffSolution reads a solution version specified as a directory, zip file or deployed solution.
+// The returned cleanup function removes the temporary files, if any, and must be called even on error.
+func loadDiffSolution(cmd *cobra.Command, spec string) (*SolutionDirectoryContents, func(), error) {
+	var tempPaths []string
+	cleanup := func() {
+		for _, path := range tempPaths {
+			os.RemoveAll(path)
+		}
+	}
+
+	path := spec
+	name, tag, version, deployed := parseDeployedSolution(spec)
+	if deployed {
+		output.PrintCmdStatus(cmd, fmt.Sprintf("Downloading solution %s with tag %s\n", name, tag))
+		zipPath, err := DownloadSolutionPackage(name, tag, "")
+		if zipPath != "" {
+			tempPaths = append(tempPaths, zipPath)
+		}
+		if err != nil {
+			return nil, cleanup, fmt.Errorf("failed to download solution %q with tag %q: %w", name, tag, err)
+		}
+		path = zipPath
+	} else if !strings.EqualFold(filepath.Ext(path), ".zip") {
+		zipPath, err := packageDiffDirectory(cmd, path)
+		if zipPath != "" {
+			tempPaths = append(tempPaths, zipPath)
+		}
+		if err != nil {
+			return nil, cleanup, err
+		}
+		path = zipPath
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".zip") {
+		dir, err := os.MkdirTemp("", "fsoc-diff-")
+		if err != nil {
+			return nil, cleanup, fmt.Errorf("failed to create a temporary directory: %w", err)
+		}
+		tempPaths = append(tempPaths, dir)
+		archivePath, err := absolutizePath(path)
+		if err != nil {
+			return nil, cleanup, err
+		}
+		if err := UnzipToAferoFs(archivePath, afero.NewBasePathFs(afero.NewOsFs(), dir), 0); err != nil {
+			return nil, cleanup, fmt.Errorf("failed to extract solution archive %q: %w", spec, err)
+		}
+		path = findSolutionRoot(dir)
+	}
+
+	contents, err := NewSolutionDirectoryContentsFromDisk(path)
+	if err != nil {
+		return nil, cleanup, clierror.Wrap(clierror.Validation, fmt.Errorf("failed to read solution %q: %w", spec, err))
+	}
+	if version != "" && contents.Manifest.SolutionVersion != version {
+		return nil, cleanup, clierror.New(clierror.NotFound, "the deployed solution version is %v, not %v", contents.Manifest.SolutionVersion, version)
+	}
+	log.WithFields(log.Fields{"solution": spec, "path": path}).Info("Loaded solution for comparison")
+	return contents, cleanup, nil
+}
+
+// packageDiffDirectory packages a local solution directory into a temporary zip file the way a push
+// would, isolating it if it uses pseudo-isolation. It returns the path of the zip file.
+func packageDiffDirectory(cmd *cobra.Command, dir string) (string, error) {
+	dir, err := absolutizePath(dir)
+	if err != nil {
+		return "", err
+	}
+	manifest, err := getSolutionManifest(dir)
+	if err != nil {
+		return "", clierror.Wrap(clierror.Validation, fmt.Errorf("failed to read solution %q: %w", dir, err))
+	}
+
+	// isolate and package quietly, the temporary files are not of interest
+	quiet := &cobra.Command{}
+	quiet.Flags().AddFlagSet(cmd.Flags())
+	quiet.SetOut(io.Discard)
+	quiet.SetErr(io.Discard)
+	if manifest.HasPseudoIsolation() {
+		isolatedDir, _, err := embeddedConditionalIsolate(quiet, dir)
+		if err != nil {
+			return "", fmt.Errorf("failed to isolate solution %q with tag: %w", dir, err)
+		}
+		if isolatedDir != dir {
+			defer os.RemoveAll(isolatedDir)
+		}
+		dir = isolatedDir
+	}
+	archive, err := createSolutionZip(quiet, dir, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to package solution %q: %w", dir, err)
+	}
+	return archive.Name(), nil
+}
+
+// parseDeployedSolution parses a deployed:NAME[@TAG[/VERSION]] solution reference; it returns
+// false if the spec is not a deployed solution reference
+func parseDeployedSolution(spec string) (name string, tag string, version string, ok bool) {
+	name, ok = strings.CutPrefix(spec, deployedSolutionPrefix)
+	if !ok {
+		return "", "", "", false
+	}
+	tag = "stable"
+	if nameTag := strings.SplitN(name, "@", 2); len(nameTag) == 2 {
+		name = nameTag[0]
+		tag, version, _ = strings.Cut(nameTag[1], "/")
+	}
+	return name, tag, version, true
+}
+
+// findSolutionRoot returns the directory with the solution manifest: the directory itself
+// or, as packaged by fsoc, its only subdirectory
+func findSolutionRoot(dir string) string {
+	if _, err := getSolutionManifest(dir); err == nil {
+		return dir
+	}
+	entries, err := os.ReadDir(dir)
+	if err == nil && len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(dir, entries[0].Name())
+	}
+	return dir
+}
+
+// diffSolutionContents compares two versions of a solution, component by component
+func diffSolutionContents(from, to *SolutionDirectoryContents) (*diffReport, error) {
+	fromComponents, err := getDiffComponents(from)
+	if err != nil {
+		return nil, err
+	}
+	toComponents, err := getDiffComponents(to)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := map[string]bool{}
+	for key := range fromComponents {
+		keys[key] = true
+	}
+	for key := range toComponents {
+		keys[key] = true
+	}
+
+	report := &diffReport{Items: []diffItem{}}
+	for _, key := range sortedKeys(keys) {
+		oldComponent, inFrom := fromComponents[key]
+		newComponent, inTo := toComponents[key]
+		switch {
+		case !inFrom:
+			report.Items = append(report.Items, diffItem{Change: "added", Type: newComponent.Type, Object: newComponent.Object, File: newComponent.File})
+			report.Added++
+		case !inTo:
+			report.Items = append(report.Items, diffItem{Change: "removed", Type: oldComponent.Type, Object: oldComponent.Object, File: oldComponent.File})
+			report.Removed++
+		case newComponent.Type == "file":
+			if oldComponent.Value != newComponent.Value { // other files are compared as a whole
+				report.Items = append(report.Items, diffItem{Change: "modified", Type: newComponent.Type, Object: newComponent.Object, File: newComponent.File})
+				report.Modified++
+			}
+		default:
+			changes := []diffChange{}
+			diffValues("", oldComponent.Value, newComponent.Value, &changes)
+			if len(changes) > 0 {
+				report.Items = append(report.Items, diffItem{Change: "modified", Type: newComponent.Type, Object: newComponent.Object, File: newComponent.File, Changes: changes})
+				report.Modified++
+			}
+		}
+	}
+	report.Total = len(report.Items)
+	return report, nil
+}
+
+// getDiffComponents breaks down a solution into its components: the manifest, the knowledge types,
+// the objects (keyed by type and identifying properties) and any other files (keyed by path)
+func getDiffComponents(s *SolutionDirectoryContents) (map[string]*diffComponent, error) {
+	components := map[string]*diffComponent{}
+	add := func(c *diffComponent) {
+		key := c.Type + " " + c.Object
+		for i := 2; components[key] != nil; i++ {
+			key = fmt.Sprintf("%s %s #%d", c.Type, c.Object, i) // keep duplicates rather than lose them
+		}
+		components[key] = c
+	}
+
+	manifest, err := toDiffValue(s.Manifest)
+	if err != nil {
+		return nil, err
+	}
+	add(&diffComponent{Type: "manifest", Object: s.Manifest.GetSolutionName(), File: "manifest." + s.Manifest.ManifestFormat.String(), Value: manifest})
+
+	// knowledge types first, to get the identifying properties of the solution's types
+	identifyingProperties := map[string][]string{}
+	err = s.WalkFiles(func(file *SolutionFile, dir *SolutionSubDirectory) error {
+		if file.FileKind != KindKnowledgeType {
+			return nil
+		}
+		path := solutionFilePath(file, dir)
+		value, err := parseDiffFile(path, file)
+		if err != nil {
+			return err
+		}
+		typeDef, _ := value.(map[string]any)
+		name, _ := typeDef["name"].(string)
+		fqtn := s.Manifest.GetNamespaceName() + ":" + name
+		if props, ok := typeDef["identifyingProperties"].([]any); ok {
+			for _, prop := range props {
+				if pointer, ok := prop.(string); ok {
+					identifyingProperties[fqtn] = append(identifyingProperties[fqtn], pointer)
+				}
+			}
+		}
+		add(&diffComponent{Type: "knowledge type", Object: fqtn, File: path, Value: value})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.WalkFiles(func(file *SolutionFile, dir *SolutionSubDirectory) error {
+		path := solutionFilePath(file, dir)
+		switch {
+		case file.FileKind == KindKnowledgeType || file.FileKind == KindHidden:
+			return nil
+		case file.FileKind != KindObjectType || file.ObjectType == "":
+			add(&diffComponent{Type: "file", Object: path, File: path, Value: file.Contents.String()})
+			return nil
+		}
+
+		value, err := parseDiffFile(path, file)
+		if err != nil {
+			log.Warnf("Comparing %q as a file: %v", path, err)
+			add(&diffComponent{Type: "file", Object: path, File: path, Value: file.Contents.String()})
+			return nil
+		}
+		objects, isArray := value.([]any)
+		if !isArray {
+			objects = []any{value}
+		}
+		for i, object := range objects {
+			identity := objectIdentity(object, identifyingProperties[file.ObjectType])
+			if identity == "" {
+				identity = path
+				if isArray {
+					identity += "/" + strconv.Itoa(i)
+				}
+			}
+			add(&diffComponent{Type: file.ObjectType, Object: identity, File: path, Value: object})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return components, nil
+}
+
+// parseDiffFile parses a JSON or YAML solution file into a generic JSON value
+func parseDiffFile(path string, file *SolutionFile) (any, error) {
+	value, _, issue := parseSolutionFile(path, file)
+	if issue != nil {
+		return nil, clierror.New(clierror.Validation, "%v: %v", path, issue.Message)
+	}
+	return toDiffValue(value)
+}
+
+// toDiffValue converts a value to its generic JSON form, so that values read from JSON and YAML
+// (e.g., integers) compare equal
+func toDiffValue(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert value to JSON: %w", err)
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("failed to convert value from JSON: %w", err)
+	}
+	return value, nil
+}
+
+// objectIdentity returns the values of the object's identifying properties (JSON pointers), as
+// a comma-separated list of POINTER=VALUE pairs; empty if the object has none of the properties
+func objectIdentity(object any, pointers []string) string {
+	if len(pointers) == 0 {
+		pointers = defaultIdentifyingProperties
+	}
+	var parts []string
+	for _, pointer := range pointers {
+		if value, found := lookupPointer(object, pointer); found {
+			parts = append(parts, fmt.Sprintf("%s=%v", strings.TrimPrefix(pointer, "/"), value))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// lookupPointer returns the value at the JSON pointer (RFC 6901) within a generic JSON value
+func lookupPointer(value any, pointer string) (any, bool) {
+	if pointer == "" {
+		return value, true
+	}
+	unescaper := strings.NewReplacer("~1", "/", "~0", "~")
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = unescaper.Replace(token)
+		switch v := value.(type) {
+		case map[string]any:
+			next, found := v[token]
+			if !found {
+				return nil, false
+			}
+			value = next
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, false
+			}
+			value = v[index]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// diffValues compares two generic JSON values, appending the changes found under the JSON pointer path
+func diffValues(path string, oldValue, newValue any, changes *[]diffChange) {
+	switch oldTyped := oldValue.(type) {
+	case map[string]any:
+		if newTyped, ok := newValue.(map[string]any); ok {
+			keys := map[string]bool{}
+			for key := range oldTyped {
+				keys[key] = true
+			}
+			for key := range newTyped {
+				keys[key] = true
+			}
+			for _, key := range sortedKeys(keys) {
+				oldChild, inOld := oldTyped[key]
+				newChild, inNew := newTyped[key]
+				childPath := path + jsonPointer([]string{key})
+				switch {
+				case !inOld:
+					*changes = append(*changes, diffChange{Path: childPath, New: newChild})
+				case !inNew:
+					*changes = append(*changes, diffChange{Path: childPath, Old: oldChild})
+				default:
+					diffValues(childPath, oldChild, newChild, changes)
+				}
+			}
+			return
+		}
+	case []any:
+		if newTyped, ok := newValue.([]any); ok {
+			for i := 0; i < len(oldTyped) || i < len(newTyped); i++ {
+				childPath := path + "/" + strconv.Itoa(i)
+				switch {
+				case i >= len(oldTyped):
+					*changes = append(*changes, diffChange{Path: childPath, New: newTyped[i]})
+				case i >= len(newTyped):
+					*changes = append(*changes, diffChange{Path: childPath, Old: oldTyped[i]})
+				default:
+					diffValues(childPath, oldTyped[i], newTyped[i], changes)
+				}
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(oldValue, newValue) {
+		if path == "" {
+			path = "/"
+		}
+		*changes = append(*changes, diffChange{Path: path, Old: oldValue, New: newValue})
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solution
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSolutionContents(t *testing.T) {
+	widgetType := `{"name": "widget", "identifyingProperties": ["/id"], "jsonSchema": {"type": "object"}}`
+
+	fromDir := t.TempDir()
+	writeTestFiles(t, fromDir, map[string]string{
+		"manifest.json":     `{"manifestVersion": "1.0.0", "name": "mysol", "solutionVersion": "1.0.0", "dependencies": [], "types": ["types/widget.json"], "objects": [{"type": "mysol:widget", "objectsDir": "widgets"}]}`,
+		"types/widget.json": widgetType,
+		"widgets/all.json":  `[{"id": "a", "size": 1, "tags": ["x"]}, {"id": "b", "size": 2}]`,
+		"README.md":         "hello",
+	})
+	toDir := t.TempDir()
+	writeTestFiles(t, toDir, map[string]string{
+		"manifest.json":     `{"manifestVersion": "1.0.0", "name": "mysol", "solutionVersion": "1.0.1", "dependencies": [], "types": ["types/widget.json"], "objects": [{"type": "mysol:widget", "objectsDir": "widgets"}]}`,
+		"types/widget.json": widgetType,
+		"widgets/a.yaml":    "id: a\nsize: 1\ntags: [x, y]\n", // moved to another file and format
+		"widgets/c.json":    `{"id": "c"}`,
+		"README.md":         "hello, world",
+	})
+
+	from, err := NewSolutionDirectoryContentsFromDisk(fromDir)
+	require.NoError(t, err)
+	to, err := NewSolutionDirectoryContentsFromDisk(toDir)
+	require.NoError(t, err)
+
+	report, err := diffSolutionContents(from, to)
+	require.NoError(t, err)
+	assert.Equal(t, []diffItem{
+		{Change: "modified", Type: "file", Object: "README.md", File: "README.md"},
+		{Change: "modified", Type: "manifest", Object: "mysol", File: "manifest.json", Changes: []diffChange{{Path: "/solutionVersion", Old: "1.0.0", New: "1.0.1"}}},
+		{Change: "modified", Type: "mysol:widget", Object: "id=a", File: "widgets/a.yaml", Changes: []diffChange{{Path: "/tags/1", New: "y"}}},
+		{Change: "removed", Type: "mysol:widget", Object: "id=b", File: "widgets/all.json"},
+		{Change: "added", Type: "mysol:widget", Object: "id=c", File: "widgets/c.json"},
+	}, report.Items)
+	assert.Equal(t, 1, report.Added)
+	assert.Equal(t, 1, report.Removed)
+	assert.Equal(t, 3, report.Modified)
+}
+
+func TestLoadDiffSolutionDirectory(t *testing.T) {
+	solutionDir := filepath.Join(t.TempDir(), "mysol")
+	writeTestFiles(t, solutionDir, map[string]string{
+		"manifest.json":    `{"manifestVersion": "1.0.0", "name": "mysol", "solutionVersion": "1.0.0", "dependencies": []}`,
+		"README.md":        "hello",
+		"build/out.txt":    "build output",
+		IgnoreFileName:     "build/\n",
+		lintConfigFileName: "rules: {}\n",
+	})
+
+	contents, cleanup, err := loadDiffSolution(&cobra.Command{}, solutionDir)
+	defer cleanup()
+	require.NoError(t, err)
+	components, err := getDiffComponents(contents)
+	require.NoError(t, err)
+	keys := []string{}
+	for key := range components {
+		keys = append(keys, key)
+	}
+	assert.ElementsMatch(t, []string{"manifest mysol", "file README.md"}, keys, "ignored and fsoc's own files must not be compared")
+
+	// a pseudo-isolated solution is isolated with the tag
+	writeTestFiles(t, solutionDir, map[string]string{
+		"manifest.json": `{"manifestVersion": "1.0.0", "name": "mysol${$toSuffix(env.tag)}", "solutionVersion": "1.0.0", "dependencies": []}`,
+	})
+	cmd := &cobra.Command{}
+	addTagFlags(cmd)
+	require.NoError(t, cmd.Flags().Set("tag", "dev"))
+	contents, cleanup, err = loadDiffSolution(cmd, solutionDir)
+	defer cleanup()
+	require.NoError(t, err)
+	assert.Equal(t, "mysoldev", contents.Manifest.Name)
+
+	t.Setenv("FSOC_SOLUTION_TAG", "")
+	os.Unsetenv("FSOC_SOLUTION_TAG")
+	_, cleanup, err = loadDiffSolution(&cobra.Command{}, solutionDir)
+	defer cleanup()
+	assert.ErrorContains(t, err, "a tag for pseudo-isolation must be specified")
+}
+
+func TestDiffValues(t *testing.T) {
+	changes := []diffChange{}
+	diffValues("",
+		map[string]any{"a": 1.0, "b": map[string]any{"c/d": "x"}, "e": false},
+		map[string]any{"a": 2.0, "b": map[string]any{"c/d": "y"}, "f": nil},
+		&changes)
+	assert.Equal(t, []diffChange{
+		{Path: "/a", Old: 1.0, New: 2.0},
+		{Path: "/b/c~1d", Old: "x", New: "y"},
+		{Path: "/e", Old: false},
+		{Path: "/f"},
+	}, changes)
+}
+
+func TestParseDeployedSolution(t *testing.T) {
+	tests := []struct {
+		spec, name, tag, version string
+		ok                       bool
+	}{
+		{"deployed:mysol", "mysol", "stable", "", true},
+		{"deployed:mysol@dev", "mysol", "dev", "", true},
+		{"deployed:mysol@stable/1.2.3", "mysol", "stable", "1.2.3", true},
+		{"mysol", "", "", "", false},
+		{"mysol.zip", "", "", "", false},
+	}
+	for _, tt := range tests {
+		name, tag, version, ok := parseDeployedSolution(tt.spec)
+		assert.Equal(t, []any{tt.name, tt.tag, tt.version, tt.ok}, []any{name, tag, version, ok}, tt.spec)
+	}
+}
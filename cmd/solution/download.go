@@ -34,7 +34,7 @@ var solutionDownloadCmd = &cobra.Command{
 	Short:            "Download solution",
 	Long:             `This downloads the indicated solution into the current directory. Also see the "fork" command.`,
 	Example:          `  fsoc solution download spacefleet`,
-	Run:              downloadSolution,
+	RunE:             downloadSolution,
 	TraverseChildren: true,
 	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		config.SetActiveProfile(cmd, args, false)
@@ -50,17 +50,21 @@ func getSolutionDownloadCmd() *cobra.Command {
 	return solutionDownloadCmd
 }
 
-func downloadSolution(cmd *cobra.Command, args []string) {
-	solutionName := getSolutionNameFromArgs(cmd, args, "name")
+func downloadSolution(cmd *cobra.Command, args []string) error {
+	solutionName, err := getSolutionNameFromArgs(cmd, args, "name")
+	if err != nil {
+		return err
+	}
 	solutionTagFlag, _ := cmd.Flags().GetString("tag")
 
 	zipPath, err := DownloadSolutionPackage(solutionName, solutionTagFlag, ".")
 	if err != nil {
-		log.Fatal(err.Error())
+		return err
 	}
 
 	message := fmt.Sprintf("Solution %q with tag %s downloaded successfully to %v.\n", solutionName, solutionTagFlag, zipPath)
 	output.PrintCmdStatus(cmd, message)
+	return nil
 }
 
 // DownloadSolutionPackage downloads the solution package into the specified target path
@@ -70,6 +74,12 @@ func downloadSolution(cmd *cobra.Command, args []string) {
 // - a file: download to that file
 // The function returns the path to the downloaded file and error.
 func DownloadSolutionPackage(name string, tag string, targetPath string) (string, error) {
+	return downloadSolutionPackage(name, tag, targetPath, "")
+}
+
+// downloadSolutionPackage downloads the solution package like DownloadSolutionPackage,
+// from the tenant of the specified profile (empty for the current profile)
+func downloadSolutionPackage(name string, tag string, targetPath string, profile string) (string, error) {
 	// validate name and tag
 	if !IsValidSolutionName(name) {
 		return "", fmt.Errorf("invalid solution name %q", name)
@@ -81,7 +91,11 @@ func DownloadSolutionPackage(name string, tag string, targetPath string) (string
 	// determine the target file path
 	if targetPath != "" {
 		// absolutize path
-		targetPath = absolutizePath(targetPath)
+		var err error
+		targetPath, err = absolutizePath(targetPath)
+		if err != nil {
+			return "", err
+		}
 
 		// if targetPath is an existing directory, place zip there; otherwise, treat as file path
 		fileInfo, err := os.Stat(targetPath)
@@ -94,7 +108,7 @@ func DownloadSolutionPackage(name string, tag string, targetPath string) (string
 		// create unique file name in the temporary directory
 		archive, err := os.CreateTemp("", name+"*.zip") // "*" will be replaced with unique string
 		if err != nil {
-			log.Fatalf("failed to create temporary archive file: %v", err)
+			return "", fmt.Errorf("failed to create temporary archive file: %w", err)
 		}
 		targetPath = archive.Name()
 	}
@@ -104,7 +118,7 @@ func DownloadSolutionPackage(name string, tag string, targetPath string) (string
 		"tag":              tag,
 		"solutionFileName": targetPath,
 	}
-	httpOptions := api.Options{Headers: headers}
+	httpOptions := api.Options{Headers: headers, Profile: profile}
 	bufRes := make([]byte, 0)
 	if err := api.HTTPGet(getSolutionDownloadUrl(name), &bufRes, &httpOptions); err != nil {
 		return "", fmt.Errorf("Solution download command failed: %v", err)
@@ -22,6 +22,8 @@ import (
 
 	"github.com/apex/log"
 	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
 )
 
 // embeddedConditionalIsolate prepares a finalized version of a solution directory
@@ -34,7 +36,10 @@ import (
 // To perform isolation without command dependencies, use isolateSolution().
 func embeddedConditionalIsolate(cmd *cobra.Command, sourceDir string) (string, string, error) {
 	// finalize flags (regardless of isolation)
-	tag, envVarsFile := DetermineTagEnvFile(cmd, sourceDir)
+	tag, envVarsFile, err := determineTagEnvFile(cmd, sourceDir)
+	if err != nil {
+		return "", "", err
+	}
 
 	// don't try to isolate if --no-isolate is specified (ignored if flag not defined)
 	noIsolate, _ := cmd.Flags().GetBool("no-isolate")
@@ -105,17 +110,22 @@ func embeddedConditionalIsolate(cmd *cobra.Command, sourceDir string) (string, s
 // This code duplicates the logic of getEmbeddedTag() to allow support for env files.
 // The function, along with the entire source file, will be removed once the pseudo-isolation support is removed.
 // This function is exported for use by `melt model` when modeling data from pseudo-isolated solutions.
-func DetermineTagEnvFile(cmd *cobra.Command, sourceDir string) (string, string) {
+func DetermineTagEnvFile(cmd *cobra.Command, sourceDir string) (string, string, error) {
+	return determineTagEnvFile(cmd, sourceDir)
+}
+
+// determineTagEnvFile is the implementation of DetermineTagEnvFile
+func determineTagEnvFile(cmd *cobra.Command, sourceDir string) (string, string, error) {
 	// if --tag flag is specified, this overrides everything
 	if cmd.Flags().Changed("tag") {
 		tag, _ := cmd.Flags().GetString("tag")
-		return tag, ""
+		return tag, "", nil
 	}
 
 	// if --stable is specified, treat the same as tag
 	stable, _ := cmd.Flags().GetBool("stable")
 	if stable {
-		return "stable", ""
+		return "stable", "", nil
 	}
 
 	// prepare the env file filename, but don't read it yet
@@ -132,13 +142,13 @@ func DetermineTagEnvFile(cmd *cobra.Command, sourceDir string) (string, string)
 		// if env var with tag is defined, it overrides env file
 		envTag, found := os.LookupEnv("FSOC_SOLUTION_TAG")
 		if found {
-			return envTag, ""
+			return envTag, "", nil
 		}
 
 		tagFile := filepath.Join(sourceDir, TagFileName)
 		tagBytes, err := os.ReadFile(tagFile) // ok if no file or empty file
 		if err == nil {
-			return strings.TrimSpace(string(tagBytes)), ""
+			return strings.TrimSpace(string(tagBytes)), "", nil
 		}
 	}
 
@@ -146,13 +156,12 @@ func DetermineTagEnvFile(cmd *cobra.Command, sourceDir string) (string, string)
 	if envFilePath != "" {
 		_, err := os.Stat(envFilePath)
 		if err == nil {
-			return "", envFilePath
+			return "", envFilePath, nil
 		}
 	}
 	if envFileSpecified {
-		log.Fatalf("Env file %q specified but not found", envFilePath)
+		return "", "", clierror.New(clierror.Usage, "env file %q specified but not found", envFilePath)
 	}
 
-	log.Fatalf("A tag for pseudo-isolation must be specified (--tag, --stable, FSOC_SOLUTION_TAG env var, .tag or env.json file)")
-	return "", "" // should never happen, keep linters happy
+	return "", "", clierror.New(clierror.Usage, "a tag for pseudo-isolation must be specified (--tag, --stable, FSOC_SOLUTION_TAG env var, .tag or env.json file)")
 }
@@ -65,7 +65,7 @@ func getEcpDetails(entity *FmmEntity) *DashuiTemplate {
 	return ecpDetails
 }
 
-func getEcpHome(manifest *Manifest) *DashuiTemplatePropsExtension {
+func getEcpHome(manifest *Manifest) (*DashuiTemplatePropsExtension, error) {
 	namespaceName := manifest.GetNamespaceName()
 	id := fmt.Sprintf("%s:%sEcpHomeExtension", namespaceName, namespaceName)
 	name := "dashui:ecpHome"
@@ -85,7 +85,10 @@ func getEcpHome(manifest *Manifest) *DashuiTemplatePropsExtension {
 	}
 
 	entityRefs := make([]string, 0)
-	fmmEntities := manifest.GetFmmEntities()
+	fmmEntities, err := manifest.GetFmmEntities()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the solution's entity types: %w", err)
+	}
 
 	ecpHomeEntities := make([]*DashuiEcpHomeEntity, 0)
 	for i, entity := range fmmEntities {
@@ -104,7 +107,7 @@ func getEcpHome(manifest *Manifest) *DashuiTemplatePropsExtension {
 
 	ecpHomeTemplateExtension.Props = ecpHome
 
-	return ecpHomeTemplateExtension
+	return ecpHomeTemplateExtension, nil
 }
 
 func getEcpName(entity *FmmEntity) *DashuiTemplate {
@@ -326,7 +329,7 @@ func getDashuiGridTable(entity *FmmEntity) *DashuiTemplate {
 	return gridTable
 }
 
-func getDashuiDetailsList(entity *FmmEntity, manifest *Manifest) *DashuiTemplate {
+func getDashuiDetailsList(entity *FmmEntity, manifest *Manifest) (*DashuiTemplate, error) {
 
 	htmlWidget := NewDashuiHtmlWidget()
 
@@ -342,7 +345,10 @@ func getDashuiDetailsList(entity *FmmEntity, manifest *Manifest) *DashuiTemplate
 
 	elements = append(elements, logsWidget)
 
-	fmmMetrics := manifest.GetFmmMetrics()
+	fmmMetrics, err := manifest.GetFmmMetrics()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the solution's metric types: %w", err)
+	}
 
 	for _, metricRef := range entity.MetricTypes {
 		cardTitle := ""
@@ -386,7 +392,7 @@ func getDashuiDetailsList(entity *FmmEntity, manifest *Manifest) *DashuiTemplate
 		Element: htmlWidget,
 	}
 
-	return detailsList
+	return detailsList, nil
 }
 
 func NewDashuiHtmlWidget() *DashuiHtmlWidget {
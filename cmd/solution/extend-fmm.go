@@ -19,16 +19,19 @@ import (
 	"os"
 	"strings"
 
-	"github.com/apex/log"
 	"github.com/spf13/cobra"
 
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
 	"github.com/cisco-open/fsoc/output"
 )
 
-func getResourceMap(cmd *cobra.Command, entityName string, manifest *Manifest) *FmmResourceMapping {
+func getResourceMap(cmd *cobra.Command, entityName string, manifest *Manifest) (*FmmResourceMapping, error) {
 	var newResoureMapping *FmmResourceMapping
 	namespaceName := manifest.GetNamespaceName()
-	entity := findEntity(entityName, manifest)
+	entity, err := findEntity(entityName, manifest)
+	if err != nil {
+		return nil, err
+	}
 	name := fmt.Sprintf("%s_%s_entity_mapping", namespaceName, entityName)
 	entityType := fmt.Sprintf("%s:%s", namespaceName, entityName)
 	scopeFilterFields := make([]string, 0)
@@ -74,11 +77,14 @@ func getResourceMap(cmd *cobra.Command, entityName string, manifest *Manifest) *
 		AttributeNameMappings: attributeMaps,
 	}
 
-	return newResoureMapping
+	return newResoureMapping, nil
 }
 
-func getAssociationDeclarations(entityName string, manifest *Manifest) []*FmmAssociationDeclaration {
-	entity := findEntity(entityName, manifest)
+func getAssociationDeclarations(entityName string, manifest *Manifest) ([]*FmmAssociationDeclaration, error) {
+	entity, err := findEntity(entityName, manifest)
+	if err != nil {
+		return nil, err
+	}
 	fmmAssocDeclarations := make([]*FmmAssociationDeclaration, 0)
 
 	if entity.AssociationTypes != nil {
@@ -102,7 +108,7 @@ func getAssociationDeclarations(entityName string, manifest *Manifest) []*FmmAss
 		}
 	}
 
-	return fmmAssocDeclarations
+	return fmmAssocDeclarations, nil
 }
 
 func getAssociationDeclaration(entity *FmmEntity, associationType string, toType string) *FmmAssociationDeclaration {
@@ -131,19 +137,17 @@ func getAssociationDeclaration(entity *FmmEntity, associationType string, toType
 	return declaration
 }
 
-func findEntity(entityName string, manifest *Manifest) *FmmEntity {
-	entities := manifest.GetFmmEntities()
-	var entity *FmmEntity
+func findEntity(entityName string, manifest *Manifest) (*FmmEntity, error) {
+	entities, err := manifest.GetFmmEntities()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the solution's entity types: %w", err)
+	}
 	for _, e := range entities {
 		if e.Name == entityName {
-			entity = e
-			break
+			return e, nil
 		}
 	}
-	if entity == nil {
-		log.Fatalf("Couldn't find an entity type named %s", entityName)
-	}
-	return entity
+	return nil, clierror.New(clierror.NotFound, "couldn't find an entity type named %s", entityName)
 }
 
 func getNamespaceComponent(solutionName string) *FmmNamespace {
@@ -292,7 +296,7 @@ func getServiceComponent(serviceName string) *ServiceDef {
 	return serviceComponentDef
 }
 
-func checkCreateSolutionNamespace(cmd *cobra.Command, manifest *Manifest, folderName string) {
+func checkCreateSolutionNamespace(cmd *cobra.Command, manifest *Manifest, folderName string) error {
 	componentType := "fmm:namespace"
 	namespaceName := manifest.GetNamespaceName()
 	var fileFormat string
@@ -309,15 +313,20 @@ func checkCreateSolutionNamespace(cmd *cobra.Command, manifest *Manifest, folder
 	componentDef := manifest.GetComponentDef(componentType)
 
 	if componentDef.Type == "" {
-		addCompDefToManifest(cmd, manifest, componentType, folderName)
+		if err := addCompDefToManifest(cmd, manifest, componentType, folderName); err != nil {
+			return err
+		}
 
 		if _, err := os.Stat(objFilePath); os.IsNotExist(err) {
 			namespaceComp := getNamespaceComponent(namespaceName)
-			createComponentFile(namespaceComp, folderName, fileName)
+			if err := createComponentFile(namespaceComp, folderName, fileName); err != nil {
+				return err
+			}
 			statusMsg := fmt.Sprintf("Added %s file to your solution \n", objFilePath)
 			output.PrintCmdStatus(cmd, statusMsg)
 		}
 
 	}
 
+	return nil
 }
@@ -24,6 +24,7 @@ import (
 	"github.com/apex/log"
 	"github.com/spf13/cobra"
 
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
 	"github.com/cisco-open/fsoc/config"
 	"github.com/cisco-open/fsoc/output"
 )
@@ -34,7 +35,7 @@ var solutionExtendCmd = &cobra.Command{
 	Short:            "Extends your solution by adding new components",
 	Long:             `This command allows you to easily add new components to your solution.`,
 	Example:          `  fsoc solution extend --add-knowledge=dataCollectorConfiguration --add-service=ingestor`,
-	Run:              extendSolution,
+	RunE:             extendSolution,
 	Annotations:      map[string]string{config.AnnotationForConfigBypass: ""}, // this command does not require a valid context
 	TraverseChildren: true,
 }
@@ -79,55 +80,67 @@ func getSolutionExtendCmd() *cobra.Command {
 
 }
 
-func extendSolution(cmd *cobra.Command, args []string) {
+func extendSolution(cmd *cobra.Command, args []string) error {
 	manifest, err := GetManifest(".")
 	if err != nil {
-		log.Fatalf("Failed to read manifest file: %v", err)
+		return fmt.Errorf("failed to read manifest file: %w", err)
 	}
 
 	if cmd.Flags().Changed("add-knowledge") {
 		componentName, _ := cmd.Flags().GetString("add-knowledge")
 		componentName = strings.ToLower(componentName)
 		if strings.Contains(componentName, ":") {
-			log.Fatalf(`":" is a disallowed character. Note that solution name is not required for the add-knowledge flag`)
+			return clierror.New(clierror.Usage, `":" is a disallowed character. Note that solution name is not required for the add-knowledge flag`)
 		}
 		output.PrintCmdStatus(cmd, fmt.Sprintf("Adding %s knowledge type.\n", componentName))
-		addNewKnowledgeComponent(cmd, manifest, getKnowledgeComponent(componentName))
+		if err := addNewKnowledgeComponent(cmd, manifest, getKnowledgeComponent(componentName)); err != nil {
+			return err
+		}
 	}
 
 	if cmd.Flags().Changed("add-service") {
 		componentName, _ := cmd.Flags().GetString("add-service")
 		componentName = strings.ToLower(componentName)
 		folderName := "objects/services"
-		addNewComponent(cmd, manifest, folderName, componentName, "zodiac:function")
+		if err := addNewComponent(cmd, manifest, folderName, componentName, "zodiac:function"); err != nil {
+			return err
+		}
 	}
 
 	if cmd.Flags().Changed("add-entity") {
 		componentName, _ := cmd.Flags().GetString("add-entity")
 		componentName = strings.ToLower(componentName)
 		folderName := "objects/model/entities"
-		addNewComponent(cmd, manifest, folderName, componentName, "fmm:entity")
+		if err := addNewComponent(cmd, manifest, folderName, componentName, "fmm:entity"); err != nil {
+			return err
+		}
 	}
 
 	if cmd.Flags().Changed("add-resourceMapping") {
 		componentName, _ := cmd.Flags().GetString("add-resourceMapping")
 		componentName = strings.ToLower(componentName)
 		folderName := "objects/model/resource-mappings"
-		addNewComponent(cmd, manifest, folderName, componentName, "fmm:resourceMapping")
+		if err := addNewComponent(cmd, manifest, folderName, componentName, "fmm:resourceMapping"); err != nil {
+			return err
+		}
 	}
 
 	if cmd.Flags().Changed("add-associationDeclarations") {
 		componentName, _ := cmd.Flags().GetString("add-associationDeclarations")
 		componentName = strings.ToLower(componentName)
 		folderName := "objects/model/association-declarations"
-		addNewComponent(cmd, manifest, folderName, componentName, "fmm:associationDeclaration")
+		if err := addNewComponent(cmd, manifest, folderName, componentName, "fmm:associationDeclaration"); err != nil {
+			return err
+		}
 	}
 
 	if cmd.Flags().Changed("add-metric") {
 		componentName, _ := cmd.Flags().GetString("add-metric")
 		componentName = strings.ToLower(componentName)
 		folderName := "objects/model/metrics"
-		addNewComponent(cmd, manifest, folderName, componentName, "fmm:metric")
+		if err := addNewComponent(cmd, manifest, folderName, componentName, "fmm:metric"); err != nil {
+			return err
+		}
 	}
 
 	if cmd.Flags().Changed("add-event") {
@@ -135,7 +148,9 @@ func extendSolution(cmd *cobra.Command, args []string) {
 		componentName = strings.ToLower(componentName)
 		folderName := "objects/model/events"
 
-		addNewComponent(cmd, manifest, folderName, componentName, "fmm:event")
+		if err := addNewComponent(cmd, manifest, folderName, componentName, "fmm:event"); err != nil {
+			return err
+		}
 	}
 
 	if cmd.Flags().Changed("add-ecpList") {
@@ -143,7 +158,9 @@ func extendSolution(cmd *cobra.Command, args []string) {
 		entityName := strings.ToLower(componentName)
 		folderName := fmt.Sprintf("objects/dashui/templates/%s", entityName)
 
-		addNewComponent(cmd, manifest, folderName, entityName, "dashui:ecpList")
+		if err := addNewComponent(cmd, manifest, folderName, entityName, "dashui:ecpList"); err != nil {
+			return err
+		}
 	}
 
 	if cmd.Flags().Changed("add-ecpDetails") {
@@ -151,18 +168,22 @@ func extendSolution(cmd *cobra.Command, args []string) {
 		entityName := strings.ToLower(componentName)
 		folderName := fmt.Sprintf("objects/dashui/templates/%s", entityName)
 
-		addNewComponent(cmd, manifest, folderName, entityName, "dashui:ecpDetails")
+		if err := addNewComponent(cmd, manifest, folderName, entityName, "dashui:ecpDetails"); err != nil {
+			return err
+		}
 	}
 
 	if cmd.Flags().Changed("add-ecpHome") {
 		folderName := "objects/dashui/templatePropsExtensions"
 
-		addNewComponent(cmd, manifest, folderName, "ecpHome", "dashui:ecpHome")
+		if err := addNewComponent(cmd, manifest, folderName, "ecpHome", "dashui:ecpHome"); err != nil {
+			return err
+		}
 	}
-
+	return nil
 }
 
-func addNewComponent(cmd *cobra.Command, manifest *Manifest, folderName, componentName, componentType string) {
+func addNewComponent(cmd *cobra.Command, manifest *Manifest, folderName, componentName, componentType string) error {
 	type newComponent struct {
 		Type       string
 		Definition interface{}
@@ -185,7 +206,9 @@ func addNewComponent(cmd *cobra.Command, manifest *Manifest, folderName, compone
 	var newComponents []*newComponent
 	var namespaceName string
 	if strings.Contains(componentType, "fmm") {
-		checkCreateSolutionNamespace(cmd, manifest, "objects/model/namespaces")
+		if err := checkCreateSolutionNamespace(cmd, manifest, "objects/model/namespaces"); err != nil {
+			return err
+		}
 		namespaceName = manifest.GetNamespaceName()
 	}
 
@@ -214,10 +237,14 @@ func addNewComponent(cmd *cobra.Command, manifest *Manifest, folderName, compone
 		{
 			entityName, _ := cmd.Flags().GetString("add-resourceMapping")
 			entityName = strings.ToLower(entityName)
+			resourceMapping, err := getResourceMap(nil, entityName, manifest)
+			if err != nil {
+				return err
+			}
 			entity := &newComponent{
 				Filename:   componentFileName(cmd, manifest, componentName+"-resourceMapping"),
 				Type:       componentType,
-				Definition: getResourceMap(nil, entityName, manifest),
+				Definition: resourceMapping,
 			}
 
 			newComponents = append(newComponents, entity)
@@ -226,10 +253,14 @@ func addNewComponent(cmd *cobra.Command, manifest *Manifest, folderName, compone
 	case "fmm:associationDeclaration":
 		{
 			entityName := strings.ToLower(componentName)
+			declarations, err := getAssociationDeclarations(entityName, manifest)
+			if err != nil {
+				return err
+			}
 			entity := &newComponent{
 				Filename:   componentFileName(cmd, manifest, entityName+"-associationDeclarations"),
 				Type:       componentType,
-				Definition: getAssociationDeclarations(entityName, manifest),
+				Definition: declarations,
 			}
 
 			newComponents = append(newComponents, entity)
@@ -263,8 +294,14 @@ func addNewComponent(cmd *cobra.Command, manifest *Manifest, folderName, compone
 	case "dashui:ecpList":
 		{
 			entityName := strings.ToLower(componentName)
-			entity := findEntity(entityName, manifest)
-			dashuiTemplates := manifest.GetDashuiTemplates()
+			entity, err := findEntity(entityName, manifest)
+			if err != nil {
+				return err
+			}
+			dashuiTemplates, err := manifest.GetDashuiTemplates()
+			if err != nil {
+				return fmt.Errorf("failed to read the solution's dashui templates: %w", err)
+			}
 
 			ecpList := &newComponent{
 				Filename:   componentFileName(cmd, manifest, "ecpList"),
@@ -324,9 +361,15 @@ func addNewComponent(cmd *cobra.Command, manifest *Manifest, folderName, compone
 	case "dashui:ecpDetails":
 		{
 			entityName := strings.ToLower(componentName)
-			entity := findEntity(entityName, manifest)
+			entity, err := findEntity(entityName, manifest)
+			if err != nil {
+				return err
+			}
 
-			dashuiTemplates := manifest.GetDashuiTemplates()
+			dashuiTemplates, err := manifest.GetDashuiTemplates()
+			if err != nil {
+				return fmt.Errorf("failed to read the solution's dashui templates: %w", err)
+			}
 
 			ecpDetails := &newComponent{
 				Filename:   componentFileName(cmd, manifest, "ecpDetails"),
@@ -336,10 +379,14 @@ func addNewComponent(cmd *cobra.Command, manifest *Manifest, folderName, compone
 
 			newComponents = append(newComponents, ecpDetails)
 
+			detailsList, err := getDashuiDetailsList(entity, manifest)
+			if err != nil {
+				return err
+			}
 			ecpDetailsList := &newComponent{
 				Filename:   componentFileName(cmd, manifest, entity.Name+"DetailsList"),
 				Type:       "dashui:template",
-				Definition: getDashuiDetailsList(entity, manifest),
+				Definition: detailsList,
 			}
 
 			newComponents = append(newComponents, ecpDetailsList)
@@ -378,10 +425,14 @@ func addNewComponent(cmd *cobra.Command, manifest *Manifest, folderName, compone
 		}
 	case "dashui:ecpHome":
 		{
+			ecpHomeExtension, err := getEcpHome(manifest)
+			if err != nil {
+				return err
+			}
 			ecpHome := &newComponent{
 				Filename:   componentFileName(cmd, manifest, componentName),
 				Type:       "dashui:templatePropsExtension",
-				Definition: getEcpHome(manifest),
+				Definition: ecpHomeExtension,
 			}
 
 			newComponents = append(newComponents, ecpHome)
@@ -392,12 +443,18 @@ func addNewComponent(cmd *cobra.Command, manifest *Manifest, folderName, compone
 	for _, newObject := range newComponents {
 		checkStructTags(reflect.TypeOf(newObject.Definition))
 
-		addCompDefToManifest(cmd, manifest, newObject.Type, folderName)
-		createComponentFile(newObject.Definition, folderName, newObject.Filename)
+		if err := addCompDefToManifest(cmd, manifest, newObject.Type, folderName); err != nil {
+			return err
+		}
+		if err := createComponentFile(newObject.Definition, folderName, newObject.Filename); err != nil {
+			return err
+		}
 		objFilePath := filepath.Join(folderName, newObject.Filename)
 		statusMsg := fmt.Sprintf("Added file %s to your solution\n", objFilePath)
 		output.PrintCmdStatus(cmd, statusMsg)
 	}
+
+	return nil
 }
 
 func getKnowledgeComponent(name string) *KnowledgeDef {
@@ -442,12 +499,12 @@ func GetManifest(path string) (*Manifest, error) {
 	return getSolutionManifest(path)
 }
 
-func addCompDefToManifest(cmd *cobra.Command, manifest *Manifest, componentType string, folderName string) {
+func addCompDefToManifest(cmd *cobra.Command, manifest *Manifest, componentType string, folderName string) error {
 	componentDefs := manifest.GetComponentDefs(componentType)
 	if len(componentDefs) > 0 {
 		for _, componentDef := range componentDefs {
 			if componentDef.ObjectsDir == folderName {
-				return
+				return nil
 			}
 		}
 	}
@@ -460,12 +517,15 @@ func addCompDefToManifest(cmd *cobra.Command, manifest *Manifest, componentType
 	}
 
 	manifest.Objects = append(manifest.Objects, *extComponentDef)
-	createSolutionManifestFile(".", manifest)
+	if err := saveSolutionManifest(".", manifest); err != nil {
+		return err
+	}
 	statusMsg := fmt.Sprintf("Added new %s definition to the solution manifest \n", componentType)
 	output.PrintCmdStatus(cmd, statusMsg)
+	return nil
 }
 
-func addNewKnowledgeComponent(cmd *cobra.Command, manifest *Manifest, obj *KnowledgeDef) {
+func addNewKnowledgeComponent(cmd *cobra.Command, manifest *Manifest, obj *KnowledgeDef) error {
 	// finalize file format
 	fileFormat := manifest.ManifestFormat
 	if isJSON, _ := cmd.Flags().GetBool("json"); isJSON {
@@ -481,7 +541,7 @@ func addNewKnowledgeComponent(cmd *cobra.Command, manifest *Manifest, obj *Knowl
 
 	// fail if the file already exists, prevent overwriting existing type
 	if _, err := os.Stat(filePath); err == nil {
-		log.Fatalf("Type file %s already exists in the solution. Please use a different type name.", filePath)
+		return clierror.New(clierror.Conflict, "type file %s already exists in the solution. Please use a different type name.", filePath)
 	}
 
 	// add the file if not already in the list
@@ -497,11 +557,16 @@ func addNewKnowledgeComponent(cmd *cobra.Command, manifest *Manifest, obj *Knowl
 	}
 
 	// add type to manifest & create type file
-	createComponentFile(obj, folderName, fileName)
-	createSolutionManifestFile(".", manifest)
+	if err := createComponentFile(obj, folderName, fileName); err != nil {
+		return err
+	}
+	if err := saveSolutionManifest(".", manifest); err != nil {
+		return err
+	}
 
 	statusMsg := fmt.Sprintf("Added knowledge type %s to your solution in %s\n", obj.Name, fileName)
 	output.PrintCmdStatus(cmd, statusMsg)
+	return nil
 }
 
 // componentFileName returns a file name for a component with a file extension reflecting the format.
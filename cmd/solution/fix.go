@@ -36,7 +36,7 @@ var solutionFixCmd = &cobra.Command{
 	2. Fix common issues in the solution (e.g., missing required fields)
 	3. Make common changes and refactoring (e.g., change file format from JSON to YAML)`,
 	Example:     `  fsoc solution fix --manifest-format=yaml --manifest-version --solution-type=module`,
-	Run:         solutionFix,
+	RunE:        solutionFix,
 	Annotations: map[string]string{config.AnnotationForConfigBypass: ""}, // this command does not require a valid context
 }
 
@@ -55,7 +55,7 @@ func getSolutionFixCmd() *cobra.Command {
 	return solutionFixCmd
 }
 
-func solutionFix(cmd *cobra.Command, args []string) {
+func solutionFix(cmd *cobra.Command, args []string) error {
 	// check that at least one fix was requested
 	work := false
 	for _, fix := range availableFixes {
@@ -65,7 +65,7 @@ func solutionFix(cmd *cobra.Command, args []string) {
 		}
 	}
 	if !work {
-		log.Fatal("At least one fix must be requested")
+		return fmt.Errorf("at least one fix must be requested")
 	}
 
 	// collect flags and values
@@ -76,13 +76,13 @@ func solutionFix(cmd *cobra.Command, args []string) {
 	// Read solution manifest
 	manifest, err := GetManifest(".")
 	if err != nil {
-		log.WithError(err).Fatal("Failed to read solution manifest. Is this a solution directory?")
+		return fmt.Errorf("failed to read solution manifest (is this a solution directory?): %w", err)
 	}
 
 	// backup manifest
 	manifestBackupPath, err := backupManifest(manifest)
 	if err != nil {
-		log.WithError(err).Fatal("Failed to back up manifest; canceling fix")
+		return fmt.Errorf("failed to back up manifest; canceling fix: %w", err)
 	}
 	log.WithField("path", manifestBackupPath).Info("Backed up original manifest")
 
@@ -95,7 +95,7 @@ func solutionFix(cmd *cobra.Command, args []string) {
 	// Upgrade manifest version
 	if manifestVersion {
 		if solutionType == "" {
-			log.Fatal("Solution type is required to upgrade manifest version, use the --solution-type flag to specify.")
+			return fmt.Errorf("solution type is required to upgrade manifest version, use the --solution-type flag to specify.")
 		}
 		err := upgradeManifestVersion(cmd, manifest, solutionType)
 		switch {
@@ -105,7 +105,7 @@ func solutionFix(cmd *cobra.Command, args []string) {
 		case errors.Is(err, ErrNoEffect):
 			log.Warn("Manifest version is already up to date for this solution; not changed.")
 		default:
-			log.WithError(err).Fatal("Failed to upgrade manifest version")
+			return fmt.Errorf("failed to upgrade manifest version: %w", err)
 		}
 	}
 
@@ -118,20 +118,20 @@ func solutionFix(cmd *cobra.Command, args []string) {
 		case errors.Is(err, ErrNoEffect):
 			log.Warn("Manifest format is already as requested; not changed.")
 		default:
-			log.WithError(err).Fatal("Failed to change manifest format")
+			return fmt.Errorf("failed to change manifest format: %w", err)
 		}
 	}
 
 	// if no fix was applied, print a message and exit
 	if nFixes == 0 {
 		output.PrintCmdStatus(cmd, "No changes were made to the solution.\n")
-		return
+		return nil
 	}
 
 	// update manifest
 	err = saveSolutionManifest(".", manifest)
 	if err != nil {
-		log.WithError(err).Fatal("Failed to write the updated manifest file")
+		return fmt.Errorf("failed to write the updated manifest file: %w", err)
 	}
 	output.PrintCmdStatus(cmd, fmt.Sprintf("Manifest file manifest.%s updated successfully.\n", manifest.ManifestFormat))
 
@@ -146,6 +146,7 @@ func solutionFix(cmd *cobra.Command, args []string) {
 	}
 
 	output.PrintCmdStatus(cmd, fmt.Sprintf("%v change(s) made to the solution.\n", nFixes))
+	return nil
 }
 
 func upgradeManifestVersion(cmd *cobra.Command, manifest *Manifest, solutionType string) error {
@@ -34,9 +34,11 @@ import (
 // Legacy algorithm to fork a solution using global string replace and assuming json manifest
 // Remove this code, together with the `--legacy-replace` flag, once the new solution is proven to work well
 
-func legacyFork(cmd *cobra.Command, solutionName string, solutionTag string, forkName string, fileSystemRoot afero.Fs, fileSystem afero.Fs) {
+func legacyFork(cmd *cobra.Command, solutionName string, solutionTag string, forkName string, fileSystemRoot afero.Fs, fileSystem afero.Fs) error {
 	// download the solution zip file to the current directory
-	downloadSolutionZip(cmd, solutionName, solutionTag, forkName)
+	if err := downloadSolutionZip(cmd, solutionName, solutionTag, forkName); err != nil {
+		return err
+	}
 
 	message := fmt.Sprintf("Solution %s was successfully downloaded in the this directory.\r\n", solutionName)
 	output.PrintCmdStatus(cmd, message)
@@ -47,23 +49,27 @@ func legacyFork(cmd *cobra.Command, solutionName string, solutionTag string, for
 	// extract files into the newly created solution directory
 	err := extractZip(fileSystemRoot, fileSystem, solutionName)
 	if err != nil {
-		log.Fatalf("Failed to copy files from the zip file to current directory: %v", err)
+		return fmt.Errorf("failed to copy files from the zip file to current directory: %w", err)
 	}
 
 	// global replace of solution name in all files in place
-	editManifest(fileSystem, forkName)
+	if err := editManifest(fileSystem, forkName); err != nil {
+		return err
+	}
 
 	// cleanup the zip file (TODO: move to temp folder and skip cleanup)
 	err = fileSystemRoot.Remove("./" + solutionName + ".zip")
 	if err != nil {
-		log.Fatalf("Failed to remove zip file in current directory: %v", err)
+		return fmt.Errorf("failed to remove zip file in current directory: %w", err)
 	}
+
+	return nil
 }
 
-func editManifest(fileSystem afero.Fs, forkName string) {
+func editManifest(fileSystem afero.Fs, forkName string) error {
 	manifestFile, err := afero.ReadFile(fileSystem, "./manifest.json")
 	if err != nil {
-		log.Fatalf("Error opening manifest file: %v", err)
+		return fmt.Errorf("error opening manifest file: %w", err)
 	}
 
 	var manifest Manifest
@@ -80,13 +86,15 @@ func editManifest(fileSystem afero.Fs, forkName string) {
 
 	f, err := fileSystem.OpenFile("./manifest.json", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
 	if err != nil {
-		log.Fatalf("Can't open manifest file: %v", err)
+		return fmt.Errorf("can't open manifest file: %w", err)
 	}
 	defer f.Close()
 	err = output.WriteJson(manifest, f)
 	if err != nil {
 		log.Errorf("Failed to to write to solution manifest: %v", err)
 	}
+
+	return nil
 }
 
 func refactorSolution(fileSystem afero.Fs, manifest *Manifest, forkName string) error {
@@ -132,11 +140,11 @@ func ReplaceStringInFile(fileSystem afero.Fs, filePath string, searchValue strin
 func extractZip(rootFileSystem afero.Fs, fileSystem afero.Fs, solutionName string) error {
 	zipFile, err := rootFileSystem.OpenFile("./"+solutionName+".zip", os.O_RDONLY, os.FileMode(0644))
 	if err != nil {
-		log.Fatalf("Error opening zip file: %v", err)
+		return fmt.Errorf("error opening zip file: %w", err)
 	}
 	fileInfo, err := rootFileSystem.Stat("./" + solutionName + ".zip")
 	if err != nil {
-		log.Errorf("Err reading zip: %v", err)
+		return fmt.Errorf("error reading zip file: %w", err)
 	}
 	reader, _ := zip.NewReader(zipFile, fileInfo.Size())
 	zipFileSystem := zipfs.New(reader)
@@ -145,7 +153,7 @@ func extractZip(rootFileSystem afero.Fs, fileSystem afero.Fs, solutionName strin
 	return err
 }
 
-func downloadSolutionZip(cmd *cobra.Command, solutionName string, solutionTag string, forkName string) {
+func downloadSolutionZip(cmd *cobra.Command, solutionName string, solutionTag string, forkName string) error {
 	var solutionNameWithZipExtension = solutionName + ".zip"
 
 	headers := map[string]string{
@@ -156,8 +164,10 @@ func downloadSolutionZip(cmd *cobra.Command, solutionName string, solutionTag st
 	httpOptions := api.Options{Headers: headers}
 	bufRes := make([]byte, 0)
 	if err := api.HTTPGet(getSolutionDownloadUrl(solutionName), &bufRes, &httpOptions); err != nil {
-		log.Fatalf("Solution download failed: %v", err)
+		return fmt.Errorf("solution download failed: %w", err)
 	}
+
+	return nil
 }
 
 func ExtractZipToDirectory(archive string, targetFs afero.Fs) error {
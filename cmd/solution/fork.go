@@ -29,6 +29,7 @@ import (
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
 
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
 	"github.com/cisco-open/fsoc/config"
 	"github.com/cisco-open/fsoc/output"
 )
@@ -44,7 +45,7 @@ var solutionForkCmd = &cobra.Command{
 	Long:  `This command downloads the specified solution into the current directory and changes its name to <target-name>`,
 	Example: `  fsoc solution fork spacefleet myfleet
   fsoc solution fork --source-dir=spacefleet myfleet`,
-	Run: solutionForkCommand,
+	RunE: solutionForkCommand,
 	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) >= 1 {
 			return nil, cobra.ShellCompDirectiveDefault
@@ -74,7 +75,7 @@ func GetSolutionForkCommand() *cobra.Command {
 	return solutionForkCmd
 }
 
-func solutionForkCommand(cmd *cobra.Command, args []string) {
+func solutionForkCommand(cmd *cobra.Command, args []string) error {
 	// get and check arguments & flags
 	sourceDir, _ := cmd.Flags().GetString("source-dir")
 	solutionName, _ := cmd.Flags().GetString("source-name")
@@ -83,24 +84,24 @@ func solutionForkCommand(cmd *cobra.Command, args []string) {
 	if len(args) == 2 {
 		if sourceDir != "" {
 			_ = cmd.Help()
-			log.Fatal("Cannot specify both source solution and source directory; use only one.")
+			return fmt.Errorf("cannot specify both source solution and source directory; use only one.")
 		}
 		solutionName, forkName = args[0], args[1]
 	} else if len(args) == 1 && sourceDir != "" {
 		forkName = args[0]
 	} else if len(args) != 0 {
 		_ = cmd.Help()
-		log.Fatal("Incorrect argument syntax.")
+		return fmt.Errorf("incorrect argument syntax.")
 	}
 	if (solutionName == "" && sourceDir == "") || forkName == "" {
 		_ = cmd.Help()
-		log.Fatalf("A source and target must be specified")
+		return fmt.Errorf("a source and target must be specified")
 	}
 	if !IsValidSolutionName(forkName) {
-		log.Fatalf("Invalid solution name %q: must start with a lowercase letter and contain only lowercase letters and digits", forkName)
+		return fmt.Errorf("invalid solution name %q: must start with a lowercase letter and contain only lowercase letters and digits", forkName)
 	}
 	if !IsValidSolutionTag(solutionTag) {
-		log.Fatalf("Invalid solution tag %q: must start with a lowercase letter and contain only lowercase letters and digits", solutionTag)
+		return fmt.Errorf("invalid solution tag %q: must start with a lowercase letter and contain only lowercase letters and digits", solutionTag)
 	}
 
 	// create a status printer function closure, based on the quiet flag
@@ -118,11 +119,15 @@ func solutionForkCommand(cmd *cobra.Command, args []string) {
 	// create afero filesystem for the target directory
 	currentDirectory, err := filepath.Abs(".")
 	if err != nil {
-		log.Fatalf("Error getting current directory: %v", currentDirectory)
+		return fmt.Errorf("error getting current directory: %v", currentDirectory)
 	}
 	fileSystemRoot := afero.NewBasePathFs(afero.NewOsFs(), currentDirectory)
-	if createSolutionDirectoryOk(fileSystemRoot, forkName) { // TODO: use code from init
-		log.Fatalf(fmt.Sprintf("A non empty directory with the name %s already exists", forkName))
+	nonEmpty, err := createSolutionDirectoryOk(fileSystemRoot, forkName)
+	if err != nil {
+		return err
+	}
+	if nonEmpty { // TODO: use code from init
+		return clierror.New(clierror.Conflict, "a non empty directory with the name %s already exists", forkName)
 	}
 	fileSystem := afero.NewBasePathFs(afero.NewOsFs(), currentDirectory+"/"+forkName)
 
@@ -130,10 +135,10 @@ func solutionForkCommand(cmd *cobra.Command, args []string) {
 	if sourceDir != "" {
 		err := forkFromDisk(sourceDir, fileSystem, forkName, statusPrint)
 		if err != nil {
-			log.Fatalf("Failed to fork solution from disk: %v", err)
+			return fmt.Errorf("failed to fork solution from disk: %w", err)
 		}
 		statusPrint("Successfully forked %q into %q.", sourceDir, forkName)
-		return
+		return nil
 	}
 
 	// Download & fork from solution that's already in the platform
@@ -142,21 +147,23 @@ func solutionForkCommand(cmd *cobra.Command, args []string) {
 	// backward compatibility: use the old algorithm that does global string replacement
 	if legacyForkFlag, _ := cmd.Flags().GetBool("legacy-replace"); legacyForkFlag {
 		// use the old algorithm that does global string replacement
-		legacyFork(cmd, solutionName, solutionTag, forkName, fileSystemRoot, fileSystem)
+		if err := legacyFork(cmd, solutionName, solutionTag, forkName, fileSystemRoot, fileSystem); err != nil {
+			return err
+		}
 		statusPrint("Successfully forked %s to current directory as %s using deprecated legacy replace.", solutionName, forkName)
-		return
+		return nil
 	}
 
 	// download solution archive to the temporary directory
 	archivePath, err := DownloadSolutionPackage(solutionName, solutionTag, "")
 	if err != nil {
-		log.Fatalf("Failed to download solution %q with tag %q: %v", solutionName, solutionTag, err)
+		return fmt.Errorf("failed to download solution %q with tag %q: %w", solutionName, solutionTag, err)
 	}
 
 	// create a temp directory to extract files to
 	sourceDir, err = os.MkdirTemp("", solutionName+"."+solutionTag+"-")
 	if err != nil {
-		log.Fatalf("Failed to create a temporary directory: %v", err)
+		return fmt.Errorf("failed to create a temporary directory: %w", err)
 	}
 	log.WithField("temp_solution_dir", sourceDir).Info("Extracting downloaded solution in temp target directory")
 
@@ -164,34 +171,35 @@ func solutionForkCommand(cmd *cobra.Command, args []string) {
 	// Note that archives have a top level directory that should be skipped at extraction)
 	sourceDirFs := afero.NewBasePathFs(afero.NewOsFs(), sourceDir)
 	if err = UnzipToAferoFs(archivePath, sourceDirFs, 1); err != nil {
-		log.Fatalf("Failed to extract downloaded solution archive: %v", err)
+		return fmt.Errorf("failed to extract downloaded solution archive: %w", err)
 	}
 
 	// fork solution from the extracted directory
 	err = forkFromDisk(sourceDir, fileSystem, forkName, statusPrint)
 	if err != nil {
-		log.Fatalf("Failed to fork solution (consider using --legacy-replace flag as a workaround): %v", err)
+		return fmt.Errorf("failed to fork solution (consider using --legacy-replace flag as a workaround): %w", err)
 	}
 
 	statusPrint("Successfully forked %s to current directory as %s.", solutionName, forkName)
+	return nil
 }
 
-func createSolutionDirectoryOk(fileSystem afero.Fs, forkName string) bool {
+func createSolutionDirectoryOk(fileSystem afero.Fs, forkName string) (bool, error) {
 	exists, _ := afero.DirExists(fileSystem, forkName)
 	if exists {
 		empty, _ := afero.IsEmpty(fileSystem, forkName)
-		return !empty
+		return !empty, nil
 	} else {
 		err := fileSystem.Mkdir(forkName, os.ModeDir)
 		if err != nil {
-			log.Fatalf("Failed to create directory in this directory")
+			return false, fmt.Errorf("failed to create directory %q in this directory: %w", forkName, err)
 		}
 		err = os.Chmod(forkName, 0700)
 		if err != nil {
-			log.Fatalf("Failed to set permission on directory")
+			return false, fmt.Errorf("failed to set permission on directory %q: %w", forkName, err)
 		}
 	}
-	return false
+	return false, nil
 }
 
 // -- New style fork
@@ -228,7 +236,7 @@ func forkFromDisk(sourceDir string, fileSystem afero.Fs, solutionName string, st
 		return nil
 	})
 	if err != nil {
-		log.Fatalf("(bug) Failed to remove special files from solution: %v", err)
+		return fmt.Errorf("(bug) failed to remove special files from solution: %w", err)
 	}
 
 	// rename the namespace file if it uses the solution name and update the manifest
@@ -257,14 +265,13 @@ func forkFromDisk(sourceDir string, fileSystem afero.Fs, solutionName string, st
 		return nil
 	})
 	if err != nil {
-		log.Fatalf("(bug) Failed to rename namespace file: %v", err)
+		return fmt.Errorf("(bug) failed to rename namespace file: %w", err)
 	}
 
 	// prepare regexp for replacing solution name in all files
 	oldNameRe, err := regexp.Compile(`\b` + regexp.QuoteMeta(oldName) + `\b`) // \b is a word boundary
 	if err != nil {
-		log.Fatalf("(bug) Failed to compile regexp for solution name replacement: %v", err)
-		panic("") // unreachable
+		return fmt.Errorf("(bug) failed to compile regexp for solution name replacement: %w", err)
 	}
 
 	// modify solution name in all files
@@ -0,0 +1,180 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solution
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// IgnoreFileName is the file in the solution root directory that lists, in gitignore syntax,
+// the files and directories that should not be included in the solution package
+const IgnoreFileName = ".fsocignore"
+
+// ignorePattern is a compiled pattern from the ignore file
+type ignorePattern struct {
+	regex   *regexp.Regexp
+	negate  bool // pattern starts with "!": re-include the matching paths
+	dirOnly bool // pattern ends with "/": match only directories
+}
+
+// ignoreList determines which solution files are excluded from packaging
+type ignoreList struct {
+	patterns []ignorePattern
+}
+
+// defaultIgnorePatterns exclude fsoc's own files in the solution root directory; they can be
+// re-included with a negated pattern in the ignore file
+var defaultIgnorePatterns = []string{"/" + IgnoreFileName, "/" + lintConfigFileName}
+
+// loadIgnoreList reads the ignore file from the solution root directory; if there is no
+// ignore file, only the default patterns apply
+func loadIgnoreList(solutionPath string) (*ignoreList, error) {
+	content, err := os.ReadFile(filepath.Join(solutionPath, IgnoreFileName))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read %v: %w", IgnoreFileName, err)
+	}
+	list, err := parseIgnoreList(strings.Join(defaultIgnorePatterns, "\n") + "\n" + string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %v: %w", IgnoreFileName, err)
+	}
+	return list, nil
+}
+
+// parseIgnoreList parses patterns in gitignore syntax, one per line
+func parseIgnoreList(content string) (*ignoreList, error) {
+	list := &ignoreList{}
+	for lineNo, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if !strings.HasSuffix(line, `\ `) {
+			line = strings.TrimRight(line, " ")
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue // blank line or comment
+		}
+
+		var pattern ignorePattern
+		if strings.HasPrefix(line, "!") {
+			pattern.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			pattern.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+
+		// patterns with a slash (other than at the end) are relative to the solution root,
+		// others match at any level
+		anchored := strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+
+		expr := globToRegex(line)
+		if anchored {
+			expr = "^" + expr + "$"
+		} else {
+			expr = "^(?:.*/)?" + expr + "$"
+		}
+		regex, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern on line %d: %w", lineNo+1, err)
+		}
+		pattern.regex = regex
+		list.patterns = append(list.patterns, pattern)
+	}
+	return list, nil
+}
+
+// globToRegex converts a gitignore glob into a regular expression (without anchors)
+func globToRegex(glob string) string {
+	var expr strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/") && (i == 0 || glob[i-1] == '/'):
+			expr.WriteString("(?:.*/)?") // zero or more directories
+			i += 2
+		case strings.HasPrefix(glob[i:], "/**") && i+3 == len(glob):
+			expr.WriteString("/.*") // everything inside
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case c == '*':
+			expr.WriteString("[^/]*")
+		case c == '?':
+			expr.WriteString("[^/]")
+		case c == '\\' && i+1 < len(glob):
+			i++
+			expr.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				expr.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return expr.String()
+}
+
+// isIgnored returns true if the path (relative to the solution root, with forward slashes) is
+// excluded, either directly or because one of its parent directories is. As with git, files
+// cannot be re-included if their parent directory is excluded.
+func (l *ignoreList) isIgnored(relPath string, isDir bool) bool {
+	if l == nil || len(l.patterns) == 0 {
+		return false
+	}
+	relPath = strings.Trim(filepath.ToSlash(relPath), "/")
+	if relPath == "" || relPath == "." {
+		return false
+	}
+
+	parts := strings.Split(relPath, "/")
+	for i := 1; i < len(parts); i++ {
+		if l.matches(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+	return l.matches(relPath, isDir)
+}
+
+// matches applies the patterns to a single path; the last matching pattern decides
+func (l *ignoreList) matches(relPath string, isDir bool) bool {
+	ignored := false
+	for _, pattern := range l.patterns {
+		if pattern.dirOnly && !isDir {
+			continue
+		}
+		if pattern.regex.MatchString(relPath) {
+			ignored = !pattern.negate
+		}
+	}
+	return ignored
+}
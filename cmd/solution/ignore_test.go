@@ -0,0 +1,64 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solution
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIgnoreList(t *testing.T) {
+	list, err := parseIgnoreList(`
+# comment
+*.log
+build/
+/local.json
+docs/**/*.md
+!keep.log
+tmp?
+`)
+	require.NoError(t, err)
+
+	tests := []struct {
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{"debug.log", false, true},
+		{"objects/debug.log", false, true},
+		{"objects/keep.log", false, false},
+		{"build", true, true},
+		{"build/out.json", false, true},
+		{"objects/build/out.json", false, true},
+		{"build", false, false}, // a file, not a directory
+		{"local.json", false, true},
+		{"objects/local.json", false, false},
+		{"docs/a.md", false, true},
+		{"docs/x/y/b.md", false, true},
+		{"docs/readme.txt", false, false},
+		{"tmp1", false, true},
+		{"tmp12", false, false},
+		{"manifest.yaml", false, false},
+		{".", true, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.ignored, list.isIgnored(tt.path, tt.isDir), "path %q (dir=%v)", tt.path, tt.isDir)
+	}
+
+	var empty *ignoreList
+	assert.False(t, empty.isIgnored("debug.log", false))
+}
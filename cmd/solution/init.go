@@ -30,6 +30,7 @@ import (
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
 	"github.com/cisco-open/fsoc/config"
 	"github.com/cisco-open/fsoc/output"
 )
@@ -47,7 +48,7 @@ a solution manifest. Once the solution is created, the "solution extend" command
 can be used to add types and objects to it.`,
 	Example: `  fsoc solution init mycomponent
   fsoc solution init mymodule --solution-type=module --yaml`,
-	Run:              createNewSolution,
+	RunE:             createNewSolution,
 	Annotations:      map[string]string{config.AnnotationForConfigBypass: ""}, // this command does not require a valid context
 	TraverseChildren: true,
 }
@@ -61,6 +62,8 @@ func getInitSolutionCmd() *cobra.Command {
 	return solutionInitCmd
 }
 
+var solutionNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
 // IsValidSolutionName checks if the solution name is valid
 func IsValidSolutionName(name string) bool {
 	if name == "" {
@@ -70,38 +73,33 @@ func IsValidSolutionName(name string) bool {
 		return false
 	}
 
-	match, err := regexp.Match(`^[a-z][a-z0-9]*$`, []byte(name))
-	if err != nil {
-		log.Fatalf("(bug) Failed to validate solution name %q: %v", name, err)
-	}
-	return match
+	return solutionNameRegexp.MatchString(name)
 }
 
-func createNewSolution(cmd *cobra.Command, args []string) {
+func createNewSolution(cmd *cobra.Command, args []string) error {
 	solutionName := strings.ToLower(args[0])
 	solutionType, _ := cmd.Flags().GetString("solution-type") // checked when creating manifest
 
 	// check solution name for validity / safety for creating a directory (incl. empty name)
-	match, err := regexp.Match(`^[a-z][a-z0-9]*$`, []byte(solutionName))
-	if err != nil {
-		log.Fatalf("Failed to validate solution name %q: %v", solutionName, err)
-	}
-	if !match {
-		log.Fatalf("Invalid solution name %q: must start with a lowercase letter and contain only lowercase letters and digits", solutionName)
+	if !solutionNameRegexp.MatchString(solutionName) {
+		return clierror.New(clierror.Usage, "invalid solution name %q: must start with a lowercase letter and contain only lowercase letters and digits", solutionName)
 	}
 
 	output.PrintCmdStatus(cmd, fmt.Sprintf("Preparing the solution directory structure for %q... \n", solutionName))
 	if err := os.Mkdir(solutionName, os.ModePerm); err != nil {
-		log.Fatalf("Failed to create a new directory %q: %v", solutionName, err)
+		return fmt.Errorf("failed to create a new directory %q: %w", solutionName, err)
 	}
 
 	manifest := createInitialSolutionManifest(solutionName, WithSolutionType(solutionType))
 	if useYaml, _ := cmd.Flags().GetBool("yaml"); useYaml {
 		manifest.ManifestFormat = FileFormatYAML
 	}
-	createSolutionManifestFile(solutionName, manifest)
+	if err := saveSolutionManifest(solutionName, manifest); err != nil {
+		return err
+	}
 
 	output.PrintCmdStatus(cmd, fmt.Sprintf("Solution %q created successfully.\n", solutionName))
+	return nil
 }
 
 // --- Solution Manifest Helpers
@@ -220,18 +218,11 @@ func saveSolutionManifestToAferoFs(fs afero.Fs, manifest *Manifest) error {
 	return nil
 }
 
-// createSolutionManifestFile is a "must succeed" version of saveSolutionManifest
-func createSolutionManifestFile(folderName string, manifest *Manifest) {
-	if err := saveSolutionManifest(folderName, manifest); err != nil {
-		log.Fatalf(err.Error())
-	}
-}
-
-func createComponentFile(compDef any, folderName string, fileName string) {
+func createComponentFile(compDef any, folderName string, fileName string) error {
 	// create directory if it doesn't exist
 	if _, err := os.Stat(folderName); errors.Is(err, os.ErrNotExist) {
 		if err := os.MkdirAll(folderName, os.ModePerm); err != nil {
-			log.Fatalf("Failed to create solution component directory %q: %v", folderName, err)
+			return fmt.Errorf("failed to create solution component directory %q: %w", folderName, err)
 		}
 	}
 
@@ -249,15 +240,17 @@ func createComponentFile(compDef any, folderName string, fileName string) {
 	filepath := filepath.Join(folderName, fileName)
 	svcFile, err := os.Create(filepath)
 	if err != nil {
-		log.Fatalf("Failed to create solution component file %q: %v", filepath, err)
+		return fmt.Errorf("failed to create solution component file %q: %w", filepath, err)
 	}
 	defer svcFile.Close()
 
 	// write the component definition into the file
 	err = writeComponent(compDef, svcFile, format)
 	if err != nil {
-		log.Fatalf("Failed to write the solution component into file %q: %v", filepath, err)
+		return fmt.Errorf("failed to write the solution component into file %q: %w", filepath, err)
 	}
+
+	return nil
 }
 
 func writeComponent(compDef any, w io.Writer, format FileFormat) error {
@@ -281,11 +274,3 @@ func writeComponent(compDef any, w io.Writer, format FileFormat) error {
 
 	return nil
 }
-
-func openFile(filePath string) *os.File {
-	svcFile, err := os.Open(filePath)
-	if err != nil {
-		log.Fatalf("Can't open the file named %q: %v", filePath, err)
-	}
-	return svcFile
-}
@@ -28,6 +28,7 @@ import (
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
 	"github.com/cisco-open/fsoc/config"
 	"github.com/cisco-open/fsoc/output"
 )
@@ -74,7 +75,7 @@ See documentation for manifest syntax and examples (link to be added here).
   fsoc solution isolate --target-file=../mysolution-release.zip --tag=stable
   fsoc solution isolate --source-dir=mysolution --target-dir=mysolution-staging --env-file=staging-env.json
 	`,
-	Run:         solutionIsolateCommand,
+	RunE:        solutionIsolateCommand,
 	Annotations: map[string]string{config.AnnotationForConfigBypass: ""},
 	Deprecated:  "please use `push`, `validate` or `package` instead",
 }
@@ -92,7 +93,7 @@ func getsolutionIsolateCmd() *cobra.Command {
 	return c
 }
 
-func solutionIsolateCommand(cmd *cobra.Command, args []string) {
+func solutionIsolateCommand(cmd *cobra.Command, args []string) error {
 	srcFolder, _ := cmd.Flags().GetString("source-dir")
 	targetFolder, _ := cmd.Flags().GetString("target-dir")
 	targetFile, _ := cmd.Flags().GetString("target-file")
@@ -103,16 +104,17 @@ func solutionIsolateCommand(cmd *cobra.Command, args []string) {
 	}
 	if targetFolder == "" && targetFile == "" {
 		_ = cmd.Usage()
-		log.Fatal("Either <target-dir> or <target-file> must be specified.")
+		return fmt.Errorf("either <target-dir> or <target-file> must be specified")
 	}
 
 	solutionName, _, err := isolateSolution(cmd, srcFolder, targetFolder, targetFile, tag, envVarsFile)
 	if err != nil {
-		log.Fatalf("Failed to isolate solution: %v", err)
+		return fmt.Errorf("failed to isolate solution: %w", err)
 	}
 
 	message := fmt.Sprintf("Successfully created isolated solution %s from %s to %s\n", solutionName, srcFolder, targetFolder+targetFile)
 	output.PrintCmdStatus(cmd, message)
+	return nil
 }
 
 // isolateSolution returns path to directory with isolated artifacts, the tag used and error
@@ -177,7 +179,10 @@ func isolateSolution(cmd *cobra.Command, srcFolder, targetFolder, targetFile, ta
 
 	// create zip if requested
 	if targetFile != "" {
-		zipFile := generateZip(cmd, targetPath, "")
+		zipFile, err := createSolutionZip(cmd, targetPath, "")
+		if err != nil {
+			return "", "", err
+		}
 		err = os.Rename(zipFile.Name(), targetFile)
 		if err != nil {
 			return "", "", fmt.Errorf("failed to rename temp file %q to final file %q: %w", zipFile.Name(), targetFile, err)
@@ -186,7 +191,11 @@ func isolateSolution(cmd *cobra.Command, srcFolder, targetFolder, targetFile, ta
 
 	log.Info("Pseudo-isolation successfully completed")
 
-	return mf.Name, GetPseudoIsolationTag(envVars), nil
+	isolationTag, err := GetPseudoIsolationTag(envVars)
+	if err != nil {
+		return "", "", err
+	}
+	return mf.Name, isolationTag, nil
 }
 
 func prepareForIsolation(srcPath, targetPath, targetFile string, envVars interface{}) error {
@@ -225,12 +234,12 @@ func isolateManifest(cmd *cobra.Command, srcPath, targetPath string, envVars int
 	fileName := "./manifest.json"
 	manifestFile, err := afero.ReadFile(srcFs, fileName)
 	if err != nil {
-		log.Fatalf("Error opening manifest file: %v", err)
+		return nil, fmt.Errorf("error opening manifest file: %w", err)
 	}
 	// evaluate jsonata in manifest
 	manifestFile, err = evaluateJSONata(manifestFile, envVars, fileName)
 	if err != nil {
-		log.Fatalf("Error evaluating expressions in manifest: %v", err)
+		return nil, fmt.Errorf("error evaluating expressions in manifest: %w", err)
 	}
 
 	var manifest Manifest
@@ -250,14 +259,17 @@ func isolateManifest(cmd *cobra.Command, srcPath, targetPath string, envVars int
 }
 
 func isolateFiles(mf *Manifest, srcPath, targetPath, targetFile string, envVars interface{}) error {
-	var err error
+	ignored, err := loadIgnoreList(srcPath)
+	if err != nil {
+		return err
+	}
 	// traverse objects
 	for _, objDef := range mf.Objects {
 		if objDef.ObjectsFile != "" {
 			err = evalAndCopyFile(objDef.ObjectsFile, srcPath, targetPath, envVars)
 		} else {
 			dirPath := filepath.Join(srcPath, objDef.ObjectsDir)
-			err = traverseSolutionFolder(dirPath, mf, srcPath, targetPath, envVars)
+			err = traverseSolutionFolder(dirPath, mf, srcPath, targetPath, envVars, ignored)
 		}
 		if err != nil {
 			return err
@@ -272,13 +284,20 @@ func isolateFiles(mf *Manifest, srcPath, targetPath, targetFile string, envVars
 	return nil
 }
 
-func traverseSolutionFolder(dirPath string, mf *Manifest, srcPath, targetPath string, envVars interface{}) error {
+func traverseSolutionFolder(dirPath string, mf *Manifest, srcPath, targetPath string, envVars interface{}, ignored *ignoreList) error {
 	log.WithField("path", dirPath).Debug("Traversing directory")
 	err := filepath.Walk(dirPath,
 		func(path string, info os.FileInfo, err error) error {
 			// log.Infof("subfolder %v, err: %v", info, err)
+			filePath := strings.Replace(path, srcPath, "", 1)
+			if ignored.isIgnored(filePath, info.IsDir()) {
+				log.WithField("path", filePath).Debug("Skipping ignored path")
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
 			if !info.IsDir() {
-				filePath := strings.Replace(path, srcPath, "", 1)
 				return evalAndCopyFile(filePath, srcPath, targetPath, envVars)
 			}
 			return nil
@@ -317,16 +336,15 @@ func LoadEnvVars(cmd *cobra.Command, tag, envVarsFile string) (interface{}, erro
 	return envVars, nil
 }
 
-func GetPseudoIsolationTag(envVars interface{}) string {
+func GetPseudoIsolationTag(envVars interface{}) (string, error) {
 	if root, ok := envVars.(map[string]any); ok {
 		if env, ok := root["env"].(map[string]any); ok {
 			if tag, ok := env["tag"].(string); ok && tag != "" {
-				return tag
+				return tag, nil
 			}
 		}
 	}
-	log.Fatalf(`Failed to extract tag from env vars. Minmum required is --tag flag or {"env":{"tag":"<tagvalue>""}} env.json file`)
-	return "" // can never get here, just keep compiler happy
+	return "", clierror.New(clierror.Usage, `failed to extract tag from env vars. Minmum required is --tag flag or {"env":{"tag":"<tagvalue>""}} env.json file`)
 }
 
 func evalAndCopyFile(fileName, srcPath, targetPath string, envVars interface{}) error {
@@ -0,0 +1,619 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solution
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
+	"github.com/cisco-open/fsoc/config"
+	"github.com/cisco-open/fsoc/output"
+)
+
+var solutionLintCmd = &cobra.Command{
+	Use:   "lint",
+	Args:  cobra.ExactArgs(0),
+	Short: "Check the references between your solution's components",
+	Long: `This command checks the cross-references between the solution's components, catching problems
+that pass schema validation (see "solution check") but fail when the solution is uploaded.
+
+The following rules are checked:
+  invalid-file             a knowledge type or component file can't be read or parsed; the objects in it
+                           are not checked
+  namespace-mismatch       an FMM type is not defined in the solution's namespace, or a resource mapping
+                           maps an entity of another namespace
+  undefined-attribute      an entity's required or optimized attribute, or a resource mapping's target
+                           attribute, is not defined in the entity's attributes
+  unknown-entity-type      a resource mapping, association, dashui template or entity refers to an entity
+                           type that the solution doesn't define
+  unknown-metric-type      an entity lists a metric type that the solution doesn't define
+  unknown-event-type       an entity lists an event type that the solution doesn't define
+  unknown-knowledge-type   the manifest lists objects of a type that the solution doesn't define
+  missing-dependency       a type of another solution is used, but that solution is not in the manifest's
+                           dependencies
+  duplicate-definition     a type is defined more than once
+
+Rules can be turned off, their severity changed and specific findings ignored in a .fsoclint.yaml file in the
+solution's root directory, next to the manifest:
+
+  rules:
+    missing-dependency: warning   # off, warning or error
+  ignore:
+    - rule: unknown-entity-type
+      object: "dashui:template legacy*"   # glob; all objects if omitted
+
+The command fails (exit code 5) if any errors are found, or warnings with --strict.`,
+	Example: `  fsoc solution lint
+  fsoc solution lint -d mysolution --strict
+  fsoc solution lint -o json`,
+	RunE:             lintSolution,
+	TraverseChildren: true,
+	Annotations: map[string]string{
+		config.AnnotationForConfigBypass: "", // works offline, without a profile
+		output.TableFieldsAnnotation:     "rule:.rule, severity:.severity, object:.object, message:.message",
+	},
+}
+
+// lintConfigFileName is the name of the lint configuration file, in the solution root directory
+const lintConfigFileName = ".fsoclint.yaml"
+
+func getSolutionLintCmd() *cobra.Command {
+	solutionLintCmd.Flags().
+		StringP("directory", "d", "", "Path to the solution root directory (defaults to current dir)")
+
+	solutionLintCmd.Flags().
+		Bool("strict", false, "Fail on warnings")
+
+	return solutionLintCmd
+}
+
+// lintIssue is a problem found by a lint rule
+type lintIssue struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Object   string `json:"object"` // the object with the problem, as "TYPE NAME"
+	Message  string `json:"message"`
+}
+
+// lintReport is the result of linting a solution
+type lintReport struct {
+	Items      []lintIssue `json:"items"`
+	Total      int         `json:"total"`
+	Errors     int         `json:"errors"`
+	Warnings   int         `json:"warnings"`
+	Suppressed int         `json:"suppressed"` // findings ignored or turned off in the lint configuration
+}
+
+// lintConfig is the content of the lint configuration file
+type lintConfig struct {
+	Rules  map[string]string `yaml:"rules"` // rule ID -> off, warning or error
+	Ignore []struct {
+		Rule   string `yaml:"rule"`
+		Object string `yaml:"object"` // glob, matches all objects if empty
+	} `yaml:"ignore"`
+}
+
+// lintRule is a check of the solution's symbol table
+type lintRule struct {
+	id       string
+	severity string // default severity
+	check    func(symbols *lintSymbols, report func(object string, format string, a ...any))
+}
+
+var lintRules = []lintRule{
+	{"invalid-file", severityError, lintInvalidFiles},
+	{"namespace-mismatch", severityError, lintNamespaces},
+	{"undefined-attribute", severityError, lintAttributes},
+	{"unknown-entity-type", severityError, lintReferences("entity type")},
+	{"unknown-metric-type", severityError, lintReferences("metric type")},
+	{"unknown-event-type", severityError, lintReferences("event type")},
+	{"unknown-knowledge-type", severityError, lintReferences("knowledge type")},
+	{"missing-dependency", severityError, lintDependencies},
+	{"duplicate-definition", severityError, lintDuplicates},
+}
+
+// lintReference is a reference from a component to a type
+type lintReference struct {
+	object   string // the referring object
+	kind     string // entity type, metric type, event type or knowledge type
+	typeName string // NAMESPACE:NAME
+}
+
+// lintSymbols is the symbol table of a solution: the types it defines and the references between them
+type lintSymbols struct {
+	manifest         *Manifest
+	entities         map[string]*FmmEntity
+	metrics          map[string]*FmmMetric
+	events           map[string]*FmmEvent
+	knowledgeTypes   map[string]*KnowledgeDef
+	resourceMappings []*FmmResourceMapping
+	associations     []*FmmAssociationDeclaration
+	templates        []*DashuiTemplate
+	fmmObjects       map[string]*FmmTypeDef // all FMM objects, by their "TYPE NAME" label
+	references       []lintReference
+	duplicates       []string // labels of types defined more than once
+	invalidFiles     []lintInvalidFile
+}
+
+// lintInvalidFile is a file that can't be read or parsed
+type lintInvalidFile struct {
+	object string // the type of the objects in the file
+	err    error
+}
+
+func lintSolution(cmd *cobra.Command, args []string) error {
+	directory, _ := cmd.Flags().GetString("directory")
+	if directory == "" {
+		directory = "."
+	}
+	strict, _ := cmd.Flags().GetBool("strict")
+
+	report, err := lintSolutionDirectory(directory)
+	if err != nil {
+		return err
+	}
+
+	if err := output.PrintCmdOutput(cmd, report); err != nil {
+		return err
+	}
+
+	summary := fmt.Sprintf("Found %d error(s), %d warning(s); %d finding(s) suppressed\n", report.Errors, report.Warnings, report.Suppressed)
+	if report.Errors > 0 || (strict && report.Warnings > 0) {
+		return clierror.New(clierror.Validation, "solution lint failed. %v", strings.TrimSpace(summary))
+	}
+	output.PrintCmdStatus(cmd, summary)
+	return nil
+}
+
+// lintSolutionDirectory builds the symbol table of the solution in the directory and runs the lint rules
+func lintSolutionDirectory(directory string) (report *lintReport, err error) {
+	directory, err = absolutizePath(directory)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := getSolutionManifest(directory)
+	if err != nil {
+		return nil, clierror.Wrap(clierror.Validation, err)
+	}
+	lintCfg, err := readLintConfig(filepath.Join(directory, lintConfigFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	// the component files are referenced relative to the solution root directory
+	fsocWorkingDir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get the current working directory: %w", err)
+	}
+	if err := os.Chdir(directory); err != nil {
+		return nil, fmt.Errorf("couldn't switch working directory to the solution root directory %q: %w", directory, err)
+	}
+	defer func() {
+		if chdirErr := os.Chdir(fsocWorkingDir); chdirErr != nil && err == nil {
+			err = fmt.Errorf("couldn't switch working directory back to starting working directory: %w", chdirErr)
+		}
+	}()
+
+	return runLintRules(newLintSymbols(manifest), lintCfg), nil
+}
+
+// readLintConfig reads the lint configuration file; a missing file is the same as an empty one
+func readLintConfig(fileName string) (*lintConfig, error) {
+	lintCfg := &lintConfig{}
+	data, err := os.ReadFile(fileName)
+	if os.IsNotExist(err) {
+		return lintCfg, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read the lint configuration: %w", err)
+	}
+	if err := yaml.Unmarshal(data, lintCfg); err != nil {
+		return nil, clierror.New(clierror.Validation, "failed to parse the lint configuration %q: %v", fileName, err)
+	}
+
+	for id, severity := range lintCfg.Rules {
+		if !isLintRule(id) {
+			return nil, clierror.New(clierror.Validation, "unknown rule %q in the lint configuration %q", id, fileName)
+		}
+		if !slices.Contains([]string{"off", severityWarning, severityError}, severity) {
+			return nil, clierror.New(clierror.Validation, "invalid severity %q for rule %q in the lint configuration %q; valid values are off, warning and error", severity, id, fileName)
+		}
+	}
+	for _, ignore := range lintCfg.Ignore {
+		if !isLintRule(ignore.Rule) {
+			return nil, clierror.New(clierror.Validation, "unknown rule %q in the lint configuration %q", ignore.Rule, fileName)
+		}
+		if _, err := path.Match(ignore.Object, ""); err != nil {
+			return nil, clierror.New(clierror.Validation, "invalid object pattern %q in the lint configuration %q: %v", ignore.Object, fileName, err)
+		}
+	}
+
+	return lintCfg, nil
+}
+
+func isLintRule(id string) bool {
+	return slices.ContainsFunc(lintRules, func(rule lintRule) bool { return rule.id == id })
+}
+
+// runLintRules runs the rules against the symbol table, applying the configured severities and suppressions
+func runLintRules(symbols *lintSymbols, lintCfg *lintConfig) *lintReport {
+	report := &lintReport{Items: []lintIssue{}}
+	for _, rule := range lintRules {
+		severity := rule.severity
+		if configured, found := lintCfg.Rules[rule.id]; found {
+			severity = configured
+		}
+		rule.check(symbols, func(object string, format string, a ...any) {
+			if severity == "off" || lintCfg.ignores(rule.id, object) {
+				report.Suppressed++
+				return
+			}
+			report.Items = append(report.Items, lintIssue{Rule: rule.id, Severity: severity, Object: object, Message: fmt.Sprintf(format, a...)})
+			if severity == severityError {
+				report.Errors++
+			} else {
+				report.Warnings++
+			}
+		})
+	}
+	report.Total = len(report.Items)
+	return report
+}
+
+// ignores returns true if the finding of the rule for the object is ignored
+func (c *lintConfig) ignores(rule string, object string) bool {
+	for _, ignore := range c.Ignore {
+		if ignore.Rule != rule {
+			continue
+		}
+		if matched, _ := path.Match(ignore.Object, object); ignore.Object == "" || matched {
+			return true
+		}
+	}
+	return false
+}
+
+// newLintSymbols builds the symbol table from the solution's components. It must be called with the
+// solution root as the current directory.
+func newLintSymbols(manifest *Manifest) *lintSymbols {
+	symbols := &lintSymbols{
+		manifest:       manifest,
+		entities:       map[string]*FmmEntity{},
+		metrics:        map[string]*FmmMetric{},
+		events:         map[string]*FmmEvent{},
+		knowledgeTypes: map[string]*KnowledgeDef{},
+		fmmObjects:     map[string]*FmmTypeDef{},
+	}
+
+	// components; the files that can't be read or parsed are reported by the invalid-file rule
+	var err error
+	symbols.resourceMappings, err = manifest.GetFmmResourceMappings()
+	symbols.addInvalidFiles("fmm:resourceMapping", err)
+	symbols.associations, err = manifest.GetFmmAssociationDeclarations()
+	symbols.addInvalidFiles("fmm:associationDeclaration", err)
+	symbols.templates, err = manifest.GetDashuiTemplates()
+	symbols.addInvalidFiles("dashui:template", err)
+	entities, err := manifest.GetFmmEntities()
+	symbols.addInvalidFiles("fmm:entity", err)
+	metrics, err := manifest.GetFmmMetrics()
+	symbols.addInvalidFiles("fmm:metric", err)
+	events, err := manifest.GetFmmEvents()
+	symbols.addInvalidFiles("fmm:event", err)
+
+	// definitions
+	for _, typeFile := range manifest.Types {
+		data, err := os.ReadFile(typeFile)
+		if err != nil {
+			symbols.addInvalidFiles("knowledge type", fmt.Errorf("can't read the %q file: %w", typeFile, err))
+			continue
+		}
+		var typeDef KnowledgeDef
+		if err := yaml.Unmarshal(data, &typeDef); err != nil {
+			symbols.addInvalidFiles("knowledge type", fmt.Errorf("can't parse a knowledge type from the %q file: %w", typeFile, err))
+			continue
+		}
+		typeName := manifest.GetNamespaceName() + ":" + typeDef.Name
+		if _, found := symbols.knowledgeTypes[typeName]; found {
+			symbols.duplicates = append(symbols.duplicates, "knowledge type "+typeName)
+		}
+		symbols.knowledgeTypes[typeName] = &typeDef
+	}
+	for _, entity := range entities {
+		label := symbols.addFmmObject("fmm:entity", entity.FmmTypeDef)
+		symbols.entities[fmmTypeName(entity.FmmTypeDef)] = entity
+		symbols.addEntityReferences(label, entity)
+	}
+	for _, metric := range metrics {
+		symbols.addFmmObject("fmm:metric", metric.FmmTypeDef)
+		symbols.metrics[fmmTypeName(metric.FmmTypeDef)] = metric
+	}
+	for _, event := range events {
+		symbols.addFmmObject("fmm:event", event.FmmTypeDef)
+		symbols.events[fmmTypeName(event.FmmTypeDef)] = event
+	}
+
+	// references
+	for _, mapping := range symbols.resourceMappings {
+		label := symbols.addFmmObject("fmm:resourceMapping", mapping.FmmTypeDef)
+		symbols.addReference(label, "entity type", mapping.EntityType)
+	}
+	for _, association := range symbols.associations {
+		label := symbols.addFmmObject("fmm:associationDeclaration", association.FmmTypeDef)
+		symbols.addReference(label, "entity type", association.FromType)
+		symbols.addReference(label, "entity type", association.ToType)
+	}
+	for _, template := range symbols.templates {
+		if template == nil {
+			continue
+		}
+		label := "dashui:template " + template.Name
+		if strings.Contains(template.Target, ":") && !strings.Contains(template.Target, "*") {
+			symbols.addReference(label, "entity type", template.Target)
+		}
+		for _, entityType := range template.RequiredEntityTypes {
+			symbols.addReference(label, "entity type", entityType)
+		}
+	}
+	for _, objectDef := range manifest.Objects {
+		symbols.addReference("manifest", "knowledge type", objectDef.Type)
+	}
+
+	return symbols
+}
+
+// addInvalidFiles records the files that failed to load, given the (possibly joined) error of loading the objects
+func (s *lintSymbols) addInvalidFiles(object string, err error) {
+	if err == nil {
+		return
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, err := range joined.Unwrap() {
+			s.addInvalidFiles(object, err)
+		}
+		return
+	}
+	s.invalidFiles = append(s.invalidFiles, lintInvalidFile{object: object, err: err})
+}
+
+// addFmmObject registers an FMM object and returns its label
+func (s *lintSymbols) addFmmObject(objectType string, typeDef *FmmTypeDef) string {
+	if typeDef == nil {
+		typeDef = &FmmTypeDef{}
+	}
+	label := objectType + " " + fmmTypeName(typeDef)
+	if _, found := s.fmmObjects[label]; found {
+		s.duplicates = append(s.duplicates, label)
+	}
+	s.fmmObjects[label] = typeDef
+	return label
+}
+
+func (s *lintSymbols) addReference(object string, kind string, typeName string) {
+	if typeName == "" {
+		return
+	}
+	s.references = append(s.references, lintReference{object: object, kind: kind, typeName: typeName})
+}
+
+func (s *lintSymbols) addEntityReferences(label string, entity *FmmEntity) {
+	for _, metricType := range entity.MetricTypes {
+		s.addReference(label, "metric type", metricType)
+	}
+	for _, eventType := range entity.EventTypes {
+		s.addReference(label, "event type", eventType)
+	}
+	if associations := entity.AssociationTypes; associations != nil {
+		for _, toTypes := range [][]string{associations.Aggregates_of, associations.Consists_of, associations.Is_a,
+			associations.Has, associations.Relates_to, associations.Uses} {
+			for _, toType := range toTypes {
+				s.addReference(label, "entity type", toType)
+			}
+		}
+	}
+}
+
+// isOwnNamespace returns true if the namespace is the solution's own (possibly pseudo-isolated) namespace
+func (s *lintSymbols) isOwnNamespace(namespace string) bool {
+	return namespace == s.manifest.Name || namespace == s.manifest.GetNamespaceName() || namespace == s.manifest.GetSolutionName()
+}
+
+// isDefined returns true if the solution defines the type of the specified kind
+func (s *lintSymbols) isDefined(kind string, typeName string) bool {
+	found := false
+	switch kind {
+	case "entity type":
+		_, found = s.entities[typeName]
+	case "metric type":
+		_, found = s.metrics[typeName]
+	case "event type":
+		_, found = s.events[typeName]
+	case "knowledge type":
+		_, found = s.knowledgeTypes[typeName]
+	}
+	return found
+}
+
+func fmmTypeName(typeDef *FmmTypeDef) string {
+	namespace := ""
+	if typeDef.Namespace != nil {
+		namespace = typeDef.Namespace.Name
+	}
+	return namespace + ":" + typeDef.Name
+}
+
+// splitTypeName splits a NAMESPACE:NAME type name
+func splitTypeName(typeName string) (namespace string, name string) {
+	index := strings.LastIndex(typeName, ":")
+	if index < 0 {
+		return "", typeName
+	}
+	return typeName[:index], typeName[index+1:]
+}
+
+var dependencyNamespaceRegex = regexp.MustCompile(`^\$\{\$dependency\('([^']+)'\)\}$`)
+
+// lintNamespaces checks that the solution's FMM types are in its namespace and that its
+// resource mappings map its own entities
+func lintNamespaces(s *lintSymbols, report func(object string, format string, a ...any)) {
+	for _, label := range sortedKeys(s.fmmObjects) {
+		typeDef := s.fmmObjects[label]
+		if typeDef.Namespace == nil || typeDef.Namespace.Name == "" {
+			report(label, "no namespace is specified; expected %q", s.manifest.GetNamespaceName())
+		} else if !s.isOwnNamespace(typeDef.Namespace.Name) {
+			report(label, "namespace %q doesn't match the solution's namespace %q", typeDef.Namespace.Name, s.manifest.GetNamespaceName())
+		}
+	}
+	for _, mapping := range s.resourceMappings {
+		namespace, _ := splitTypeName(mapping.EntityType)
+		if mapping.EntityType != "" && !s.isOwnNamespace(namespace) {
+			report("fmm:resourceMapping "+fmmTypeName(mapping.FmmTypeDef), "maps entity type %q, which is not in the solution's namespace %q", mapping.EntityType, s.manifest.GetNamespaceName())
+		}
+	}
+}
+
+// lintAttributes checks that the attributes referenced by entities and resource mappings are defined
+func lintAttributes(s *lintSymbols, report func(object string, format string, a ...any)) {
+	for _, typeName := range sortedKeys(s.entities) {
+		entity := s.entities[typeName]
+		if entity.AttributeDefinitions == nil {
+			continue
+		}
+		label := "fmm:entity " + typeName
+		for _, attribute := range entity.AttributeDefinitions.Required {
+			if !hasAttribute(entity, attribute) {
+				report(label, "required attribute %q is not defined in the entity's attributes", attribute)
+			}
+		}
+		if entity.AttributeDefinitions.FmmAttributeDefinitionsTypeDef != nil {
+			for _, attribute := range entity.AttributeDefinitions.Optimized {
+				if !hasAttribute(entity, attribute) {
+					report(label, "optimized attribute %q is not defined in the entity's attributes", attribute)
+				}
+			}
+		}
+	}
+
+	for _, mapping := range s.resourceMappings {
+		entity, found := s.entities[mapping.EntityType]
+		if !found {
+			continue // reported by unknown-entity-type, if in the solution's namespace
+		}
+		label := "fmm:resourceMapping " + fmmTypeName(mapping.FmmTypeDef)
+		for _, attribute := range sortedKeys(mapping.AttributeNameMappings) {
+			if !hasAttribute(entity, attribute) {
+				report(label, "attribute %q is not defined in entity type %q", attribute, mapping.EntityType)
+			}
+		}
+		for _, m := range mapping.Mappings {
+			if m.To != "" && !hasAttribute(entity, m.To) {
+				report(label, "mapping target attribute %q is not defined in entity type %q", m.To, mapping.EntityType)
+			}
+		}
+	}
+}
+
+// hasAttribute returns true if the entity defines the attribute, by its short or fully qualified
+// (NAMESPACE.ENTITY.ATTRIBUTE) name
+func hasAttribute(entity *FmmEntity, attribute string) bool {
+	if entity.AttributeDefinitions == nil || entity.AttributeDefinitions.FmmAttributeDefinitionsTypeDef == nil {
+		return false
+	}
+	attributes := entity.AttributeDefinitions.Attributes
+	if _, found := attributes[attribute]; found {
+		return true
+	}
+	namespace := ""
+	if entity.Namespace != nil {
+		namespace = entity.Namespace.Name
+	}
+	prefix := fmt.Sprintf("%s.%s.", namespace, entity.Name)
+	if _, found := attributes[strings.TrimPrefix(attribute, prefix)]; found {
+		return true
+	}
+	_, found := attributes[prefix+attribute]
+	return found
+}
+
+// lintReferences returns a rule that checks that the references to the solution's own types
+// of the specified kind are defined
+func lintReferences(kind string) func(*lintSymbols, func(string, string, ...any)) {
+	return func(s *lintSymbols, report func(object string, format string, a ...any)) {
+		for _, ref := range s.references {
+			if ref.kind != kind {
+				continue
+			}
+			namespace, _ := splitTypeName(ref.typeName)
+			if s.isOwnNamespace(namespace) && !s.isDefined(kind, ref.typeName) {
+				report(ref.object, "%s %q is not defined in the solution", kind, ref.typeName)
+			}
+		}
+	}
+}
+
+// lintDependencies checks that the solutions whose types are referenced are listed as dependencies
+func lintDependencies(s *lintSymbols, report func(object string, format string, a ...any)) {
+	reported := map[string]bool{}
+	for _, ref := range s.references {
+		namespace, _ := splitTypeName(ref.typeName)
+		if namespace == "" || s.isOwnNamespace(namespace) {
+			continue
+		}
+		dependency := namespace
+		if match := dependencyNamespaceRegex.FindStringSubmatch(namespace); match != nil {
+			dependency = match[1]
+		}
+		isolatedDependency := fmt.Sprintf("${$dependency('%s')}", dependency)
+		if s.manifest.CheckDependencyExists(dependency) || s.manifest.CheckDependencyExists(isolatedDependency) {
+			continue
+		}
+		if reported[ref.object+" "+dependency] {
+			continue
+		}
+		reported[ref.object+" "+dependency] = true
+		report(ref.object, "%s %q belongs to solution %q, which is not listed in the manifest's dependencies", ref.kind, ref.typeName, dependency)
+	}
+}
+
+// lintInvalidFiles reports the files that can't be read or parsed
+func lintInvalidFiles(s *lintSymbols, report func(object string, format string, a ...any)) {
+	for _, file := range s.invalidFiles {
+		report(file.object, "%v", file.err)
+	}
+}
+
+// lintDuplicates checks that each type is defined once
+func lintDuplicates(s *lintSymbols, report func(object string, format string, a ...any)) {
+	for _, label := range s.duplicates {
+		report(label, "the type is defined more than once")
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,156 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solution
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintSolution(t *testing.T) {
+	solutionDir := t.TempDir()
+	writeTestFiles(t, solutionDir, map[string]string{
+		"manifest.yaml": `manifestVersion: "1.0.0"
+name: mysol
+solutionVersion: 1.0.0
+dependencies: [fmm]
+objects:
+  - type: fmm:entity
+    objectsDir: entities
+  - type: fmm:metric
+    objectsDir: metrics
+  - type: fmm:resourceMapping
+    objectsDir: mappings
+  - type: dashui:template
+    objectsDir: templates
+  - type: mysol:widget
+    objectsDir: widgets
+`,
+		"entities/host.yaml": `namespace: {name: mysol, version: 1}
+kind: entity
+name: host
+attributeDefinitions:
+  required: [name, ip]
+  optimized: [mysol.host.name]
+  attributes:
+    name: {type: string}
+metricTypes: [mysol:cpu, mysol:memory]
+associationTypes:
+  common:has: [other:disk]
+`,
+		"entities/other.yaml": `namespace: {name: othersol, version: 1}
+kind: entity
+name: thing
+`,
+		"metrics/cpu.yaml": `namespace: {name: mysol, version: 1}
+kind: metric
+name: cpu
+`,
+		"mappings/host.yaml": `namespace: {name: mysol, version: 1}
+kind: resourceMapping
+name: host_mapping
+entityType: mysol:host
+attributeNameMappings:
+  name: host.name
+  port: host.port
+`,
+		"templates/list.json": `{"kind": "template", "name": "hostList", "target": "mysol:host", "requiredEntityTypes": ["mysol:vm"]}`,
+		"widgets/a.json":      `{"name": "a"}`,
+	})
+
+	report, err := lintSolutionDirectory(solutionDir)
+	require.NoError(t, err)
+
+	findings := map[string][]string{}
+	for _, issue := range report.Items {
+		findings[issue.Rule] = append(findings[issue.Rule], issue.Object+": "+issue.Message)
+	}
+	assert.Equal(t, []string{
+		`fmm:entity othersol:thing: namespace "othersol" doesn't match the solution's namespace "mysol"`,
+	}, findings["namespace-mismatch"])
+	assert.Equal(t, []string{
+		`fmm:entity mysol:host: required attribute "ip" is not defined in the entity's attributes`,
+		`fmm:resourceMapping mysol:host_mapping: attribute "port" is not defined in entity type "mysol:host"`,
+	}, findings["undefined-attribute"])
+	assert.Equal(t, []string{
+		`dashui:template hostList: entity type "mysol:vm" is not defined in the solution`,
+	}, findings["unknown-entity-type"])
+	assert.Equal(t, []string{
+		`fmm:entity mysol:host: metric type "mysol:memory" is not defined in the solution`,
+	}, findings["unknown-metric-type"])
+	assert.Equal(t, []string{
+		`manifest: knowledge type "mysol:widget" is not defined in the solution`,
+	}, findings["unknown-knowledge-type"])
+	assert.Equal(t, []string{
+		`fmm:entity mysol:host: entity type "other:disk" belongs to solution "other", which is not listed in the manifest's dependencies`,
+		`manifest: knowledge type "dashui:template" belongs to solution "dashui", which is not listed in the manifest's dependencies`,
+	}, findings["missing-dependency"])
+	assert.Equal(t, 8, report.Errors)
+	assert.Equal(t, 0, report.Suppressed)
+
+	// suppress findings in the lint configuration
+	writeTestFiles(t, solutionDir, map[string]string{
+		lintConfigFileName: `rules:
+  missing-dependency: warning
+  namespace-mismatch: "off"
+ignore:
+  - rule: undefined-attribute
+    object: "fmm:resourceMapping *"
+`,
+	})
+	report, err = lintSolutionDirectory(solutionDir)
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.Errors)
+	assert.Equal(t, 2, report.Warnings)
+	assert.Equal(t, 2, report.Suppressed)
+
+	writeTestFiles(t, solutionDir, map[string]string{lintConfigFileName: "rules:\n  no-such-rule: error\n"})
+	_, err = lintSolutionDirectory(solutionDir)
+	assert.ErrorContains(t, err, `unknown rule "no-such-rule"`)
+}
+
+func TestLintSolutionInvalidFiles(t *testing.T) {
+	solutionDir := t.TempDir()
+	writeTestFiles(t, solutionDir, map[string]string{
+		"manifest.yaml": `manifestVersion: "1.0.0"
+name: mysol
+solutionVersion: 1.0.0
+dependencies: [fmm]
+objects:
+  - type: fmm:entity
+    objectsDir: entities
+  - type: fmm:resourceMapping
+    objectsFile: mappings.yaml
+`,
+		"entities/host.yaml": "namespace: {name: mysol, version: 1}\nkind: entity\nname: host\n",
+		"entities/bad.yaml":  "name: [unterminated\n",
+		"mappings.yaml":      "[{name: host_mapping\n",
+	})
+
+	report, err := lintSolutionDirectory(solutionDir)
+	require.NoError(t, err)
+
+	var objects []string
+	for _, issue := range report.Items {
+		if issue.Rule == "invalid-file" {
+			objects = append(objects, issue.Object)
+			assert.Equal(t, severityError, issue.Severity)
+		}
+	}
+	assert.Equal(t, []string{"fmm:resourceMapping", "fmm:entity"}, objects)
+	assert.Equal(t, 2, report.Errors)
+}
@@ -34,7 +34,7 @@ var solutionListCmd = &cobra.Command{
 	Long:  `This command list all the solutions that are deployed in the current tenant specified in the profile.`,
 	Example: `  fsoc solution list
   fsoc solution list -o json`,
-	Run:              getSolutionList,
+	RunE:             getSolutionList,
 	TraverseChildren: true,
 	Annotations: map[string]string{
 		output.TableFieldsAnnotation:  "name:.data.name, tag:.data.tag, isSystem:.data.isSystem, isSubscribed:.data.isSubscribed, dependencies:.data.dependencies",
@@ -54,7 +54,7 @@ func getSolutionListCmd() *cobra.Command {
 
 }
 
-func getSolutionList(cmd *cobra.Command, args []string) {
+func getSolutionList(cmd *cobra.Command, args []string) error {
 	log.Info("Fetching the list of solutions...")
 	// get subscribe and unsubscribe flags
 	subscribed := cmd.Flags().Lookup("subscribed").Changed
@@ -76,7 +76,7 @@ func getSolutionList(cmd *cobra.Command, args []string) {
 	} else if unsubscribed {
 		filters = []string{"filter=" + url.QueryEscape("data.isSubscribed ne true")}
 	}
-	cmdkit.FetchAndPrint(cmd, solutionBaseURL, &cmdkit.FetchAndPrintOptions{Headers: headers, IsCollection: true, Filters: filters})
+	return cmdkit.FetchAndPrint(cmd, solutionBaseURL, &cmdkit.FetchAndPrintOptions{Headers: headers, IsCollection: true, Filters: filters})
 }
 
 func getSolutionNames(prefix string) (names []string) {
@@ -16,6 +16,8 @@ package solution
 
 import (
 	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -23,7 +25,9 @@ import (
 	"path/filepath"
 	"reflect"
 	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/apex/log"
 	"github.com/spf13/cobra"
@@ -51,10 +55,16 @@ The following priority is available:
 2. A tag is defined in the FSOC_SOLUTION_TAG environment variable (ignores env file)
 3. An explicitly provided --env-file path
 4. Implicitly looking into env.json file in the solution directory (usually not version controlled)
+
+Files and directories listed in the .fsocignore file in the solution directory (gitignore syntax) are not
+included in the package. The package is reproducible: the entries are sorted and have a fixed timestamp,
+so packaging the same files produces an identical zip file. Use --checksum to write the SHA-256 checksum of
+the package to a .sha256 file next to it (in the sha256sum format), e.g., for caching and verifying artifacts.
 `,
 	Example: `  fsoc solution package --solution-bundle=../mysolution.zip
-  fsoc solution package -d mysolution --solution-bundle=/somepath/mysolution-1234.zip`,
-	Run:         packageSolution,
+  fsoc solution package -d mysolution --solution-bundle=/somepath/mysolution-1234.zip
+  fsoc solution package --solution-bundle=../mysolution.zip --checksum`,
+	RunE:        packageSolution,
 	Annotations: map[string]string{config.AnnotationForConfigBypass: ""},
 }
 
@@ -75,11 +85,13 @@ func getSolutionPackageCmd() *cobra.Command {
 	solutionPackageCmd.Flags().
 		Bool("no-isolate", false, "Disable fsoc-supported solution isolation")
 	solutionPackageCmd.MarkFlagsMutuallyExclusive("tag", "stable", "env-file", "no-isolate")
+	solutionPackageCmd.Flags().
+		Bool("checksum", false, "Write the SHA-256 checksum of the zip file to a .sha256 file next to it")
 
 	return solutionPackageCmd
 }
 
-func packageSolution(cmd *cobra.Command, args []string) {
+func packageSolution(cmd *cobra.Command, args []string) error {
 	outputFilePath, _ := cmd.Flags().GetString("solution-bundle")
 	solutionDirectoryPath, _ := cmd.Flags().GetString("directory")
 
@@ -87,24 +99,24 @@ func packageSolution(cmd *cobra.Command, args []string) {
 	if solutionDirectoryPath == "" {
 		currentDir, err := os.Getwd()
 		if err != nil {
-			log.Fatal(err.Error())
+			return err
 		}
 		solutionDirectoryPath = currentDir
 	}
 	if !isSolutionPackageRoot(solutionDirectoryPath) {
-		log.Fatal("Could not find solution manifest") //nb: isSolutionPackageRoot prints clear message
+		return fmt.Errorf("could not find solution manifest") //nb: isSolutionPackageRoot prints clear message
 	}
 
 	// isolate if needed
 	solutionDirectoryPath, tag, err := embeddedConditionalIsolate(cmd, solutionDirectoryPath)
 	if err != nil {
-		log.Fatalf("Failed to isolate solution with tag: %v", err)
+		return fmt.Errorf("failed to isolate solution with tag: %w", err)
 	}
 
 	// load manifest
 	manifest, err := getSolutionManifest(solutionDirectoryPath)
 	if err != nil {
-		log.Fatalf("Failed to read solution manifest: %v", err)
+		return fmt.Errorf("failed to read solution manifest: %w", err)
 	}
 
 	var message string
@@ -112,93 +124,163 @@ func packageSolution(cmd *cobra.Command, args []string) {
 	output.PrintCmdStatus(cmd, message)
 
 	// create archive
-	solutionArchive := generateZip(cmd, solutionDirectoryPath, outputFilePath)
-	solutionArchive.Close()
+	solutionArchive, err := createSolutionZip(cmd, solutionDirectoryPath, outputFilePath)
+	if err != nil {
+		return err
+	}
 
 	message = fmt.Sprintf("Solution %s version %s is ready in %s\n", manifest.Name, manifest.SolutionVersion, solutionArchive.Name())
 	output.PrintCmdStatus(cmd, message)
+
+	if checksum, _ := cmd.Flags().GetBool("checksum"); checksum {
+		checksumPath, digest, err := writeChecksumFile(solutionArchive.Name())
+		if err != nil {
+			return err
+		}
+		output.PrintCmdStatus(cmd, fmt.Sprintf("SHA-256 checksum %s written to %s\n", digest, checksumPath))
+	}
+	return nil
+}
+
+// writeChecksumFile computes the SHA-256 checksum of a file and writes it, in the format of
+// the sha256sum utility, to a file with the same name and a .sha256 suffix. It returns the
+// path of the checksum file and the checksum.
+func writeChecksumFile(filePath string) (string, string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to open %q for computing its checksum: %w", filePath, err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", "", fmt.Errorf("failed to compute the checksum of %q: %w", filePath, err)
+	}
+	digest := hex.EncodeToString(hash.Sum(nil))
+
+	checksumPath := filePath + ".sha256"
+	content := fmt.Sprintf("%s  %s\n", digest, filepath.Base(filePath))
+	if err := os.WriteFile(checksumPath, []byte(content), 0644); err != nil {
+		return "", "", fmt.Errorf("failed to write the checksum file: %w", err)
+	}
+	return checksumPath, digest, nil
 }
 
 // --- Helper functions for managing solution directory and zip bundle
 
-// generateZip creates a solution bundle (zip file) from a given solutionPath directory.
+// createSolutionZip creates a solution bundle (zip file) from a given solutionPath directory.
 // If outputPath is specified, the zip will be placed in that directory (if an existing directory) or filename (otherwise);
 // if outputPath is empty, the zip file will be placed in the temp directory.
 // If solutionPath is not specified, the current directory is assumed (it must contain the solution
-// manifest in its final form).
-func generateZip(cmd *cobra.Command, solutionPath string, outputPath string) *os.File {
-	var archive *os.File
-	var err error
-	var archiveFileTemplate string
+// manifest in its final form). The returned file is closed.
+func createSolutionZip(cmd *cobra.Command, solutionPath string, outputPath string) (archive *os.File, err error) {
 	solutionName := filepath.Base(solutionPath)
 	solutionNameWithZipSuffix := fmt.Sprintf("%s.zip", solutionName)
 
 	// create zip file
 	if outputPath != "" {
 		// absolutize path
-		outputPath = absolutizePath(outputPath)
+		outputPath, err = absolutizePath(outputPath)
+		if err != nil {
+			return nil, err
+		}
 
 		// if outputPath is an existing directory, place zip there; otherwise, treat as file path
-		var fileInfo os.FileInfo
-		fileInfo, err = os.Stat(outputPath)
+		fileInfo, err := os.Stat(outputPath)
 		if err == nil && fileInfo.IsDir() {
 			outputPath = filepath.Join(filepath.Dir(outputPath), solutionNameWithZipSuffix)
 		} else if err != nil && !errors.Is(err, os.ErrNotExist) {
-			log.Fatalf("Failed to access target path %q: %v", outputPath, err)
+			return nil, fmt.Errorf("failed to access target path %q: %w", outputPath, err)
 		} // else treat as file path, possibly overwriting existing file
 		archive, err = os.Create(outputPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create file %s: %w", outputPath, err)
+		}
 	} else {
-		archiveFileTemplate = fmt.Sprintf("%s*.zip", solutionName)
-		archive, err = os.CreateTemp("", archiveFileTemplate)
-		outputPath = archive.Name()
-	}
-	if err != nil {
-		log.Fatalf("failed to create file %s: %v", outputPath, err)
-		panic(err)
+		archive, err = os.CreateTemp("", fmt.Sprintf("%s*.zip", solutionName))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create a temporary file for the solution zip: %w", err)
+		}
 	}
 	output.PrintCmdStatus(cmd, fmt.Sprintf("Creating solution zip: %q\n", archive.Name()))
 	log.WithField("path", archive.Name()).Info("Creating solution file")
-	defer archive.Close()
-	zipWriter := zip.NewWriter(archive)
+	file := archive
+	defer func() {
+		file.Close()
+		if err != nil {
+			os.Remove(file.Name())
+		}
+	}()
+	zipWriter := zip.NewWriter(file)
 
 	// determine the solution directory's parent folder to start archiving from
-	solutionPath = absolutizePath(solutionPath)
+	solutionPath, err = absolutizePath(solutionPath)
+	if err != nil {
+		return nil, err
+	}
 	solutionParentPath := filepath.Dir(solutionPath)
 
 	// switch cwd to the solution directory for archiving
 	fsocWorkingDir, err := os.Getwd()
 	if err != nil {
-		log.Fatalf("Couldn't get the current working directory: %v", err)
+		return nil, fmt.Errorf("couldn't get the current working directory: %w", err)
 	}
 	err = os.Chdir(solutionParentPath)
 	if err != nil {
-		log.Fatalf("Couldn't switch working directory to solution root's parent directory %q: %v", solutionParentPath, err)
+		return nil, fmt.Errorf("couldn't switch working directory to solution root's parent directory %q: %w", solutionParentPath, err)
 	}
 	defer func() {
 		// restore original working directory
-		err := os.Chdir(fsocWorkingDir)
-		if err != nil {
-			log.Fatalf("Couldn't switch working directory back to starting working directory: %v", err)
+		if chdirErr := os.Chdir(fsocWorkingDir); chdirErr != nil && err == nil {
+			err = fmt.Errorf("couldn't switch working directory back to starting working directory: %w", chdirErr)
 		}
 	}()
 
+	ignored, err := loadIgnoreList(solutionPath)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't load the solution's ignore file: %w", err)
+	}
+
+	// collect the entries and archive them in sorted order, so that the archive doesn't depend on the file system
+	entries := map[string]os.FileInfo{}
 	err = filepath.Walk(solutionName,
 		func(path string, info os.FileInfo, err error) error {
 			if err != nil {
 				return err
 			}
-			if isAllowedPath(path, info) {
-				addFileToZip(zipWriter, path, info)
+			relPath, err := filepath.Rel(solutionName, path)
+			if err != nil {
+				return err
+			}
+			if path != solutionName && (!isAllowedPath(relPath, info) || ignored.isIgnored(relPath, info.IsDir())) {
+				log.WithField("path", relPath).Debug("Excluding path from the solution zip")
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
 			}
+			entries[path] = info
 			return nil
 		})
 	if err != nil {
-		log.Fatalf("Error traversing the directory: %v", err)
+		return nil, fmt.Errorf("error traversing the directory: %w", err)
+	}
+	paths := make([]string, 0, len(entries))
+	for path := range entries {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool { return filepath.ToSlash(paths[i]) < filepath.ToSlash(paths[j]) })
+	for _, path := range paths {
+		if err = addFileToZip(zipWriter, path, entries[path]); err != nil {
+			return nil, err
+		}
 	}
-	zipWriter.Close()
-	log.WithField("path", archive.Name()).Info("Created a solution with path")
+	if err = zipWriter.Close(); err != nil {
+		return nil, fmt.Errorf("couldn't finish the solution zip: %w", err)
+	}
+	log.WithField("path", file.Name()).Info("Created a solution with path")
 
-	return archive
+	return file, nil
 }
 
 func isAllowedPath(path string, info os.FileInfo) bool {
@@ -225,10 +307,13 @@ func isAllowedPath(path string, info os.FileInfo) bool {
 	return allow
 }
 
-func addFileToZip(zipWriter *zip.Writer, fileName string, info os.FileInfo) {
+// zipEntryTime is the modification time of all solution zip entries (the earliest time the zip format supports)
+var zipEntryTime = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func addFileToZip(zipWriter *zip.Writer, fileName string, info os.FileInfo) error {
 	newFile, err := os.Open(fileName)
 	if err != nil {
-		log.Fatalf("Couldn't open file %q: %v", fileName, err)
+		return fmt.Errorf("couldn't open file %q: %w", fileName, err)
 	}
 	defer newFile.Close()
 
@@ -238,17 +323,23 @@ func addFileToZip(zipWriter *zip.Writer, fileName string, info os.FileInfo) {
 
 	fileName = filepath.ToSlash(fileName)
 
-	archWriter, err := zipWriter.Create(fileName)
+	// use a fixed timestamp, so that packaging the same files produces an identical archive
+	archWriter, err := zipWriter.CreateHeader(&zip.FileHeader{
+		Name:     fileName,
+		Method:   zip.Deflate,
+		Modified: zipEntryTime,
+	})
 
 	if err != nil {
-		log.Fatalf("Couldn't create archive writer for file: %v", err)
+		return fmt.Errorf("couldn't create archive writer for file: %w", err)
 	}
 
 	if !info.IsDir() {
 		if _, err := io.Copy(archWriter, newFile); err != nil {
-			log.Fatalf("Couldn't write file to architve: %v", err)
+			return fmt.Errorf("couldn't write file %q to archive: %w", fileName, err)
 		}
 	}
+	return nil
 }
 
 func isSolutionPackageRoot(path string) bool {
@@ -313,8 +404,12 @@ func getSolutionManifest(path string) (*Manifest, error) {
 	}
 
 	// Log manifest summary
+	manifestAbsPath, err := absolutizePath(manifestPath)
+	if err != nil {
+		return nil, err
+	}
 	log.WithFields(log.Fields{
-		"manifest_path":    absolutizePath(manifestPath),
+		"manifest_path":    manifestAbsPath,
 		"manifest_version": manifest.ManifestVersion,
 		"manifest_format":  manifest.ManifestFormat,
 		"solution_name":    manifest.Name,
@@ -328,14 +423,14 @@ func getSolutionManifest(path string) (*Manifest, error) {
 // absolutizePath takes a path in any form (absolute, relative or home-dir-relative)
 // and converts it to an absolute path (which is also cleaned up/canonicalized).
 // Note that this works both for files and directories, including just "~"
-func absolutizePath(inputPath string) string {
+func absolutizePath(inputPath string) (string, error) {
 	path := inputPath // keep original value for error messages
 
 	// replace ~ with home directory, if needed
 	if strings.HasPrefix(path, "~") {
 		dirname, err := os.UserHomeDir()
 		if err != nil {
-			log.Fatalf("Failed to get current directory to use for %q: %v", inputPath, err)
+			return "", fmt.Errorf("failed to get home directory to use for %q: %w", inputPath, err)
 		}
 		path = dirname + path[1:] // can't use Join because source may be just "~"
 	}
@@ -343,11 +438,11 @@ func absolutizePath(inputPath string) string {
 	// convert to absolute path
 	path, err := filepath.Abs(path)
 	if err != nil {
-		log.Fatalf("Failed to get absolute path for %q: %v", inputPath, err)
+		return "", fmt.Errorf("failed to get absolute path for %q: %w", inputPath, err)
 	}
 
 	// clean path
 	path = filepath.Clean(path)
 
-	return path
+	return path, nil
 }
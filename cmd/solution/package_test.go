@@ -0,0 +1,87 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solution
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateZipReproducible(t *testing.T) {
+	solutionDir := filepath.Join(t.TempDir(), "mysol")
+	writeTestFiles(t, solutionDir, map[string]string{
+		"manifest.json":    `{"manifestVersion": "1.0.0", "name": "mysol", "solutionVersion": "1.0.0"}`,
+		"objects/b.json":   `{"name": "b"}`,
+		"objects/a.json":   `{"name": "a"}`,
+		"objects/a.log":    "debug output",
+		"build/out.json":   `{}`,
+		".git/HEAD":        "ref: refs/heads/main",
+		IgnoreFileName:     "*.log\nbuild/\n",
+		lintConfigFileName: "rules: {}\n",
+	})
+
+	outputDir := t.TempDir()
+	first, err := createSolutionZip(nil, solutionDir, filepath.Join(outputDir, "first.zip"))
+	require.NoError(t, err)
+
+	later := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(solutionDir, "objects", "a.json"), later, later))
+	second, err := createSolutionZip(nil, solutionDir, filepath.Join(outputDir, "second.zip"))
+	require.NoError(t, err)
+
+	firstData, err := os.ReadFile(first.Name())
+	require.NoError(t, err)
+	secondData, err := os.ReadFile(second.Name())
+	require.NoError(t, err)
+	assert.Equal(t, firstData, secondData, "packages of the same files must be identical")
+
+	reader, err := zip.OpenReader(first.Name())
+	require.NoError(t, err)
+	defer reader.Close()
+	names := []string{}
+	for _, f := range reader.File {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"mysol/", "mysol/manifest.json", "mysol/objects/", "mysol/objects/a.json", "mysol/objects/b.json"}, names)
+
+	checksumPath, digest, err := writeChecksumFile(first.Name())
+	require.NoError(t, err)
+	content, err := os.ReadFile(checksumPath)
+	require.NoError(t, err)
+	assert.Equal(t, digest+"  first.zip", strings.TrimSpace(string(content)))
+	assert.Len(t, digest, 64)
+}
+
+func TestCreateSolutionZipError(t *testing.T) {
+	workingDir, err := os.Getwd()
+	require.NoError(t, err)
+	outputPath := filepath.Join(t.TempDir(), "mysol.zip")
+
+	archive, err := createSolutionZip(nil, filepath.Join(t.TempDir(), "mysol"), outputPath)
+	assert.Nil(t, archive)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.NoFileExists(t, outputPath, "a failed package must not be left behind")
+
+	currentDir, err := os.Getwd()
+	require.NoError(t, err)
+	assert.Equal(t, workingDir, currentDir)
+}
@@ -104,7 +104,10 @@ func NewSolutionDirectoryContentsFromDisk(path string) (*SolutionDirectoryConten
 	s := SolutionDirectoryContents{}
 
 	// get absolute path (deals with relative paths, .., ~, etc.)
-	rootPath := absolutizePath(path)
+	rootPath, err := absolutizePath(path)
+	if err != nil {
+		return nil, err
+	}
 
 	// read manifest
 	manifest, err := getSolutionManifest(path)
@@ -186,7 +189,7 @@ func (s *SolutionDirectoryContents) Write(fs afero.Fs) error {
 }
 
 // Dump displays the contents of the solution contents object
-func (s *SolutionDirectoryContents) Dump(cmd *cobra.Command) {
+func (s *SolutionDirectoryContents) Dump(cmd *cobra.Command) error {
 	t := output.Table{
 		Headers: []string{"Solution Name", "Solution Version", "Solution Type", "Manifest Version", "Description"},
 		Lines: [][]string{
@@ -199,7 +202,9 @@ func (s *SolutionDirectoryContents) Dump(cmd *cobra.Command) {
 		Detail: true,
 	}
 	if cmd != nil {
-		output.PrintCmdOutputCustom(cmd, nil, &t)
+		if err := output.PrintCmdOutputCustom(cmd, nil, &t); err != nil {
+			return err
+		}
 	} else {
 		for i, line := range t.Lines {
 			fmt.Printf("%v: %v\n", t.Headers[i], line[0])
@@ -213,6 +218,8 @@ func (s *SolutionDirectoryContents) Dump(cmd *cobra.Command) {
 			fmt.Printf("  File %v\n", file)
 		}
 	}
+
+	return nil
 }
 
 // SetComponentDefType sets the component definition type for the specified object,
@@ -392,9 +399,7 @@ func (s *SolutionDirectoryContents) annotateFiles() error {
 		f := &s.RootFiles[fileIndex]
 		if slices.Contains(hiddenFiles, f.Name) {
 			f.FileKind = KindHidden
-		} else {
-			f.FileKind = KindUnknown
-		}
+		} // else keep the kind assigned from the manifest, if any
 	}
 
 	return nil
@@ -405,13 +410,13 @@ func (s *SolutionDirectoryContents) annotateFile(name string, kind SolutionFileK
 
 	dirName, fileName := filepath.Split(name)
 	dirName = filepath.Clean(dirName) // removes the trailing separator
-	if dirName == "" {
+	if dirName == "" || dirName == "." {
 		log.Warnf("File %q for %v %v is in the root directory; it should be in a subdirectory", name, kind, objectType)
 
 		// find the file
-		for _, f := range s.RootFiles {
-			if f.Name == name {
-				file = &f
+		for fileIndex := 0; fileIndex < len(s.RootFiles); fileIndex++ {
+			if s.RootFiles[fileIndex].Name == fileName {
+				file = &s.RootFiles[fileIndex]
 				break
 			}
 		}
@@ -15,7 +15,14 @@
 package solution
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
+	"github.com/cisco-open/fsoc/config"
+	"github.com/cisco-open/fsoc/output"
 )
 
 var solutionPushCmd = &cobra.Command{
@@ -35,14 +42,19 @@ A tag may be defined in the following ways (in order of precedence):
   1. Specified flag --tag=xyz or --stable: use this tag, ignoring .tag file or env vars
   2. A tag is defined in the FSOC_SOLUTION_TAG environment variable (ignores .tag file)
   3. A tag is defined in the .tag file in the solution directory (usually not version controlled)
+
+To promote a solution from another tenant, use --profile-from with the name of that tenant's profile and
+--from-solution with the solution's name: the solution's package is downloaded from that tenant (with the
+--from-tag tag) and pushed to the current profile's tenant with the tag specified as above.
 `,
 	Example: `
   fsoc solution push --tag=stable
   fsoc solution push --wait --tag=dev
   fsoc solution push --bump --wait=60
   fsoc solution push -d mysolution --stable --wait
-  fsoc solution push --solution-bundle=mysolution-1.22.3.zip --tag=stable`,
-	Run:              pushSolution,
+  fsoc solution push --solution-bundle=mysolution-1.22.3.zip --tag=stable
+  fsoc solution push --profile-from staging --from-solution mysolution --from-tag=stable --tag=stable`,
+	RunE:             pushSolution,
 	TraverseChildren: true,
 }
 
@@ -70,6 +82,20 @@ func getSolutionPushCmd() *cobra.Command {
 	solutionPushCmd.Flags().
 		Bool("subscribe", false, "Subscribe to the solution that you are pushing")
 
+	solutionPushCmd.Flags().
+		String("profile-from", "", "Profile of the tenant to download the solution package from, instead of using a local solution")
+	_ = solutionPushCmd.RegisterFlagCompletionFunc("profile-from", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return config.ListContexts(toComplete), cobra.ShellCompDirectiveNoFileComp
+	})
+	solutionPushCmd.Flags().
+		String("from-solution", "", "Name of the solution to download with --profile-from")
+	solutionPushCmd.Flags().
+		String("from-tag", "stable", "Tag of the solution to download with --profile-from")
+
+	solutionPushCmd.MarkFlagsRequiredTogether("profile-from", "from-solution")
+	for _, flag := range []string{"solution-bundle", "directory", "bump", "wait", "subscribe"} {
+		solutionPushCmd.MarkFlagsMutuallyExclusive("profile-from", flag) // pushes a downloaded package, like solution-bundle
+	}
 	solutionPushCmd.MarkFlagsMutuallyExclusive("solution-bundle", "directory") // either solution dir or prepackaged zip
 	solutionPushCmd.MarkFlagsMutuallyExclusive("solution-bundle", "bump")      // cannot modify prepackaged zip
 	solutionPushCmd.MarkFlagsMutuallyExclusive("solution-bundle", "wait")      // TODO: allow when extracting manifest data
@@ -79,6 +105,24 @@ func getSolutionPushCmd() *cobra.Command {
 	return solutionPushCmd
 }
 
-func pushSolution(cmd *cobra.Command, args []string) {
-	uploadSolution(cmd, true)
+func pushSolution(cmd *cobra.Command, args []string) error {
+	fromProfile, _ := cmd.Flags().GetString("profile-from")
+	if fromProfile == "" {
+		return uploadSolution(cmd, true)
+	}
+
+	// download the solution from the other tenant & push it as a prepackaged solution
+	solutionName, _ := cmd.Flags().GetString("from-solution")
+	fromTag, _ := cmd.Flags().GetString("from-tag")
+	if fromProfile == config.GetCurrentProfileName() {
+		return clierror.New(clierror.Usage, "the --profile-from profile must be different from the current profile %q", fromProfile)
+	}
+	zipPath, err := downloadSolutionPackage(solutionName, fromTag, "", fromProfile)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(zipPath)
+	output.PrintCmdStatus(cmd, fmt.Sprintf("Downloaded solution %q with tag %v from profile %q\n", solutionName, fromTag, fromProfile))
+
+	return uploadSolution(cmd, true, WithSolutionName(solutionName), WithSolutionZipPath(zipPath))
 }
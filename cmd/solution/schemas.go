@@ -0,0 +1,204 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solution
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// schemaStore provides the JSON schemas of knowledge types for validating solution objects
+// without access to the platform. Schemas are looked up, in order, in the solution's own
+// knowledge types, the vendored schema bundle (if any) and the local schema cache; if
+// fetching is enabled, missing schemas are fetched from the platform and added to the cache.
+// Bundles and the cache contain a NAMESPACE/TYPE.json file for each type (e.g., fmm/entity.json),
+// with either the knowledge type definition (as returned by "fsoc knowledge get-type") or just its JSON schema.
+type schemaStore struct {
+	solutionTypes map[string]any // JSON schemas of the solution's own types, by fully qualified type name
+	bundle        fs.FS          // vendored schema bundle, nil if none
+	cacheDir      string         // local schema cache directory, empty if not available
+	fetch         bool           // fetch missing schemas from the platform
+	schemas       map[string]*gojsonschema.Schema
+	errors        map[string]error
+}
+
+// defaultSchemaCacheDir returns the directory of the local schema cache
+func defaultSchemaCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		log.Warnf("Cannot determine the cache directory, schema cache disabled: %v", err)
+		return ""
+	}
+	return filepath.Join(dir, "fsoc", "schemas")
+}
+
+// newSchemaStore creates a schema store; bundlePath is a directory or a zip file, empty if none
+func newSchemaStore(bundlePath string, cacheDir string, fetch bool) (*schemaStore, error) {
+	store := &schemaStore{
+		solutionTypes: map[string]any{},
+		cacheDir:      cacheDir,
+		fetch:         fetch,
+		schemas:       map[string]*gojsonschema.Schema{},
+		errors:        map[string]error{},
+	}
+
+	if bundlePath != "" {
+		var err error
+		bundlePath, err = absolutizePath(bundlePath)
+		if err != nil {
+			return nil, err
+		}
+		info, err := os.Stat(bundlePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to access the schema bundle: %w", err)
+		}
+		if info.IsDir() {
+			store.bundle = os.DirFS(bundlePath)
+		} else {
+			reader, err := zip.OpenReader(bundlePath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open the schema bundle %q: %w", bundlePath, err)
+			}
+			store.bundle = reader // never closed, lives as long as the command
+		}
+	}
+
+	return store, nil
+}
+
+// addSolutionType registers the JSON schema of a knowledge type defined by the solution
+func (s *schemaStore) addSolutionType(fqtn string, schema any) {
+	s.solutionTypes[fqtn] = schema
+}
+
+// get returns the compiled schema for a type, or nil if no schema is available for it
+func (s *schemaStore) get(fqtn string) (*gojsonschema.Schema, error) {
+	if schema, found := s.schemas[fqtn]; found {
+		return schema, s.errors[fqtn]
+	}
+
+	schema, err := s.load(fqtn)
+	if err == nil && schema != nil {
+		var compiled *gojsonschema.Schema
+		compiled, err = gojsonschema.NewSchema(gojsonschema.NewGoLoader(schema))
+		if err != nil {
+			err = fmt.Errorf("invalid JSON schema for type %q: %w", fqtn, err)
+		}
+		s.schemas[fqtn] = compiled
+	} else {
+		s.schemas[fqtn] = nil
+	}
+	s.errors[fqtn] = err
+	return s.schemas[fqtn], err
+}
+
+// load finds the JSON schema for a type, returning nil if not found
+func (s *schemaStore) load(fqtn string) (any, error) {
+	if schema, found := s.solutionTypes[fqtn]; found {
+		return schema, nil
+	}
+
+	path, err := schemaPath(fqtn)
+	if err != nil {
+		return nil, err
+	}
+	if s.bundle != nil {
+		if data, err := fs.ReadFile(s.bundle, path); err == nil {
+			return parseSchemaFile(data)
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read schema bundle: %w", err)
+		}
+	}
+	if s.cacheDir != "" {
+		if data, err := os.ReadFile(filepath.Join(s.cacheDir, filepath.FromSlash(path))); err == nil {
+			return parseSchemaFile(data)
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read schema cache: %w", err)
+		}
+	}
+	if !s.fetch {
+		return nil, nil
+	}
+
+	// fetch from the platform & save to the cache
+	var typeDef map[string]any
+	if err := api.JSONGet(getTypeUrl(fqtn), &typeDef, &api.Options{ExpectedErrors: []int{404}}); err != nil {
+		var statusErr *api.HttpStatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == 404 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch the schema for type %q: %w", fqtn, err)
+	}
+	schema, found := typeDef["jsonSchema"]
+	if !found {
+		return nil, fmt.Errorf("the definition of type %q has no JSON schema", fqtn)
+	}
+	if s.cacheDir != "" {
+		s.save(path, schema)
+	}
+	return schema, nil
+}
+
+// save stores a schema in the local cache; failures are only logged, as the cache is an optimization
+func (s *schemaStore) save(path string, schema any) {
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err == nil {
+		filePath := filepath.Join(s.cacheDir, filepath.FromSlash(path))
+		if err = os.MkdirAll(filepath.Dir(filePath), 0o755); err == nil {
+			err = os.WriteFile(filePath, data, 0o644)
+		}
+	}
+	if err != nil {
+		log.Warnf("Failed to save schema %q to the cache: %v", path, err)
+	}
+}
+
+// schemaPath returns the path of a type's schema file in bundles and the cache
+func schemaPath(fqtn string) (string, error) {
+	namespace, name, found := strings.Cut(fqtn, ":")
+	if !found || namespace == "" || name == "" || strings.ContainsAny(fqtn, `/\`) {
+		return "", fmt.Errorf("invalid type name %q, expected NAMESPACE:TYPE", fqtn)
+	}
+	return namespace + "/" + name + ".json", nil
+}
+
+// parseSchemaFile parses a schema file, which contains either a type definition or a JSON schema
+func parseSchemaFile(data []byte) (any, error) {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse schema file: %w", err)
+	}
+	if typeDef, ok := doc.(map[string]any); ok {
+		if schema, found := typeDef["jsonSchema"]; found {
+			return schema, nil
+		}
+	}
+	return doc, nil
+}
+
+func getTypeUrl(fqtn string) string {
+	return fmt.Sprintf("knowledge-store/v1/types/%s", fqtn)
+}
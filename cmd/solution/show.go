@@ -15,11 +15,11 @@
 package solution
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 
-	"github.com/apex/log"
 	"github.com/spf13/cobra"
 
 	"github.com/cisco-open/fsoc/output"
@@ -31,7 +31,7 @@ var solutionShowCmd = &cobra.Command{
 	Short:   "Show solution details",
 	Long:    `Show all objects that are part of the solution in this directory`,
 	Example: `  fsoc solution show`,
-	Run:     solutionShow,
+	RunE:    solutionShow,
 	Annotations: map[string]string{
 		output.DetailFieldsAnnotation: "ManifestVersion:.ManifestVersion, Name:.SolutionName, Version:.SolutionVersion, Type:.SolutionType, Description:.Description, Dependencies:.Dependencies",
 	},
@@ -64,24 +64,24 @@ func getSolutionShowCmd() *cobra.Command {
 	return solutionShowCmd
 }
 
-func solutionShow(cmd *cobra.Command, args []string) {
+func solutionShow(cmd *cobra.Command, args []string) error {
 	solutionDirectoryPath, _ := cmd.Flags().GetString("directory")
 
 	// finalize solution path
 	if solutionDirectoryPath == "" {
 		currentDir, err := os.Getwd()
 		if err != nil {
-			log.Fatal(err.Error())
+			return err
 		}
 		solutionDirectoryPath = currentDir
 	}
 	if !isSolutionPackageRoot(solutionDirectoryPath) {
-		log.Fatal("Could not find solution manifest") //nb: isSolutionPackageRoot prints clear message
+		return fmt.Errorf("could not find solution manifest") //nb: isSolutionPackageRoot prints clear message
 	}
 
 	contents, err := NewSolutionDirectoryContentsFromDisk(solutionDirectoryPath)
 	if err != nil {
-		log.Fatal(err.Error())
+		return err
 	}
 
 	manifest := contents.Manifest
@@ -96,7 +96,9 @@ func solutionShow(cmd *cobra.Command, args []string) {
 
 	_ = cmd.Flags().Set("output", "detail")
 	_ = cmd.Flags().Set("fields", "ManifestVersion:.ManifestVersion, Name:.SolutionName, Version:.SolutionVersion, Type:.SolutionType, Description:.Description, Dependencies:.Dependencies")
-	output.PrintCmdOutput(cmd, display)
+	if err := output.PrintCmdOutput(cmd, display); err != nil {
+		return err
+	}
 
 	// create list of files
 	var files []solutionFileElement
@@ -129,7 +131,7 @@ func solutionShow(cmd *cobra.Command, args []string) {
 
 	_ = cmd.Flags().Set("output", "table")
 	_ = cmd.Flags().Set("fields", "File:.Path, Kind:.Kind, Type:.Type")
-	output.PrintCmdOutput(cmd, solutionFileList{Items: files, Total: len(files)})
+	return output.PrintCmdOutput(cmd, solutionFileList{Items: files, Total: len(files)})
 }
 
 func verboseFileKind(kind SolutionFileKinds) string {
@@ -15,11 +15,13 @@
 package solution
 
 import (
+	"fmt"
 	"net/url"
 
 	"github.com/apex/log"
 	"github.com/spf13/cobra"
 
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
 	"github.com/cisco-open/fsoc/config"
 )
 
@@ -59,6 +61,9 @@ func NewSubCmd() *cobra.Command {
 	solutionCmd.AddCommand(getSolutionValidateCmd())
 	solutionCmd.AddCommand(GetSolutionForkCommand())
 	solutionCmd.AddCommand(getSolutionCheckCmd())
+	solutionCmd.AddCommand(getSolutionLintCmd())
+	solutionCmd.AddCommand(getSolutionDiffCmd())
+	solutionCmd.AddCommand(getSolutionDevCmd())
 	solutionCmd.AddCommand(getSolutionStatusCmd())
 	solutionCmd.AddCommand(getSolutionDescribeCmd())
 	solutionCmd.AddCommand(getSolutionShowCmd())
@@ -76,15 +81,15 @@ func NewSubCmd() *cobra.Command {
 // getSolutionNameFromArgs gets the solution name from the command line, either from
 // the first positional argument or from a flag (deprecated but kepts for backward compatibility).
 // The flagName is optional (use "" to omit).
-// Prints error message and terminates if the name is missing/empty
-func getSolutionNameFromArgs(cmd *cobra.Command, args []string, flagName string) string {
+// Returns a usage error if the name is missing/empty or specified both ways
+func getSolutionNameFromArgs(cmd *cobra.Command, args []string, flagName string) (string, error) {
 	// get solution name from a flag, if provided (deprecated but kept for backward compatibility)
 	var nameFromFlag string
 	if flagName != "" {
 		var err error
 		nameFromFlag, err = cmd.Flags().GetString(flagName)
 		if err != nil {
-			log.Fatalf("Error parsing flag %q: %v", flagName, err)
+			return "", fmt.Errorf("error parsing flag %q: %w", flagName, err)
 		}
 	}
 
@@ -96,20 +101,19 @@ func getSolutionNameFromArgs(cmd *cobra.Command, args []string, flagName string)
 	}
 	if name != "" {
 		if nameFromFlag != "" {
-			log.Fatal("Solution name must be specified either as a positional argument or with a flag but not both")
+			return "", clierror.New(clierror.Usage, "solution name must be specified either as a positional argument or with a flag but not both")
 		}
 
-		return name
+		return name, nil
 	}
 
 	// return the solution name from flag, if provided
 	if nameFromFlag != "" {
-		return nameFromFlag
+		return nameFromFlag, nil
 	}
 
 	// fail
-	log.Fatal("A non-empty <solution-name> argument is required.")
-	return "" // unreachable
+	return "", clierror.New(clierror.Usage, "a non-empty <solution-name> argument is required")
 }
 
 // getSolutionObjectUrl returns the tenant-relative URL path to the solution object
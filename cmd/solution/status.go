@@ -20,10 +20,12 @@ import (
 	"net/url"
 	"reflect"
 	"strings"
+	"sync"
 
 	"github.com/apex/log"
 	"github.com/spf13/cobra"
 
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
 	"github.com/cisco-open/fsoc/config"
 	"github.com/cisco-open/fsoc/output"
 	"github.com/cisco-open/fsoc/platform/api"
@@ -65,11 +67,7 @@ var solutionStatusCmd = &cobra.Command{
 	Long:  `This command provides the ability to see the installation and upload status of a solution.`,
 	Example: `  fsoc solution status spacefleet
   fsoc solution status spacefleet --solution-version 1.0.0`,
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := getSolutionStatus(cmd, args); err != nil {
-			log.Fatalf(err.Error())
-		}
-	},
+	RunE:             getSolutionStatus,
 	TraverseChildren: true,
 	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		config.SetActiveProfile(cmd, args, false)
@@ -95,20 +93,21 @@ func getSolutionStatusCmd() *cobra.Command {
 	return solutionStatusCmd
 }
 
-func getObjects(url string, headers map[string]string) StatusItem {
+// fetchObjects returns the most recent status object at the url, or an empty one if there is none
+func fetchObjects(url string, headers map[string]string) (StatusItem, error) {
 	var res ResponseBlob
 	var emptyData StatusItem
 
 	err := api.JSONGet(url, &res, &api.Options{Headers: headers})
 
 	if err != nil {
-		log.Fatalf("Error fetching solution object %q: %v", url, err)
+		return emptyData, fmt.Errorf("error fetching solution object %q: %w", url, err)
 	}
 
 	if len(res.Items) > 0 {
-		return res.Items[0]
+		return res.Items[0], nil
 	} else {
-		return emptyData
+		return emptyData, nil
 	}
 }
 
@@ -124,43 +123,43 @@ func getExtensibilitySolutionObject(url string, headers map[string]string) (Exte
 	return res.Data, nil
 }
 
-func checkIfSolutionDeleted(solutionName string, solutionTag string) (bool, SolutionDeletionData) {
+func checkIfSolutionDeleted(solutionName string, solutionTag string) (bool, SolutionDeletionData, error) {
 	log.Infof("Checking if solution with name: %s and tag: %s has been deleted", solutionName, solutionTag)
-	solutionDeletionObject := getSolutionDeletionObject(solutionTag, solutionName)
+	solutionDeletionObject, err := getSolutionDeletionObject(solutionTag, solutionName)
+	if err != nil {
+		return false, SolutionDeletionData{}, err
+	}
 
 	if solutionDeletionObject.IsEmpty() {
 		log.Infof("Solution with name: %s and tag: %s not found in deletion object", solutionName, solutionTag)
-		return false, SolutionDeletionData{}
+		return false, SolutionDeletionData{}, nil
 	} else {
-		return true, solutionDeletionObject.DeletionData
+		return true, solutionDeletionObject.DeletionData, nil
 	}
 }
 
-func fetchInstallationAndReleaseObjects(solutionReleaseObjectQuery string, solutionInstallObjectQuery string, successfulSolutionInstallObjectQuery string, requestHeaders map[string]string) (StatusItem, StatusItem, StatusItem) {
-	// Initialize channels for each type of status
-	uploadStatusChan := make(chan StatusItem)
-	installStatusChan := make(chan StatusItem)
-	successfulSolutionInstallStatusChan := make(chan StatusItem)
-
-	// Launch goroutines to fetch status objects in parallel
-	go func() {
-		uploadStatusChan <- getObjects(fmt.Sprintf(getSolutionReleaseUrl(), solutionReleaseObjectQuery), requestHeaders)
-	}()
-	go func() {
-		installStatusChan <- getObjects(fmt.Sprintf(getSolutionInstallUrl(), solutionInstallObjectQuery), requestHeaders)
-	}()
-	go func() {
-		successfulSolutionInstallStatusChan <- getObjects(fmt.Sprintf(getSolutionInstallUrl(), successfulSolutionInstallObjectQuery), requestHeaders)
-	}()
-
-	// Wait for and receive the status objects from the channels
-	uploadStatusItem := <-uploadStatusChan
-	installStatusItem := <-installStatusChan
-	successfulInstallStatusItem := <-successfulSolutionInstallStatusChan
-
-	// Return the received status objects
-	return uploadStatusItem, installStatusItem, successfulInstallStatusItem
+func fetchInstallationAndReleaseObjects(solutionReleaseObjectQuery string, solutionInstallObjectQuery string, successfulSolutionInstallObjectQuery string, requestHeaders map[string]string) (StatusItem, StatusItem, StatusItem, error) {
+	urls := []string{
+		fmt.Sprintf(getSolutionReleaseUrl(), solutionReleaseObjectQuery),
+		fmt.Sprintf(getSolutionInstallUrl(), solutionInstallObjectQuery),
+		fmt.Sprintf(getSolutionInstallUrl(), successfulSolutionInstallObjectQuery),
+	}
+	items := make([]StatusItem, len(urls))
+	errs := make([]error, len(urls))
+
+	// Launch goroutines to fetch status objects in parallel and wait for all of them
+	var wg sync.WaitGroup
+	for i, objectUrl := range urls {
+		wg.Add(1)
+		go func(i int, objectUrl string) {
+			defer wg.Done()
+			items[i], errs[i] = fetchObjects(objectUrl, requestHeaders)
+		}(i, objectUrl)
+	}
+	wg.Wait()
 
+	// Return the received status objects (upload, install, successful install)
+	return items[0], items[1], items[2], errors.Join(errs...)
 }
 
 func getSolutionStatus(cmd *cobra.Command, args []string) error {
@@ -174,7 +173,10 @@ func getSolutionStatus(cmd *cobra.Command, args []string) error {
 	cfg := config.GetCurrentContext()
 
 	layerType := "TENANT"
-	solutionName := getSolutionNameFromArgs(cmd, args, "name")
+	solutionName, err := getSolutionNameFromArgs(cmd, args, "name")
+	if err != nil {
+		return err
+	}
 	solutionTag, _ := cmd.Flags().GetString("tag")
 
 	requestHeaders := map[string]string{
@@ -216,7 +218,10 @@ func getSolutionStatus(cmd *cobra.Command, args []string) error {
 			log.Warn("No tag provided, defaulting to stable tag when querying for objects")
 			solutionTag = "stable"
 		}
-		isSolutionDeleted, solutionDeletionData := checkIfSolutionDeleted(solutionName, solutionTag)
+		isSolutionDeleted, solutionDeletionData, deletionErr := checkIfSolutionDeleted(solutionName, solutionTag)
+		if deletionErr != nil {
+			return deletionErr
+		}
 		// If solution has been deleted previously, print out a helpful message to let the user know
 		// else throw an error
 		if isSolutionDeleted {
@@ -227,20 +232,23 @@ func getSolutionStatus(cmd *cobra.Command, args []string) error {
 			} else if solutionDeletionData.Status == "inProgress" {
 				output.PrintCmdStatus(cmd, fmt.Sprintf("Deletion for solution with name: %s and tag: %s currently in progress.  \nPlease wait until the deletion completes for an updated status.\n", solutionName, solutionTag))
 			} else {
-				log.Fatalf("Error fetching extensibility:solution object %q: %v", getSolutionObjectUrl(solutionID), err)
+				return fmt.Errorf("error fetching extensibility:solution object %q: %w", getSolutionObjectUrl(solutionID), err)
 			}
 			return nil
 		} else {
 			var httpErr *api.HttpStatusError
 			if errors.As(err, &httpErr) && httpErr.StatusCode == 404 {
-				log.Fatalf("Solution with name: %s and tag: %s not found", solutionName, solutionTag)
+				return clierror.New(clierror.NotFound, "solution with name: %s and tag: %s not found", solutionName, solutionTag)
 			} else {
-				log.Fatalf("Error fetching extensibility:solution object %q: %v", getSolutionObjectUrl(solutionID), err)
+				return fmt.Errorf("error fetching extensibility:solution object %q: %w", getSolutionObjectUrl(solutionID), err)
 			}
 		}
 	}
 
-	uploadStatusItem, installStatusItem, successfulInstallStatusItem := fetchInstallationAndReleaseObjects(solutionReleaseObjectQuery, solutionInstallObjectQuery, successfulSolutionInstallObjectQuery, requestHeaders)
+	uploadStatusItem, installStatusItem, successfulInstallStatusItem, err := fetchInstallationAndReleaseObjects(solutionReleaseObjectQuery, solutionInstallObjectQuery, successfulSolutionInstallObjectQuery, requestHeaders)
+	if err != nil {
+		return err
+	}
 
 	// process status & display
 	installStatusData := installStatusItem.StatusData
@@ -290,11 +298,13 @@ func getSolutionStatus(cmd *cobra.Command, args []string) error {
 	appendValue(fmt.Sprintf("%s Install Time", solutionInstallationMessagePrefix), installStatusData.InstallTime)
 	appendValue(fmt.Sprintf("%s Install Message", solutionInstallationMessagePrefix), installStatusData.InstallMessage)
 
-	output.PrintCmdOutputCustom(cmd, installStatusData, &output.Table{
+	if err := output.PrintCmdOutputCustom(cmd, installStatusData, &output.Table{
 		Headers: headers,
 		Lines:   [][]string{values},
 		Detail:  true,
-	})
+	}); err != nil {
+		return err
+	}
 
 	return nil
 }
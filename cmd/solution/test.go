@@ -36,7 +36,7 @@ var solutionTestCmd = &cobra.Command{
 	Short:            "Test Solution",
 	Long:             "This command allows the current tenant specified in the profile to run tests against an already deployed solution",
 	Example:          `  fsoc solution test`,
-	Run:              testSolution,
+	RunE:             testSolution,
 	TraverseChildren: true,
 }
 
@@ -46,7 +46,7 @@ var solutionTestStatusCmd = &cobra.Command{
 	Short:            "Status of Solution Test",
 	Long:             "This command allows the current tenant specified in the profile to check the status of a test-run already initiated via the `fsoc solution test` command",
 	Example:          ` fsoc solution test-status`,
-	Run:              testSolutionStatus,
+	RunE:             testSolutionStatus,
 	TraverseChildren: true,
 }
 
@@ -71,7 +71,7 @@ func getSolutionTestStatusCmd() *cobra.Command {
 // The command will try to read those files and replace file references in `test-objects.json` with actual file contents.
 // Once all this parsing is done, the command will prepare the payload for test-runner; Make http call to it and print the `test-run-id“ string that it gets from the test-runner.
 // The test-run-id returned by this command should be used to check status of the test using `fsoc solution test-status` command.
-func testSolution(cmd *cobra.Command, args []string) {
+func testSolution(cmd *cobra.Command, args []string) error {
 	var testBundleDir string
 	testBundlePath, _ := cmd.Flags().GetString("test-bundle")
 	initialDelay, _ := cmd.Flags().GetString("initial-delay")
@@ -81,7 +81,7 @@ func testSolution(cmd *cobra.Command, args []string) {
 	// Get Test Bundle Directory
 	currentDir, err := os.Getwd()
 	if err != nil {
-		log.Fatal(err.Error())
+		return err
 	}
 	if testBundlePath == "" {
 		fmt.Println("Supplied test-bundle path is empty. Using current directory to look for test-bundle.")
@@ -92,11 +92,11 @@ func testSolution(cmd *cobra.Command, args []string) {
 
 	// Read Test Objects JSON
 	if !isTestPackageRoot(testBundleDir) {
-		log.Fatalf("No test-objects file found in %q; please run this command in a directory with a test-objects file or use the --test-bundle flag", testBundleDir)
+		return fmt.Errorf("no test-objects file found in %q; please run this command in a directory with a test-objects file or use the --test-bundle flag", testBundleDir)
 	}
 	testObjects, err := getTestObjects(testBundleDir)
 	if err != nil {
-		log.Fatalf("Failed to read the test objects file in %q: %v", testBundleDir, err)
+		return fmt.Errorf("failed to read the test objects file in %q: %w", testBundleDir, err)
 	}
 
 	// Replace any file references in input, assertions with contents of those files
@@ -106,17 +106,17 @@ func testSolution(cmd *cobra.Command, args []string) {
 		if setup.Location != "" {
 			inputBytes, err := readFileLocation(fmt.Sprintf("%s/%s", testBundleDir, setup.Location))
 			if err != nil {
-				log.Fatalf("Failed to read file ref %q: %v", setup.Location, err)
+				return fmt.Errorf("failed to read file ref %q: %w", setup.Location, err)
 			}
 			inputCompactBytes := new(bytes.Buffer)
 			err = json.Compact(inputCompactBytes, inputBytes)
 			if err != nil {
-				log.Fatalf("JSON compact operation failed: %v", err)
+				return fmt.Errorf("JSON compact operation failed: %w", err)
 			}
 			var inputData interface{}
 			err = json.Unmarshal(inputCompactBytes.Bytes(), &inputData)
 			if err != nil {
-				log.Fatalf("JSON Unmarshal failed: %v", err)
+				return fmt.Errorf("JSON Unmarshal failed: %w", err)
 			}
 			setup.Input = inputData
 			testObj.Setup = setup
@@ -128,7 +128,7 @@ func testSolution(cmd *cobra.Command, args []string) {
 				if transform.Location != "" {
 					transformBytes, err := readFileLocation(fmt.Sprintf("%s/%s", testBundleDir, transform.Location))
 					if err != nil {
-						log.Fatalf("Failed to load JSON in place of file ref %q: %v", transform.Location, err)
+						return fmt.Errorf("failed to load JSON in place of file ref %q: %w", transform.Location, err)
 					}
 					transformStr := string(transformBytes)
 					transformStr = sanitizeString(transformStr)
@@ -145,21 +145,21 @@ func testSolution(cmd *cobra.Command, args []string) {
 	if initialDelay != "" {
 		testObjectsInt, err := strconv.Atoi(initialDelay)
 		if err != nil {
-			log.Fatalf("Error while reading integer value from string %s: %v", initialDelay, err)
+			return fmt.Errorf("error while reading integer value from string %s: %w", initialDelay, err)
 		}
 		testObjects.InitialDelay = testObjectsInt
 	}
 	if maxRetryCount != "" {
 		maxRetryCountInt, err := strconv.Atoi(maxRetryCount)
 		if err != nil {
-			log.Fatalf("Error while reading integer value from string %s: %v", maxRetryCount, err)
+			return fmt.Errorf("error while reading integer value from string %s: %w", maxRetryCount, err)
 		}
 		testObjects.MaxRetryCount = maxRetryCountInt
 	}
 	if retryDelay != "" {
 		retryDelayInt, err := strconv.Atoi(retryDelay)
 		if err != nil {
-			log.Fatalf("Error while reading integer value from string %s: %v", retryDelay, err)
+			return fmt.Errorf("error while reading integer value from string %s: %w", retryDelay, err)
 		}
 		testObjects.RetryDelay = retryDelayInt
 	}
@@ -168,28 +168,29 @@ func testSolution(cmd *cobra.Command, args []string) {
 	var res SolutionTestResult
 	err = api.JSONPut(getSolutionTestUrl(), testObjects, &res, nil)
 	if err != nil {
-		log.Fatalf("Solution Test request failed: %v", err)
+		return fmt.Errorf("solution test request failed: %w", err)
 	}
 	testId := res.ID
 	output.PrintCmdStatus(cmd, fmt.Sprintf("Solution Test data sent to test-runner successfully. Test ID - %s", testId))
+	return nil
 }
 
 // Implementation for `fsoc solution test-status` command.
 // This command takes 1 mandatory argument, called `test-run-id` which is a string that represents a solution test-run already initiated via `fsoc solution test` command.
 // It is therefore recommended that `fsoc solution test` command is run before this command, and the `test-run-id` returned from it is used here.
 // The command will read the supplied test-run-id; Call test-runner server-side component, that runs the solution tests; Get the latest status of the test-run and print it in a user-friendly notation.
-func testSolutionStatus(cmd *cobra.Command, args []string) {
+func testSolutionStatus(cmd *cobra.Command, args []string) error {
 	// Read the test-run-id
 	suppliedTestId, _ := cmd.Flags().GetString("test-run-id")
 	if suppliedTestId == "" {
-		log.Fatal("Supplied test-run-id is null or empty.")
+		return fmt.Errorf("supplied test-run-id is null or empty")
 	}
 
 	// Send the test-run-id to the test-runner and print the response
 	var res SolutionTestStatusResult
 	err := api.JSONGet(getSolutionTestStatusUrl(suppliedTestId), &res, nil)
 	if err != nil {
-		log.Fatalf("Solution Test Status request failed: %v", err)
+		return fmt.Errorf("solution test status request failed: %w", err)
 	}
 
 	// Print the result in JSON format
@@ -202,9 +203,10 @@ func testSolutionStatus(cmd *cobra.Command, args []string) {
 	}
 	resJsonBytes, err := json.MarshalIndent(res, "", "  ")
 	if err != nil {
-		log.Fatalf("JSON marshal failed: %v", err)
+		return fmt.Errorf("JSON marshal failed: %w", err)
 	}
 	output.PrintCmdStatus(cmd, fmt.Sprintf("Solution Test Status received for test-run-id (%s): \n%v", suppliedTestId, string(resJsonBytes)))
+	return nil
 }
 
 func isTestPackageRoot(path string) bool {
@@ -15,11 +15,12 @@
 package solution
 
 type DashuiTemplate struct {
-	Kind    string      `json:"kind" yaml:"kind"`
-	Name    string      `json:"name" yaml:"name"`
-	Target  string      `json:"target" yaml:"target"`
-	View    string      `json:"view" yaml:"view"`
-	Element interface{} `json:"element" yaml:"element"`
+	Kind                string      `json:"kind" yaml:"kind"`
+	Name                string      `json:"name" yaml:"name"`
+	Target              string      `json:"target" yaml:"target"`
+	View                string      `json:"view" yaml:"view"`
+	RequiredEntityTypes []string    `json:"requiredEntityTypes,omitempty" yaml:"requiredEntityTypes,omitempty"`
+	Element             interface{} `json:"element" yaml:"element"`
 }
 
 type DashuiTemplatePropsExtension struct {
@@ -15,13 +15,12 @@
 package solution
 
 import (
+	"errors"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
-	"github.com/apex/log"
 	"gopkg.in/yaml.v3"
 )
 
@@ -117,91 +116,102 @@ func (manifest *Manifest) HasPseudoIsolation() bool {
 	return strings.Contains(manifest.Name, "${")
 }
 
-func (manifest *Manifest) GetFmmEntities() []*FmmEntity {
-	fmmEntities := make([]*FmmEntity, 0)
-	entityComponentDefs := manifest.GetComponentDefs("fmm:entity")
-	for _, compDef := range entityComponentDefs {
-		if compDef.ObjectsFile != "" {
-			filePath := compDef.ObjectsFile
-			fmmEntities = append(fmmEntities, getFmmEntitiesFromFile(filePath)...)
-		}
-		if compDef.ObjectsDir != "" {
-			filePath := compDef.ObjectsDir
-			err := filepath.Walk(filePath,
-				func(path string, info os.FileInfo, err error) error {
-					if err != nil {
-						return err
-					}
-					if strings.Contains(path, ".json") || strings.Contains(path, ".yaml") {
-						fmmEntities = append(fmmEntities, getFmmEntitiesFromFile(path)...)
-					}
-					return nil
-				})
-			if err != nil {
-				log.Fatalf("Error traversing the directory: %v", err)
-			}
-		}
+// GetFmmEntities reads the solution's entity definitions. It must be called with the solution root
+// as the current directory.
+func (manifest *Manifest) GetFmmEntities() ([]*FmmEntity, error) {
+	return getComponentObjects(manifest, "fmm:entity", func() *FmmEntity {
+		return &FmmEntity{FmmTypeDef: &FmmTypeDef{}}
+	})
+}
 
-	}
-	return fmmEntities
+// GetFmmMetrics reads the solution's metric definitions. It must be called with the solution root
+// as the current directory.
+func (manifest *Manifest) GetFmmMetrics() ([]*FmmMetric, error) {
+	return getComponentObjects(manifest, "fmm:metric", func() *FmmMetric {
+		return &FmmMetric{FmmTypeDef: &FmmTypeDef{}}
+	})
 }
 
-func (manifest *Manifest) GetFmmMetrics() []*FmmMetric {
-	fmmMetrics := make([]*FmmMetric, 0)
-	entityComponentDefs := manifest.GetComponentDefs("fmm:metric")
-	for _, compDef := range entityComponentDefs {
-		if compDef.ObjectsFile != "" {
-			filePath := compDef.ObjectsFile
-			fmmMetrics = append(fmmMetrics, getFmmMetricsFromFile(filePath)...)
-		}
-		if compDef.ObjectsDir != "" {
-			filePath := compDef.ObjectsDir
-			err := filepath.Walk(filePath,
-				func(path string, info os.FileInfo, err error) error {
-					if err != nil {
-						return err
-					}
-					if strings.Contains(path, ".json") || strings.Contains(path, ".yaml") {
-						fmmMetrics = append(fmmMetrics, getFmmMetricsFromFile(path)...)
-					}
-					return nil
-				})
-			if err != nil {
-				log.Fatalf("Error traversing the directory: %v", err)
-			}
-		}
+// GetFmmEvents reads the solution's event definitions. It must be called with the solution root
+// as the current directory.
+func (manifest *Manifest) GetFmmEvents() ([]*FmmEvent, error) {
+	return getComponentObjects(manifest, "fmm:event", func() *FmmEvent {
+		return &FmmEvent{FmmTypeDef: &FmmTypeDef{}}
+	})
+}
 
-	}
-	return fmmMetrics
+// GetFmmResourceMappings reads the solution's resource mappings. It must be called with the solution root
+// as the current directory.
+func (manifest *Manifest) GetFmmResourceMappings() ([]*FmmResourceMapping, error) {
+	return getComponentObjects(manifest, "fmm:resourceMapping", func() *FmmResourceMapping {
+		return &FmmResourceMapping{FmmTypeDef: &FmmTypeDef{}}
+	})
+}
+
+// GetFmmAssociationDeclarations reads the solution's association declarations. It must be called with the
+// solution root as the current directory.
+func (manifest *Manifest) GetFmmAssociationDeclarations() ([]*FmmAssociationDeclaration, error) {
+	return getComponentObjects(manifest, "fmm:associationDeclaration", func() *FmmAssociationDeclaration {
+		return &FmmAssociationDeclaration{FmmTypeDef: &FmmTypeDef{}}
+	})
 }
 
-func (manifest *Manifest) GetFmmEvents() []*FmmEvent {
-	fmmEvents := make([]*FmmEvent, 0)
-	entityComponentDefs := manifest.GetComponentDefs("fmm:event")
-	for _, compDef := range entityComponentDefs {
-		if compDef.ObjectsFile != "" {
-			filePath := compDef.ObjectsFile
-			fmmEvents = append(fmmEvents, getFmmEventsFromFile(filePath)...)
+// getComponentObjects reads the objects of a component type from the files and directories listed in the
+// manifest. Files that can't be read or parsed are skipped; the returned error joins the errors of all of them.
+func getComponentObjects[T any](manifest *Manifest, typeName string, newObject func() *T) ([]*T, error) {
+	objects := make([]*T, 0)
+	var errs []error
+	addFile := func(filePath string) {
+		fileObjects, err := getComponentObjectsFromFile(filePath, typeName, newObject)
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		objects = append(objects, fileObjects...)
+	}
+	for _, objDef := range manifest.GetComponentDefs(typeName) {
+		if objDef.ObjectsFile != "" {
+			addFile(objDef.ObjectsFile)
 		}
-		if compDef.ObjectsDir != "" {
-			filePath := compDef.ObjectsDir
-			err := filepath.Walk(filePath,
+		if objDef.ObjectsDir != "" {
+			err := filepath.Walk(objDef.ObjectsDir,
 				func(path string, info os.FileInfo, err error) error {
 					if err != nil {
 						return err
 					}
 					if strings.Contains(path, ".json") || strings.Contains(path, ".yaml") {
-						fmmEvents = append(fmmEvents, getFmmEventsFromFile(path)...)
+						addFile(path)
 					}
 					return nil
 				})
 			if err != nil {
-				log.Fatalf("Error traversing the directory: %v", err)
+				errs = append(errs, fmt.Errorf("error traversing the directory %q: %w", objDef.ObjectsDir, err))
 			}
 		}
+	}
+	return objects, errors.Join(errs...)
+}
+
+// getComponentObjectsFromFile reads the objects of a component type from a file containing either a single
+// object or an array of objects
+func getComponentObjectsFromFile[T any](filePath string, typeName string, newObject func() *T) ([]*T, error) {
+	objDefBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("can't read the %q file: %w", filePath, err)
+	}
 
+	if strings.Index(string(objDefBytes), "[") == 0 {
+		objectsArray := make([]*T, 0)
+		if err := yaml.Unmarshal(objDefBytes, &objectsArray); err != nil {
+			return nil, fmt.Errorf("can't parse an array of %v objects from the %q file: %w", typeName, filePath, err)
+		}
+		return objectsArray, nil
+	}
+	object := newObject()
+	if err := yaml.Unmarshal(objDefBytes, object); err != nil {
+		return nil, fmt.Errorf("can't parse a %v object from the %q file: %w", typeName, filePath, err)
 	}
-	return fmmEvents
+	return []*T{object}, nil
 }
 
 func (manifest *Manifest) CheckDependencyExists(solutionName string) bool {
@@ -248,146 +258,10 @@ func (manifest *Manifest) GetComponentDefs(typeName string) []ComponentDef {
 	return componentDefs
 }
 
-func (manifest *Manifest) GetDashuiTemplates() []*DashuiTemplate {
-	dashuiTemplates := make([]*DashuiTemplate, 0)
-	objectDefs := manifest.GetComponentDefs("dashui:template")
-	for _, objDef := range objectDefs {
-		if objDef.ObjectsFile != "" {
-			filePath := objDef.ObjectsFile
-			dashuiTemplates = append(dashuiTemplates, getDashuiTemplatesFromFile(filePath)...)
-		}
-		if objDef.ObjectsDir != "" {
-			filePath := objDef.ObjectsDir
-			err := filepath.Walk(filePath,
-				func(path string, info os.FileInfo, err error) error {
-					if err != nil {
-						return err
-					}
-					if strings.Contains(path, ".json") || strings.Contains(path, ".yaml") {
-						dashuiTemplates = append(dashuiTemplates, getDashuiTemplatesFromFile(path)...)
-					}
-					return nil
-				})
-			if err != nil {
-				log.Fatalf("Error traversing the directory: %v", err)
-			}
-		}
-
-	}
-	return dashuiTemplates
-}
-
-func getDashuiTemplatesFromFile(filePath string) []*DashuiTemplate {
-	dashuiTemplates := make([]*DashuiTemplate, 0)
-	objDefFile := openFile(filePath)
-	defer objDefFile.Close()
-	objDefBytes, _ := io.ReadAll(objDefFile)
-	objDefContent := string(objDefBytes)
-
-	if strings.Index(objDefContent, "[") == 0 {
-		objectsArray := make([]*DashuiTemplate, 0)
-		//err := json.Unmarshal(objDefBytes, &objectsArray)
-		err := yaml.Unmarshal(objDefBytes, &objectsArray)
-		if err != nil {
-			log.Fatalf("Can't parse an array of dashui:template definition objects from the %q file:\n %v", filePath, err)
-		}
-		dashuiTemplates = append(dashuiTemplates, objectsArray...)
-	} else {
-		var object *DashuiTemplate
-		// err := json.Unmarshal(objDefBytes, &object)
-		err := yaml.Unmarshal(objDefBytes, &object)
-		if err != nil {
-			log.Fatalf("Can't parse dashui:template definition objects from the %q file:\n %v ", filePath, err)
-		}
-		dashuiTemplates = append(dashuiTemplates, object)
-	}
-	return dashuiTemplates
-}
-
-func getFmmEntitiesFromFile(filePath string) []*FmmEntity {
-	fmmEntities := make([]*FmmEntity, 0)
-	entityDefFile := openFile(filePath)
-	defer entityDefFile.Close()
-	entityDefBytes, _ := io.ReadAll(entityDefFile)
-	entityDefContent := string(entityDefBytes)
-
-	if strings.Index(entityDefContent, "[") == 0 {
-		entitiesArray := make([]*FmmEntity, 0)
-		err := yaml.Unmarshal(entityDefBytes, &entitiesArray)
-		if err != nil {
-			log.Fatalf("Can't parse an array of entity definition objects from the %q file:\n %v", filePath, err)
-		}
-		fmmEntities = append(fmmEntities, entitiesArray...)
-	} else {
-		entity := &FmmEntity{
-			FmmTypeDef: &FmmTypeDef{},
-		}
-		err := yaml.Unmarshal(entityDefBytes, &entity)
-		if err != nil {
-			log.Fatalf("Can't parse an entity definition objects from the %q file:\n %v", filePath, err)
-		}
-		fmmEntities = append(fmmEntities, entity)
-	}
-	return fmmEntities
-}
-
-func getFmmMetricsFromFile(filePath string) []*FmmMetric {
-	fmmMetrics := make([]*FmmMetric, 0)
-	metricDefFile := openFile(filePath)
-	defer metricDefFile.Close()
-	metricDefBytes, _ := io.ReadAll(metricDefFile)
-	metricDefContent := string(metricDefBytes)
-
-	if strings.Index(metricDefContent, "[") == 0 {
-		objectsArray := make([]*FmmMetric, 0)
-		// err := json.Unmarshal(metricDefBytes, &objectsArray)
-		err := yaml.Unmarshal(metricDefBytes, &objectsArray)
-		if err != nil {
-			log.Fatalf("Can't parse an array of metric definition objects from the %q file:\n %v", filePath, err)
-		}
-		fmmMetrics = append(fmmMetrics, objectsArray...)
-	} else {
-		// err := json.Unmarshal(metricDefBytes, &object)
-		object := &FmmMetric{
-			FmmTypeDef: &FmmTypeDef{},
-		}
-
-		err := yaml.Unmarshal(metricDefBytes, &object)
-		if err != nil {
-			log.Fatalf("Can't parse a metric definition objects from the %q file:\n %v ", filePath, err)
-		}
-		fmmMetrics = append(fmmMetrics, object)
-	}
-	return fmmMetrics
-}
-
-func getFmmEventsFromFile(filePath string) []*FmmEvent {
-	fmmEvents := make([]*FmmEvent, 0)
-	eventsDefFile := openFile(filePath)
-	defer eventsDefFile.Close()
-	eventDefBytes, _ := io.ReadAll(eventsDefFile)
-	eventDefContent := string(eventDefBytes)
-
-	if strings.Index(eventDefContent, "[") == 0 {
-		objectsArray := make([]*FmmEvent, 0)
-		// err := json.Unmarshal(eventDefBytes, &objectsArray)
-		err := yaml.Unmarshal(eventDefBytes, &objectsArray)
-
-		if err != nil {
-			log.Fatalf("Can't parse an array of event definition objects from the %q file:\n %v", filePath, err)
-		}
-		fmmEvents = append(fmmEvents, objectsArray...)
-	} else {
-		object := &FmmEvent{
-			FmmTypeDef: &FmmTypeDef{},
-		}
-
-		// err := json.Unmarshal(eventDefBytes, &object)
-		err := yaml.Unmarshal(eventDefBytes, &object)
-		if err != nil {
-			log.Fatalf("Can't parse a event` definition objects from the %q file:\n %v ", filePath, err)
-		}
-		fmmEvents = append(fmmEvents, object)
-	}
-	return fmmEvents
+// GetDashuiTemplates reads the solution's dashui templates. It must be called with the solution root
+// as the current directory.
+func (manifest *Manifest) GetDashuiTemplates() ([]*DashuiTemplate, error) {
+	return getComponentObjects(manifest, "dashui:template", func() *DashuiTemplate {
+		return &DashuiTemplate{}
+	})
 }
@@ -16,6 +16,7 @@ package solution
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -27,6 +28,7 @@ import (
 	"github.com/apex/log"
 	"github.com/spf13/cobra"
 
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
 	"github.com/cisco-open/fsoc/config"
 	"github.com/cisco-open/fsoc/output"
 	"github.com/cisco-open/fsoc/platform/api"
@@ -60,17 +62,18 @@ func WithSolutionInstallVersion(version string) uploadOption {
 	}
 }
 
-func bumpSolutionVersionInManifest(cmd *cobra.Command, manifest *Manifest, manifestPath string) {
+func bumpSolutionVersionInManifest(cmd *cobra.Command, manifest *Manifest, manifestPath string) error {
 	if err := bumpManifestPatchVersion(manifest); err != nil {
-		log.Fatal(err.Error())
+		return err
 	}
 	if err := saveSolutionManifest(manifestPath, manifest); err != nil {
-		log.Fatalf("Failed to update solution manifest in %q after version bump: %v", manifestPath, err)
+		return fmt.Errorf("failed to update solution manifest in %q after version bump: %w", manifestPath, err)
 	}
 	output.PrintCmdStatus(cmd, fmt.Sprintf("Solution version updated to %v\n", manifest.SolutionVersion))
+	return nil
 }
 
-func uploadSolution(cmd *cobra.Command, push bool, options ...uploadOption) {
+func uploadSolution(cmd *cobra.Command, push bool, options ...uploadOption) error {
 	opts := uploadOptions{}
 	for _, option := range options {
 		option(&opts)
@@ -101,7 +104,7 @@ func uploadSolution(cmd *cobra.Command, push bool, options ...uploadOption) {
 	// prepare tag-related values
 	solutionTag, err := getEmbeddedTag(cmd, solutionRootDirectory) // flag, env var or .tag file
 	if err != nil {
-		log.Fatalf("Failed to get solution tag: %v", err)
+		return fmt.Errorf("failed to get solution tag: %w", err)
 	}
 	requestedSolutionTag := solutionTag // mostly for display, as solutionTagFlag may be changed to comply with supported API values (pseudo-isolation only)
 	// TODO remove `requestedSolutionTag` when solution pseudo-isolation is removed
@@ -109,7 +112,10 @@ func uploadSolution(cmd *cobra.Command, push bool, options ...uploadOption) {
 	// prepare archive if needed
 	solutionAlreadyZipped = solutionBundlePath != ""
 	if solutionAlreadyZipped {
-		solutionBundlePath = absolutizePath(solutionBundlePath)
+		solutionBundlePath, err = absolutizePath(solutionBundlePath)
+		if err != nil {
+			return err
+		}
 		solutionFileName := filepath.Base(solutionBundlePath)
 		// handle case where we are passing the solution name as a flag argument
 		if solutionNameFromOptions != "" {
@@ -127,25 +133,27 @@ func uploadSolution(cmd *cobra.Command, push bool, options ...uploadOption) {
 		if solutionRootDirectory == "" {
 			solutionRootDirectory, err = os.Getwd()
 			if err != nil {
-				log.Fatal(err.Error())
+				return err
 			}
 		} else {
 			solutionRootDirectory, err = filepath.Abs(solutionRootDirectory)
 			if err != nil {
-				log.Fatal(err.Error())
+				return err
 			}
 		}
 		if !isSolutionPackageRoot(solutionRootDirectory) {
-			log.Fatalf("No solution manifest found in %q; please use -d or --solution-bundle flag", solutionRootDirectory)
+			return fmt.Errorf("no solution manifest found in %q; please use -d or --solution-bundle flag", solutionRootDirectory)
 		}
 
 		// get manifest, bump version if needed
 		manifest, err = getSolutionManifest(solutionRootDirectory)
 		if err != nil {
-			log.Fatalf("Failed to read the solution manifest from %q: %v", solutionRootDirectory, err)
+			return fmt.Errorf("failed to read the solution manifest from %q: %w", solutionRootDirectory, err)
 		}
 		if bumpFlag {
-			bumpSolutionVersionInManifest(cmd, manifest, solutionRootDirectory)
+			if err := bumpSolutionVersionInManifest(cmd, manifest, solutionRootDirectory); err != nil {
+				return err
+			}
 		}
 
 		// pseudo-isolate if needed (update tag values to reflect env var and/or env file settings)
@@ -153,7 +161,7 @@ func uploadSolution(cmd *cobra.Command, push bool, options ...uploadOption) {
 		solutionTag = tag
 		requestedSolutionTag = tag
 		if err != nil {
-			log.Fatalf("Failed to isolate solution with tag: %v", err)
+			return fmt.Errorf("failed to isolate solution with tag: %w", err)
 		}
 		if solutionIsolateDirectory != solutionRootDirectory { // if pseudo-isolated, post-process
 			// set root directory to the isolated version's root
@@ -162,20 +170,17 @@ func uploadSolution(cmd *cobra.Command, push bool, options ...uploadOption) {
 			// re-read manifest, to get the isolated name
 			manifest, err = getSolutionManifest(solutionRootDirectory)
 			if err != nil {
-				log.Fatalf("Failed to read the solution manifest from %q: %v", solutionRootDirectory, err)
+				return fmt.Errorf("failed to read the solution manifest from %q: %w", solutionRootDirectory, err)
 			}
 
 			// update tag to use supported values
-			if solutionTag != "stable" {
-				if cfg.EnvType != "dev" {
-					solutionTag = "dev" // TODO: use tag value as-is once free-form values are supported by API
-				} else {
-					solutionTag = "stable" // TODO: use tag value as-is once free-form values are supported by API
-				}
-			}
+			solutionTag = pseudoIsolationApiTag(solutionTag)
 		}
 		// create archive
-		solutionArchive := generateZip(cmd, solutionRootDirectory, "")
+		solutionArchive, err := createSolutionZip(cmd, solutionRootDirectory, "")
+		if err != nil {
+			return err
+		}
 		solutionBundlePath = solutionArchive.Name()
 
 		// fill in details
@@ -206,46 +211,14 @@ func uploadSolution(cmd *cobra.Command, push bool, options ...uploadOption) {
 	log.WithFields(log.Fields(logFields)).Info("Solution details")
 
 	// --- Upload archive
-
-	// read zip file into a buffer
-	file, err := os.Open(solutionBundlePath)
-	if err != nil {
-		log.Fatalf("Failed to open file %q: %v", solutionBundlePath, err)
-	}
-	defer file.Close()
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-	fw, err := writer.CreateFormFile("file", solutionBundlePath)
-	if err != nil {
-		log.Fatalf("Failed to create form file: %v", err)
-	}
-	_, err = io.Copy(fw, file)
+	res, err := postSolutionArchive(solutionBundlePath, solutionTag, push)
 	if err != nil {
-		log.Fatalf("Failed to copy file %q into file writer: %v", solutionBundlePath, err)
-	}
-	writer.Close()
-
-	// send request
-	var operation string
-	if push {
-		operation = "UPLOAD"
-	} else {
-		operation = "VALIDATE"
-	}
-	headers := map[string]string{
-		"tag":          solutionTag,
-		"operation":    operation,
-		"Content-Type": writer.FormDataContentType(),
-	}
-	var res Result
-	err = api.HTTPPost(getSolutionPushUrl(), body.Bytes(), &res, &api.Options{Headers: headers})
-	if err != nil {
-		log.Fatalf("Solution %s command failed: %v", operation, err)
+		return err
 	}
 	if !push && !res.Valid {
 		message := getSolutionValidationErrorsString(res.Errors.Total, res.Errors)
 		output.PrintCmdStatus(cmd, message)
-		log.Fatalf("%d error(s) found while validating the solution", res.Errors.Total)
+		return clierror.New(clierror.Validation, "%d error(s) found while validating the solution", res.Errors.Total)
 	}
 
 	// display result
@@ -262,13 +235,13 @@ func uploadSolution(cmd *cobra.Command, push bool, options ...uploadOption) {
 		}
 		log.WithField("solution", solutionObjName).Info("Subscribing to solution")
 		layerID := cfg.Tenant
-		headers = map[string]string{
+		headers := map[string]string{
 			"layer-type": "TENANT",
 			"layer-id":   layerID,
 		}
 		for i := 1; i <= MAX_SUBSCRIBE_TRIES; i++ {
 			url := getSolutionObjectUrl(solutionObjName)
-			err = api.JSONPatch(url, &subscriptionStruct{IsSubscribed: true}, &res, &api.Options{Headers: headers, ExpectedErrors: []int{404}})
+			err = api.JSONPatch(url, &subscriptionStruct{IsSubscribed: true}, res, &api.Options{Headers: headers, ExpectedErrors: []int{404}})
 			if err == nil {
 				output.PrintCmdStatus(cmd, fmt.Sprintf("Tenant %s has successfully subscribed to solution %s\n", layerID, solutionObjName))
 				break
@@ -276,7 +249,7 @@ func uploadSolution(cmd *cobra.Command, push bool, options ...uploadOption) {
 			time.Sleep(time.Second * time.Duration(i))
 		}
 		if err != nil {
-			log.Fatalf("Solution command failed: %v", err)
+			return fmt.Errorf("solution command failed: %w", err)
 		}
 
 	}
@@ -291,30 +264,107 @@ func uploadSolution(cmd *cobra.Command, push bool, options ...uploadOption) {
 		}
 		output.PrintCmdStatus(cmd, fmt.Sprintf("Waiting %s for %s to be installed...\n", duration, solutionDisplayText))
 
-		filter := fmt.Sprintf(`data.solutionName eq "%s" and data.solutionVersion eq "%s" and data.tag eq "%s"`, solutionName, solutionVersion, solutionTag)
-		query := fmt.Sprintf("?order=%s&filter=%s&max=1", url.QueryEscape("desc"), url.QueryEscape(filter))
-
-		headers := map[string]string{
-			"layer-type": "TENANT",
-			"layer-id":   config.GetCurrentContext().Tenant,
-		}
-		var statusData StatusData
-		waitStartTime := time.Now()
-		for statusData.SolutionVersion != solutionVersion {
-			if waitFlag > 0 {
-				if time.Since(waitStartTime).Seconds() > float64(waitFlag) {
-					log.Fatalf("Failed to validate %s was installed: timed out", solutionDisplayText)
-				}
-			}
-			status := getObjects(fmt.Sprintf(getSolutionInstallUrl(), query), headers)
-			statusData = status.StatusData
-			time.Sleep(3 * time.Second)
+		statusData, err := waitForSolutionInstall(cmd, solutionName, solutionVersion, solutionTag, waitFlag, nil)
+		if err != nil {
+			return fmt.Errorf("failed to validate %s was installed: %w", solutionDisplayText, err)
 		}
 		if !statusData.SuccessfulInstall {
-			log.Fatalf("Failed to install %s: %s", solutionDisplayText, statusData.InstallMessage)
+			return fmt.Errorf("failed to install %s: %s", solutionDisplayText, statusData.InstallMessage)
 		}
 		output.PrintCmdStatus(cmd, fmt.Sprintf("Installed %v successfully.\n", solutionDisplayText))
 	}
+	return nil
+}
+
+// pseudoIsolationApiTag returns the tag to use in the API calls for a pseudo-isolated solution,
+// since the API supports only the "stable" and "dev" tags for such solutions
+func pseudoIsolationApiTag(tag string) string {
+	if tag == "stable" {
+		return tag
+	}
+	if config.GetCurrentContext().EnvType != "dev" {
+		return "dev" // TODO: use tag value as-is once free-form values are supported by API
+	}
+	return "stable" // TODO: use tag value as-is once free-form values are supported by API
+}
+
+// postSolutionArchive uploads a solution archive for validation only or for deployment (push)
+func postSolutionArchive(solutionBundlePath string, solutionTag string, push bool) (*Result, error) {
+	// read zip file into a buffer
+	file, err := os.Open(solutionBundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %q: %w", solutionBundlePath, err)
+	}
+	defer file.Close()
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	fw, err := writer.CreateFormFile("file", solutionBundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create form file: %w", err)
+	}
+	_, err = io.Copy(fw, file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy file %q into file writer: %w", solutionBundlePath, err)
+	}
+	writer.Close()
+
+	// send request
+	var operation string
+	if push {
+		operation = "UPLOAD"
+	} else {
+		operation = "VALIDATE"
+	}
+	headers := map[string]string{
+		"tag":          solutionTag,
+		"operation":    operation,
+		"Content-Type": writer.FormDataContentType(),
+	}
+	var res Result
+	err = api.HTTPPost(getSolutionPushUrl(), body.Bytes(), &res, &api.Options{Headers: headers})
+	if err != nil {
+		return nil, fmt.Errorf("solution %s command failed: %w", operation, err)
+	}
+	return &res, nil
+}
+
+// waitForSolutionInstall waits until the installation of the solution version completes, up to
+// waitSeconds (0 to wait indefinitely), and returns its status. onStatus, if not nil, is called
+// with the latest installation status each time it is checked.
+func waitForSolutionInstall(cmd *cobra.Command, solutionName, solutionVersion, solutionTag string, waitSeconds int, onStatus func(StatusData)) (StatusData, error) {
+	filter := fmt.Sprintf(`data.solutionName eq "%s" and data.solutionVersion eq "%s" and data.tag eq "%s"`, solutionName, solutionVersion, solutionTag)
+	query := fmt.Sprintf("?order=%s&filter=%s&max=1", url.QueryEscape("desc"), url.QueryEscape(filter))
+
+	headers := map[string]string{
+		"layer-type": "TENANT",
+		"layer-id":   config.GetCurrentContext().Tenant,
+	}
+	var statusData StatusData
+	waitStartTime := time.Now()
+	for statusData.SolutionVersion != solutionVersion {
+		if waitSeconds > 0 {
+			if time.Since(waitStartTime).Seconds() > float64(waitSeconds) {
+				return statusData, clierror.New(clierror.Timeout, "timed out")
+			}
+		}
+		status, err := fetchObjects(fmt.Sprintf(getSolutionInstallUrl(), query), headers)
+		if err != nil {
+			return statusData, err
+		}
+		statusData = status.StatusData
+		if onStatus != nil {
+			onStatus(statusData)
+		}
+		if statusData.SolutionVersion == solutionVersion {
+			break
+		}
+		select {
+		case <-time.After(3 * time.Second):
+		case <-cmd.Context().Done():
+			return statusData, fmt.Errorf("stopped waiting: %w. Installation continues, please check status for outcome", context.Cause(cmd.Context()))
+		}
+	}
+	return statusData, nil
 }
 
 func getSolutionValidationErrorsString(total int, errors Errors) string {
@@ -15,12 +15,14 @@
 package solution
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
 	"path/filepath"
 	"reflect"
 	"strings"
+	"sync"
 
 	"github.com/apex/log"
 	"github.com/spf13/cobra"
@@ -38,7 +40,7 @@ var solutionZapCmd = &cobra.Command{
 This is for the purpose of cleaning up a solution by removing the knowledge types and knowledge objects
 associated with it.  Use this command with caution.`,
 	Example:          `  fsoc solution zap mysolution`,
-	Run:              zapSolution,
+	RunE:             zapSolution,
 	TraverseChildren: true,
 }
 
@@ -57,7 +59,7 @@ func getSolutionZapCmd() *cobra.Command {
 	return solutionZapCmd
 }
 
-func zapSolution(cmd *cobra.Command, args []string) {
+func zapSolution(cmd *cobra.Command, args []string) error {
 	var confirmationAnswer string
 	var solutionZipPath string
 	var solutionId string
@@ -65,14 +67,15 @@ func zapSolution(cmd *cobra.Command, args []string) {
 	var lastSolutionInstallVersion string
 	var solutionName string
 
-	solutionInstallObjectChan := make(chan StatusItem)
-	solutionObjectChan := make(chan ExtensibilitySolutionObjectData)
-
 	cfg := config.GetCurrentContext()
 	solutionTag, _ := cmd.Flags().GetString("tag")
 	skipConfirmationMessage, _ := cmd.Flags().GetBool("yes")
 
-	solutionName = getSolutionNameFromArgs(cmd, args, "")
+	var err error
+	solutionName, err = getSolutionNameFromArgs(cmd, args, "")
+	if err != nil {
+		return err
+	}
 	solutionName = strings.ToLower(solutionName)
 
 	if !skipConfirmationMessage {
@@ -80,7 +83,7 @@ func zapSolution(cmd *cobra.Command, args []string) {
 		fmt.Scanln(&confirmationAnswer)
 
 		if confirmationAnswer != solutionName {
-			log.Fatal("Solution zap not confirmed, exiting command")
+			return fmt.Errorf("solution zap not confirmed, exiting command")
 		}
 	}
 
@@ -92,7 +95,7 @@ func zapSolution(cmd *cobra.Command, args []string) {
 	if solutionTag == "dev" {
 		solutionId = solutionName
 	} else if solutionTag == "stable" {
-		log.Fatal("Error: stable solutions are not able to be zapped.  Please specify a tag other that 'stable'")
+		return fmt.Errorf("stable solutions are not able to be zapped; please specify a tag other that 'stable'")
 	} else {
 		solutionId = fmt.Sprintf("%s.%s", solutionName, solutionTag)
 	}
@@ -100,21 +103,27 @@ func zapSolution(cmd *cobra.Command, args []string) {
 	solutionInstallObjectFilterQuery := fmt.Sprintf(`data.solutionID eq "%s" and data.tag eq "%s"`, solutionId, solutionTag)
 	solutionInstallObjectQuery = fmt.Sprintf("?order=%s&filter=%s&max=1", url.QueryEscape("desc"), url.QueryEscape(solutionInstallObjectFilterQuery))
 
+	var solutionInstallObject StatusItem
+	var solutionObject ExtensibilitySolutionObjectData
+	var installErr, solutionErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
 	go func() {
-		solutionInstallObjectChan <- getObjects(fmt.Sprintf(getSolutionInstallUrl(), solutionInstallObjectQuery), headers)
+		defer wg.Done()
+		solutionInstallObject, installErr = fetchObjects(fmt.Sprintf(getSolutionInstallUrl(), solutionInstallObjectQuery), headers)
 	}()
 	go func() {
-		solutionObject, err := getExtensibilitySolutionObject(getSolutionObjectUrl(solutionId), headers)
-
-		if err != nil {
-			log.Fatalf("Error getting solution object: %v", err)
+		defer wg.Done()
+		solutionObject, solutionErr = getExtensibilitySolutionObject(getSolutionObjectUrl(solutionId), headers)
+		if solutionErr != nil {
+			solutionErr = fmt.Errorf("error getting solution object: %w", solutionErr)
 		}
-
-		solutionObjectChan <- solutionObject
 	}()
+	wg.Wait()
+	if err := errors.Join(installErr, solutionErr); err != nil {
+		return err
+	}
 
-	solutionInstallObject := <-solutionInstallObjectChan
-	solutionObject := <-solutionObjectChan
 	solutionInstallObjectData := solutionInstallObject.StatusData
 	solutionType := solutionObject.SolutionType
 	if solutionInstallObjectData.IsEmpty() {
@@ -128,13 +137,13 @@ func zapSolution(cmd *cobra.Command, args []string) {
 
 	tempDirRoot, err := os.MkdirTemp("", "")
 	if err != nil {
-		log.Fatal(err.Error())
+		return err
 	}
 	solutionRootDirectory := filepath.Join(tempDirRoot, solutionName)
 
 	// Create the directory inside the temporary directory
 	if err := os.Mkdir(solutionRootDirectory, 0755); err != nil {
-		log.Fatal(err.Error())
+		return err
 	}
 
 	// remove the temporary blank solution created so that the zap command can be run again in the same directory without
@@ -147,20 +156,28 @@ func zapSolution(cmd *cobra.Command, args []string) {
 		WithSolutionType(solutionType),
 		WithSolutionVersion(lastSolutionInstallVersion))
 	if err := bumpManifestPatchVersion(manifest); err != nil {
-		log.Fatalf(err.Error())
+		return err
+	}
+	if err := saveSolutionManifest(solutionRootDirectory, manifest); err != nil {
+		return err
 	}
-	createSolutionManifestFile(solutionRootDirectory, manifest)
 	updatedManifestVersion := manifest.SolutionVersion
 
-	solutionArchive := generateZip(cmd, solutionRootDirectory, "")
+	solutionArchive, err := createSolutionZip(cmd, solutionRootDirectory, "")
+	if err != nil {
+		return err
+	}
 	solutionZipPath = solutionArchive.Name()
 	defer os.RemoveAll(solutionZipPath)
 
 	// upload the hollowed-out solution
 	// Note that since the tag flag is REQUIRED in this command, the FSOC_SOLUTION_TAG env var and any locally present .tag file will be ignored
-	uploadSolution(cmd, true, WithSolutionName(solutionName), WithSolutionZipPath(solutionZipPath), WithSolutionInstallVersion(updatedManifestVersion))
+	if err := uploadSolution(cmd, true, WithSolutionName(solutionName), WithSolutionZipPath(solutionZipPath), WithSolutionInstallVersion(updatedManifestVersion)); err != nil {
+		return err
+	}
 
 	output.PrintCmdStatus(cmd, fmt.Sprintf("Solution with name: %s and tag: %s zapped\n", solutionName, solutionTag))
+	return nil
 }
 
 func (s StatusData) IsEmpty() bool {
@@ -15,8 +15,9 @@
 package uql
 
 import (
+	"encoding/json"
 	"fmt"
-	"os"
+	"reflect"
 	"strings"
 
 	"github.com/apex/log"
@@ -25,6 +26,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
 	fsoc "github.com/cisco-open/fsoc/output"
 )
 
@@ -40,7 +42,7 @@ type Config struct {
 var GlobalConfig Config
 
 const (
-	availableFormats string = "auto, table, json, yaml"
+	availableFormats string = "auto, table, json, yaml, jsonl, csv, tsv, template=TEMPLATE, template-file=FILE"
 )
 
 // uqlCmd represents the uql command
@@ -70,6 +72,7 @@ const (
 	rawFormat
 	jsonFormat
 	yamlFormat
+	genericFormat // formats supported by the output package (csv, tsv, jsonl, template)
 )
 
 func init() {
@@ -102,10 +105,9 @@ func uqlQuery(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		if problem, ok := err.(uqlProblem); ok {
 			printProblemDescription(cmd, problem, queryStr)
-			os.Exit(1)
-		} else {
-			log.Fatal(err.Error())
+			return clierror.Wrap(clierror.Validation, problem)
 		}
+		return err
 	}
 	if response.HasErrors() {
 		log.Error("Execution of query encountered errors. Returned data are not complete!")
@@ -124,7 +126,8 @@ func outputFormat(output string, useRaw bool) (format, error) {
 	if useRaw {
 		return rawFormat, nil
 	}
-	switch strings.ToLower(output) {
+	name, _, _ := strings.Cut(output, "=") // some formats take an argument, e.g., template=TEMPLATE
+	switch strings.ToLower(name) {
 	case "auto":
 		return autoFormat, nil
 	case "table":
@@ -133,6 +136,8 @@ func outputFormat(output string, useRaw bool) (format, error) {
 		return jsonFormat, nil
 	case "yaml":
 		return yamlFormat, nil
+	case "jsonl", "csv", "tsv", "template", "template-file":
+		return genericFormat, nil
 
 	default:
 		return -1, fmt.Errorf(
@@ -172,11 +177,67 @@ func printResponse(cmd *cobra.Command, response *Response, output format) error
 		}
 		return fsoc.PrintYaml(cmd, json)
 	case rawFormat:
-		fsoc.PrintCmdOutput(cmd, string(*response.raw))
+		if err := fsoc.PrintCmdOutput(cmd, string(*response.raw)); err != nil {
+			return err
+		}
+	case genericFormat:
+		result, err := transformForJsonOutput(response)
+		if err != nil {
+			return err
+		}
+		table, err := makeRowTable(response.Model(), result.Data)
+		if err != nil {
+			return err
+		}
+		var items any = result.Data
+		if fields, _ := cmd.Flags().GetString("fields"); fields != "" {
+			// the fields transform requires generic data (maps rather than structs)
+			data, err := json.Marshal(result.Data)
+			if err != nil {
+				return err
+			}
+			if err := json.Unmarshal(data, &items); err != nil {
+				return err
+			}
+		}
+		if err := fsoc.PrintCmdOutputCustom(cmd, map[string]any{"items": items, "total": len(result.Data)}, table); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// makeRowTable creates a table with one line per row of the response data, as transformed
+// for JSON output, keeping the column order of the query. Complex values (e.g., nested data sets)
+// are displayed as JSON.
+func makeRowTable(model *Model, data []any) (*fsoc.Table, error) {
+	table := &fsoc.Table{Headers: []string{}, Lines: [][]string{}}
+	for _, field := range model.Fields {
+		table.Headers = append(table.Headers, field.Alias)
+	}
+	for _, row := range data {
+		rowValue := reflect.ValueOf(row)
+		if rowValue.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("(bug) unexpected type of row data: %T", row)
+		}
+		line := make([]string, rowValue.NumField())
+		for i := range line {
+			value, err := json.Marshal(rowValue.Field(i).Interface())
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert value of column %q: %w", table.Headers[i], err)
+			}
+			var s string
+			if json.Unmarshal(value, &s) == nil {
+				line[i] = s // display strings without quotes
+			} else {
+				line[i] = string(value)
+			}
+		}
+		table.Lines = append(table.Lines, line)
+	}
+	return table, nil
+}
+
 func changeFlagUsage(cmd *cobra.Command) {
 	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
 		if flag.Name == "output" {
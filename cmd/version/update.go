@@ -15,9 +15,9 @@
 package version
 
 import (
+	"fmt"
 	//"errors"
 
-	"github.com/apex/log"
 	"github.com/spf13/cobra"
 
 	"github.com/cisco-open/fsoc/config"
@@ -27,7 +27,7 @@ var updateCmd = &cobra.Command{
 	Use:         "update",
 	Short:       "Update fsoc",
 	Long:        `Update fsoc if a new version is available.`,
-	Run:         update,
+	RunE:        update,
 	Annotations: map[string]string{config.AnnotationForConfigBypass: ""},
 }
 
@@ -35,8 +35,8 @@ func init() {
 	versionCmd.AddCommand(updateCmd)
 }
 
-func update(cmd *cobra.Command, args []string) {
-	log.Fatalf("Update command is not implemented yet. Please check version manually and download an update if available.")
+func update(cmd *cobra.Command, args []string) error {
+	return fmt.Errorf("update command is not implemented yet. Please check version manually and download an update if available.")
 }
 
 // func update(core *Core, pkg dependency.Installable) {
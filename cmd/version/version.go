@@ -27,8 +27,8 @@ var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print fsoc version",
 	Long:  `Print fsoc version`,
-	Run: func(cmd *cobra.Command, args []string) {
-		displayVersion(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return displayVersion(cmd)
 	},
 	Annotations: map[string]string{config.AnnotationForConfigBypass: ""},
 }
@@ -42,13 +42,13 @@ func NewSubCmd() *cobra.Command {
 	return versionCmd
 }
 
-func displayVersion(cmd *cobra.Command) {
+func displayVersion(cmd *cobra.Command) error {
 	// determine whether we need short output
 	outfmt, _ := cmd.Flags().GetString("output")
 	detail, _ := cmd.Flags().GetBool("detail")
 	if !detail && (outfmt == "" || outfmt == "human") {
 		output.PrintCmdStatus(cmd, fmt.Sprintf("fsoc version %v\n", GetVersionShort()))
-		return
+		return nil
 	}
 
 	// prepare human output (in case needed)
@@ -58,7 +58,7 @@ func displayVersion(cmd *cobra.Command) {
 		titles = append(titles, fieldTuple[0])
 		values = append(values, fieldTuple[1])
 	}
-	output.PrintCmdOutputCustom(cmd, version, &output.Table{
+	return output.PrintCmdOutputCustom(cmd, version, &output.Table{
 		Headers: titles,
 		Lines:   [][]string{values},
 		Detail:  true,
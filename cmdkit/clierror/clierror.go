@@ -0,0 +1,226 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package clierror classifies the errors that fail fsoc commands into kinds, each with a
+// stable process exit code, and reports them in human or machine-readable (JSON) form.
+// Commands return errors from their RunE handlers; errors returned by the platform API
+// (see api.HttpStatusError) and network errors are classified automatically, while commands
+// can set the kind explicitly for other errors using New or Wrap.
+package clierror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/cisco-open/fsoc/cmdkit/interrupt"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+// Kind is the category of an error
+type Kind int
+
+const (
+	General     Kind = iota // any other failure
+	Usage                   // invalid command line (flags, arguments)
+	Auth                    // authentication or authorization failure
+	NotFound                // the requested object does not exist
+	Validation              // the request or input data is not valid
+	Conflict                // the request conflicts with the current state (e.g., object already exists)
+	Throttled               // too many requests, rate-limited
+	Network                 // failed to reach the platform
+	Timeout                 // the command did not complete in the time allowed (see --timeout)
+	Interrupted             // the user interrupted the command (Ctrl-C)
+)
+
+// kindInfo defines the name and exit code for each kind. The exit codes are stable
+// and documented in the root command's help; new kinds may be added but existing
+// codes must not change.
+var kindInfo = map[Kind]struct {
+	name     string
+	exitCode int
+}{
+	General:     {"general", 1},
+	Usage:       {"usage", 2},
+	Auth:        {"auth", 3},
+	NotFound:    {"not-found", 4},
+	Validation:  {"validation", 5},
+	Conflict:    {"conflict", 6},
+	Throttled:   {"throttled", 7},
+	Network:     {"network", 8},
+	Timeout:     {"timeout", 9},
+	Interrupted: {"interrupted", 130}, // same as shells for SIGINT
+}
+
+// String returns the name of the error kind, as used in JSON error reports
+func (k Kind) String() string {
+	if info, found := kindInfo[k]; found {
+		return info.name
+	}
+	return kindInfo[General].name
+}
+
+// ExitCode returns the process exit code for the error kind
+func (k Kind) ExitCode() int {
+	if info, found := kindInfo[k]; found {
+		return info.exitCode
+	}
+	return kindInfo[General].exitCode
+}
+
+// Error is an error with an explicitly assigned kind
+type Error struct {
+	Kind Kind
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New creates an error of the specified kind, formatting the message like fmt.Errorf
+func New(kind Kind, format string, a ...any) error {
+	return &Error{Kind: kind, Err: fmt.Errorf(format, a...)}
+}
+
+// Wrap assigns a kind to an error; it returns nil if err is nil
+func Wrap(kind Kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: kind, Err: err}
+}
+
+// KindOf determines the kind of an error. An explicitly assigned kind (see New and Wrap) takes
+// precedence; otherwise, the kind is derived from the platform API response status or the
+// type of failure.
+func KindOf(err error) Kind {
+	if err == nil {
+		return General
+	}
+
+	var kindErr *Error
+	if errors.As(err, &kindErr) {
+		return kindErr.Kind
+	}
+	if errors.Is(err, interrupt.ErrInterrupted) {
+		return Interrupted
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return Timeout
+	}
+
+	var statusErr *api.HttpStatusError
+	if errors.As(err, &statusErr) {
+		return kindOfStatus(statusErr.StatusCode)
+	}
+
+	// nb: don't match the net.Error interface, since system call errors (e.g., a missing file) implement it
+	var urlErr *url.Error
+	var opErr *net.OpError
+	if errors.As(err, &urlErr) || errors.As(err, &opErr) {
+		return Network
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return NotFound
+	}
+
+	return General
+}
+
+// kindOfStatus determines the error kind for an HTTP response status code
+func kindOfStatus(status int) Kind {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return Auth
+	case http.StatusNotFound, http.StatusGone:
+		return NotFound
+	case http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusRequestEntityTooLarge:
+		return Validation
+	case http.StatusConflict, http.StatusPreconditionFailed:
+		return Conflict
+	case http.StatusTooManyRequests:
+		return Throttled
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return Network
+	}
+	return General
+}
+
+// ExitCode returns the process exit code for an error (0 if err is nil)
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	return KindOf(err).ExitCode()
+}
+
+// Report is the machine-readable form of an error
+type Report struct {
+	Kind          string `json:"kind"`
+	ExitCode      int    `json:"exitCode"`
+	Message       string `json:"message"`
+	Status        int    `json:"status,omitempty"`        // HTTP status of the failed platform API call
+	Type          string `json:"type,omitempty"`          // problem type (RFC 7807)
+	Title         string `json:"title,omitempty"`         // problem title (RFC 7807)
+	Detail        string `json:"detail,omitempty"`        // problem detail (RFC 7807)
+	TraceResponse string `json:"traceResponse,omitempty"` // trace response header, for troubleshooting with the platform team
+}
+
+// NewReport creates the machine-readable report for an error
+func NewReport(err error) *Report {
+	kind := KindOf(err)
+	report := &Report{
+		Kind:     kind.String(),
+		ExitCode: kind.ExitCode(),
+		Message:  err.Error(),
+	}
+
+	var statusErr *api.HttpStatusError
+	if errors.As(err, &statusErr) {
+		report.Status = statusErr.StatusCode
+		report.TraceResponse = statusErr.TraceResponse
+	}
+	var problem *api.Problem
+	if errors.As(err, &problem) {
+		report.Type = problem.Type
+		report.Title = problem.Title
+		report.Detail = problem.Detail
+		if problem.Status != 0 {
+			report.Status = problem.Status
+		}
+	}
+
+	return report
+}
+
+// WriteJSON writes the error as a JSON object of the form {"error": {...}} (see Report)
+func WriteJSON(w io.Writer, err error) error {
+	data, jsonErr := json.MarshalIndent(map[string]any{"error": NewReport(err)}, "", "    ")
+	if jsonErr != nil {
+		return jsonErr
+	}
+	_, jsonErr = w.Write(append(data, '\n'))
+	return jsonErr
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clierror
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/url"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cisco-open/fsoc/cmdkit/interrupt"
+	"github.com/cisco-open/fsoc/platform/api"
+)
+
+func TestKindOf(t *testing.T) {
+	statusError := func(status int) error {
+		return fmt.Errorf("platform API call failed: %w", &api.HttpStatusError{StatusCode: status, Message: "failed"})
+	}
+
+	tests := []struct {
+		err      error
+		kind     Kind
+		exitCode int
+	}{
+		{errors.New("something failed"), General, 1},
+		{New(Usage, "bad flag"), Usage, 2},
+		{statusError(401), Auth, 3},
+		{statusError(403), Auth, 3},
+		{statusError(404), NotFound, 4},
+		{statusError(400), Validation, 5},
+		{statusError(409), Conflict, 6},
+		{statusError(429), Throttled, 7},
+		{statusError(503), Network, 8},
+		{statusError(500), General, 1},
+		{&url.Error{Op: "Get", URL: "http://localhost", Err: errors.New("connection refused")}, Network, 8},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, Network, 8},
+		{fmt.Errorf("can't open file: %w", &fs.PathError{Op: "open", Path: "x", Err: syscall.ENOENT}), NotFound, 4},
+		{fmt.Errorf("can't open file: %w", &fs.PathError{Op: "open", Path: "x", Err: syscall.EACCES}), General, 1},
+		{fmt.Errorf("aborted: %w", New(Timeout, "command timed out")), Timeout, 9},
+		{fmt.Errorf("aborted: %w", context.DeadlineExceeded), Timeout, 9},
+		{fmt.Errorf("aborted: %w", interrupt.ErrInterrupted), Interrupted, 130},
+		{Wrap(Conflict, statusError(404)), Conflict, 6}, // explicit kind takes precedence
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.kind, KindOf(tt.err), "kind of %v", tt.err)
+		assert.Equal(t, tt.exitCode, ExitCode(tt.err), "exit code of %v", tt.err)
+	}
+
+	assert.Equal(t, 0, ExitCode(nil))
+	assert.Nil(t, Wrap(Auth, nil))
+}
+
+func TestWriteJSON(t *testing.T) {
+	problem := &api.Problem{Type: "https://example.com/problems/not-found", Title: "Not Found", Detail: "no such solution", Status: 404}
+	err := fmt.Errorf("platform API call failed: %w", &api.HttpStatusError{StatusCode: 404, TraceResponse: "00-abc-def-01", WrappedErr: problem})
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteJSON(&buf, err))
+
+	var out struct {
+		Error Report `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+	assert.Equal(t, Report{
+		Kind:          "not-found",
+		ExitCode:      4,
+		Message:       err.Error(),
+		Status:        404,
+		Type:          problem.Type,
+		Title:         problem.Title,
+		Detail:        problem.Detail,
+		TraceResponse: "00-abc-def-01",
+	}, out.Error)
+}
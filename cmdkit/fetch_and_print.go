@@ -15,6 +15,7 @@
 package cmdkit
 
 import (
+	"fmt"
 	"reflect"
 	"strings"
 
@@ -41,8 +42,9 @@ type FetchAndPrintOptions struct {
 // If a cmd is not provided or it has no `output` flag, human output is assumed (table)
 // If a human format is requested/assumed but no table is provided, it displays YAML
 // If the object cannot be converted to the desired format, shows the object in Go's %+v format
-// In addition, if the fetch API command fails, this function prints the error and exits with failure.
-func FetchAndPrint(cmd *cobra.Command, path string, options *FetchAndPrintOptions) {
+// If the fetch API command fails, the error is returned, so that the command can return it from its RunE handler.
+// Collections are displayed page by page as they are retrieved if the output format allows it (see output.NewStreamPrinter).
+func FetchAndPrint(cmd *cobra.Command, path string, options *FetchAndPrintOptions) error {
 	// finalize override fields
 	method := "GET"
 	if options != nil && options.Method != nil {
@@ -80,10 +82,27 @@ func FetchAndPrint(cmd *cobra.Command, path string, options *FetchAndPrintOption
 		if method != "GET" {
 			log.Fatalf("bug: cannot request %q for a collection at %q, only GET is supported for collections", method, path)
 		}
+
+		// stream the output page by page, if the output format allows it
+		if printer, ok := output.NewStreamPrinter(cmd); ok {
+			var printErr error
+			err := api.ForEachPage(path, httpOptions, func(page *api.CollectionPage[any]) error {
+				printErr = printer.PrintItems(page.Items)
+				return printErr
+			})
+			if printErr != nil {
+				return printErr
+			}
+			if err != nil {
+				return fmt.Errorf("platform API call failed: %w", err)
+			}
+			return nil
+		}
+
 		var result api.CollectionResult[any]
 		err := api.JSONGetCollection[any](path, &result, httpOptions)
 		if err != nil {
-			log.Fatalf("Platform API call failed: %v", err)
+			return fmt.Errorf("platform API call failed: %w", err)
 		}
 		res = result
 
@@ -91,9 +110,9 @@ func FetchAndPrint(cmd *cobra.Command, path string, options *FetchAndPrintOption
 		err = api.JSONRequest(method, path, body, &res, httpOptions)
 	}
 	if err != nil {
-		log.Fatalf("Platform API call failed: %v", err)
+		return fmt.Errorf("platform API call failed: %w", err)
 	}
 
 	// print command output data
-	output.PrintCmdOutput(cmd, res)
+	return output.PrintCmdOutput(cmd, res)
 }
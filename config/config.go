@@ -20,12 +20,10 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"os"
-	"path/filepath"
-	"strings"
 
 	"github.com/apex/log"
-	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -37,48 +35,61 @@ const (
 
 var activeProfile string
 
-func getContext(name string) *Context {
+// getContext returns the named context with its inherited settings resolved, or nil if there is
+// no such context. It fails if the context's inheritance is invalid (e.g., a cycle).
+func getContext(name string) (*Context, error) {
+	// the environment profile is not in the config file
+	if IsEnvContext(name) {
+		return getEnvContext(), nil
+	}
+
 	// read config file
 	cfg := getConfig()
 	if len(cfg.Contexts) == 0 {
-		return nil
+		return nil, nil
 	}
 
-	// locate & return the named context
-	for _, c := range cfg.Contexts {
-		if c.Name == name {
-			// ensure a subsystem config map exists, even if empty
-			if c.SubsystemConfigs == nil {
-				c.SubsystemConfigs = map[string]map[string]any{}
-			}
-			return &c
-		}
+	// locate the named context & resolve the settings it inherits
+	c, err := resolveContext(cfg.Contexts, name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid profile %q: %w", name, err)
 	}
+	return c, nil
+}
 
-	return nil
+// upgradeContext replaces deprecated settings in the context; returns true if the context was changed
+func upgradeContext(context *Context) bool {
+	if context.Server == "" {
+		return false
+	}
+	context.URL = "https://" + context.Server
+	context.Server = ""
+	return true
 }
 
 func checkUpgradeScheme(c *configFileContents) {
 	needReWrite := false
-	newContexts := make([]Context, len(c.Contexts))
-	for i, context := range c.Contexts {
-		if context.Server != "" {
-			context.URL = "https://" + context.Server
+	for i := range c.Contexts {
+		server := c.Contexts[i].Server
+		if upgradeContext(&c.Contexts[i]) {
 			log.WithFields(log.Fields{
-				"context": context.Name,
-				"server":  context.Server,
-				"url":     context.URL,
+				"context": c.Contexts[i].Name,
+				"server":  server,
+				"url":     c.Contexts[i].URL,
 			}).Warn("The \"server\" config attribute is deprecated; replacing it with \"url\" now.")
-			context.Server = ""
 			needReWrite = true
 		}
-		newContexts[i] = context
 	}
 	if needReWrite {
-		updateConfigFile(map[string]interface{}{
-			"contexts": newContexts,
+		err := updateConfigFile(func(cfg *configFileContents) error {
+			for i := range cfg.Contexts {
+				upgradeContext(&cfg.Contexts[i])
+			}
+			return nil
 		})
-		c.Contexts = newContexts
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
 		log.Warnf("Config file updated to upgrade settings schema.")
 	}
 }
@@ -97,100 +108,66 @@ func getConfig() configFileContents {
 	return c
 }
 
-func updateConfigFile(keyValues map[string]interface{}) {
-	// update values
-	for key, value := range keyValues {
-		viper.Set(key, value)
-	}
-
-	// set up config file in viper
-	viper.SetConfigType("yaml")
-	if viper.ConfigFileUsed() == "" {
-		home, _ := os.UserHomeDir()
-		configFileLocation := strings.Replace(DefaultConfigFile, "~", home, 1)
-		viper.SetConfigFile(configFileLocation)
+func updateContext(ctx *Context) error {
+	if ctx.Name == "" {
+		log.Fatalf("bug: context name cannot be empty when updating context")
 	}
-	viper.SetConfigPermissions(0600) // o=rw
-
-	// ensure file exists (viper fails to create it, likely a bug in viper)
-	ensureConfigFile()
 
-	// update file contents
-	err := viper.WriteConfig()
-	if err != nil {
-		log.Fatalf("failed to write config file %q: %v", viper.ConfigFileUsed(), err)
+	// keep the environment profile's changes (e.g., refreshed tokens) only in memory
+	if IsEnvContext(ctx.Name) {
+		newCtx := *ctx
+		envContext = &newCtx
+		log.WithField("profile", ctx.Name).Info("Updated the environment profile (in memory only)")
+		return nil
 	}
-}
-
-func ensureConfigFile() {
-	appFs := afero.NewOsFs()
 
-	// finalize the path to use
-	var fileLoc = viper.ConfigFileUsed()
-	if strings.Contains(fileLoc[:2], "~/") {
-		homeDir, _ := os.UserHomeDir()
-		fileLoc = strings.Replace(fileLoc, "~", homeDir, 1)
+	// move the secrets to the context's secret store, unless they are kept in the config file
+	newCtx := *copyContext(ctx) // copy, in case ctx is not what GetCurrentContext() had returned
+	if err := saveSecrets(&newCtx); err != nil {
+		return err
 	}
-	configPath, _ := filepath.Abs(fileLoc)
 
-	// try to open the file, create it if it doesn't exist
-	_, err := appFs.Open(configPath)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			_, err = appFs.Create(configPath)
+	contextExists := false
+	err := updateConfigFile(func(cfg *configFileContents) error {
+		// keep only the settings that the context doesn't inherit
+		if newCtx.Extends != "" {
+			base, err := resolveContext(cfg.Contexts, newCtx.Extends)
 			if err != nil {
-				log.Fatalf("failed to create config file %q: %v", configPath, err)
+				return fmt.Errorf("profile %q: %w", newCtx.Name, err)
 			}
-			viper.SetConfigFile(configPath)
-		} else {
-			log.Fatalf("failed to open config file %q: %v", configPath, err)
+			if base == nil {
+				return fmt.Errorf("profile %q extends %q: %w", newCtx.Name, newCtx.Extends, ErrProfileNotFound)
+			}
+			removeInherited(&newCtx, base)
 		}
-	}
-}
 
-func updateContext(ctx *Context) {
-	contextExists := false
-	var ctxPtr *Context
-
-	if ctx.Name == "" {
-		log.Fatalf("bug: context name cannot be empty when updating context")
-	}
-
-	cfg := getConfig()
-	for idx, c := range cfg.Contexts {
-		if c.Name == ctx.Name {
-			ctxPtr = &cfg.Contexts[idx]
-			contextExists = true
-			break
+		// replace only this context, keeping any changes made to other contexts
+		for idx, c := range cfg.Contexts {
+			if c.Name == newCtx.Name {
+				cfg.Contexts[idx] = newCtx
+				contextExists = true
+				return nil
+			}
 		}
-	}
 
-	// If context not found, create a new one
-	if !contextExists {
-		ctx := Context{
-			Name: ctx.Name,
+		// If context not found, create a new one
+		cfg.Contexts = append(cfg.Contexts, newCtx)
+		if len(cfg.Contexts) == 1 { // just created the first context, set it as current
+			cfg.CurrentContext = newCtx.Name
+			log.Infof("Setting context %s as current", newCtx.Name)
 		}
-		cfg.Contexts = append(cfg.Contexts, ctx)
-		ctxPtr = &cfg.Contexts[len(cfg.Contexts)-1]
-	}
-
-	// copy context if needed
-	if ctx != ctxPtr {
-		*ctxPtr = *ctx // copy, in case ctx is not what GetCurrentContext() had returned
-	}
-
-	update := map[string]interface{}{"contexts": cfg.Contexts}
-	if !contextExists && len(cfg.Contexts) == 1 { // just created the first context, set it as current
-		update["current_context"] = ctx.Name
-		log.Infof("Setting context %s as current", ctx.Name)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
-	updateConfigFile(update)
 
 	if contextExists {
 		log.WithField("profile", ctx.Name).Info("Updated context")
 	} else {
 		log.WithField("profile", ctx.Name).Info("Created context")
 	}
+	return nil
 }
 
 // ReplaceCurrentContext updates the all values within the current context.
@@ -209,7 +186,9 @@ func ReplaceCurrentContext(ctx *Context) {
 	}
 
 	// update context
-	updateContext(ctx)
+	if err := updateContext(ctx); err != nil {
+		log.Fatalf("%v", err)
+	}
 }
 
 // SetActiveProfile sets the name of the profile that should be used instead of the
@@ -226,8 +205,10 @@ func SetActiveProfile(cmd *cobra.Command, args []string, emptyOK bool) {
 		return // no change
 	}
 	// Check if profile exists
-	if !emptyOK && getContext(profile) == nil {
-		log.Fatalf("Could not find profile %q", profile)
+	if !emptyOK {
+		if _, err := GetContext(profile); errors.Is(err, ErrProfileNotFound) {
+			log.Fatalf("Could not find profile %q", profile)
+		}
 	}
 	if activeProfile != "" && activeProfile != profile {
 		log.Warnf("The selected profile is being overridden: old=%q, new=%q", activeProfile, profile)
@@ -250,9 +231,11 @@ func GetCurrentProfileName() string {
 	// start with default
 	profile := DefaultContext
 
-	// use the profile from command line or the config file's current
+	// use the profile from command line, the environment profile or the config file's current
 	if activeProfile != "" {
 		profile = activeProfile
+	} else if envContext != nil {
+		profile = envContext.Name
 	} else {
 		// get profile that is current for the config file
 		cfg := getConfig()
@@ -16,6 +16,7 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/spf13/viper"
@@ -23,7 +24,7 @@ import (
 )
 
 func TestUpdateConfigWhenGetConfig(t *testing.T) {
-	viper.SetConfigFile(".tmp-config")
+	viper.SetConfigFile(filepath.Join(t.TempDir(), ".tmp-config")) // also holds the lock file
 	viper.SetConfigType("yaml")
 	fileName := viper.ConfigFileUsed()
 	fo, err := os.Create(fileName)
@@ -0,0 +1,119 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// credentialHelperPrefix is the executable name prefix of credential helpers
+const credentialHelperPrefix = "fsoc-credential-"
+
+// credentialHelperStore keeps secrets using an external credential helper program, similar to
+// git's and docker's credential helpers. The helper is an executable named fsoc-credential-NAME,
+// found in the PATH, that is invoked with one of these actions:
+//
+//	get    reads the profile name from stdin and writes the profile's secrets to stdout as a
+//	       JSON object, e.g., {"token": "...", "refresh_token": "..."} ({} if there are none)
+//	store  reads {"profile": "NAME", "secrets": {...}} from stdin and stores the secrets
+//	erase  reads the profile name from stdin and removes the profile's secrets
+//
+// The helper must exit with a non-zero status on failure, describing the error on stderr.
+type credentialHelperStore struct {
+	name string
+}
+
+// credentialHelperCache keeps the secrets obtained from credential helpers, so that
+// each helper is invoked once per profile per fsoc invocation
+var credentialHelperCache = struct {
+	sync.Mutex
+	secrets map[string]map[string]string // key is helper name + "/" + profile
+}{secrets: map[string]map[string]string{}}
+
+func (h credentialHelperStore) Get(profile string) (map[string]string, error) {
+	credentialHelperCache.Lock()
+	defer credentialHelperCache.Unlock()
+
+	cacheKey := h.name + "/" + profile
+	secrets, found := credentialHelperCache.secrets[cacheKey]
+	if !found {
+		out, err := h.run("get", []byte(profile))
+		if err != nil {
+			return nil, err
+		}
+		secrets = map[string]string{}
+		if len(bytes.TrimSpace(out)) > 0 {
+			if err := json.Unmarshal(out, &secrets); err != nil {
+				return nil, fmt.Errorf("failed to parse the output of credential helper %q: %w", credentialHelperPrefix+h.name, err)
+			}
+		}
+		credentialHelperCache.secrets[cacheKey] = secrets
+	}
+
+	result := map[string]string{}
+	for key, value := range secrets {
+		result[key] = value
+	}
+	return result, nil
+}
+
+func (h credentialHelperStore) Store(profile string, secrets map[string]string) error {
+	input, err := json.Marshal(struct {
+		Profile string            `json:"profile"`
+		Secrets map[string]string `json:"secrets"`
+	}{profile, secrets})
+	if err != nil {
+		return err
+	}
+	return h.update(profile, "store", input, secrets)
+}
+
+func (h credentialHelperStore) Erase(profile string) error {
+	return h.update(profile, "erase", []byte(profile), map[string]string{})
+}
+
+// update runs a helper action that modifies the secrets and updates the cache
+func (h credentialHelperStore) update(profile string, action string, input []byte, secrets map[string]string) error {
+	credentialHelperCache.Lock()
+	defer credentialHelperCache.Unlock()
+
+	if _, err := h.run(action, input); err != nil {
+		return err
+	}
+	credentialHelperCache.secrets[h.name+"/"+profile] = secrets
+	return nil
+}
+
+// run invokes the credential helper with the action and input, returning its output
+func (h credentialHelperStore) run(action string, input []byte) ([]byte, error) {
+	program := credentialHelperPrefix + h.name
+	cmd := exec.Command(program, action)
+	cmd.Stdin = bytes.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("credential helper %q failed to %v: %w: %v", program, action, err, msg)
+		}
+		return nil, fmt.Errorf("credential helper %q failed to %v: %w", program, action, err)
+	}
+	return out, nil
+}
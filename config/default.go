@@ -25,21 +25,14 @@ func GetDefaultContextName() string {
 
 // SetDefaultContextName sets the default context name in the config file and updates the file
 func SetDefaultContextName(name string) error {
-	// look up selected context
-	contextExists := false
-	cfg := getConfig()
-	for _, c := range cfg.Contexts {
-		if c.Name == name {
-			contextExists = true
-			break
+	return updateConfigFile(func(cfg *configFileContents) error {
+		// look up selected context
+		for _, c := range cfg.Contexts {
+			if c.Name == name {
+				cfg.CurrentContext = name
+				return nil
+			}
 		}
-	}
-	if !contextExists {
 		return fmt.Errorf("%q: %w", name, ErrProfileNotFound)
-	}
-
-	// update config file
-	updateConfigFile(map[string]interface{}{"current_context": name})
-
-	return nil
+	})
 }
@@ -0,0 +1,146 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/apex/log"
+)
+
+// Environment variables that define the environment profile
+const (
+	FSOC_URL_ENVVAR           = "FSOC_URL" // defines the environment profile when set
+	FSOC_AUTH_METHOD_ENVVAR   = "FSOC_AUTH_METHOD"
+	FSOC_TENANT_ENVVAR        = "FSOC_TENANT"
+	FSOC_SECRET_FILE_ENVVAR   = "FSOC_SECRET_FILE"
+	FSOC_CLIENT_ID_ENVVAR     = "FSOC_CLIENT_ID"
+	FSOC_CLIENT_SECRET_ENVVAR = "FSOC_CLIENT_SECRET"
+	FSOC_TOKEN_ENVVAR         = "FSOC_TOKEN"
+)
+
+// EnvContextName is the name of the environment profile
+const EnvContextName = "env"
+
+// envContext is the profile defined by environment variables, nil if FSOC_URL is not set.
+// It is never written to the config file: tokens obtained by logging in are kept only in memory.
+var envContext *Context
+
+// envSources maps the environment profile's settings to the environment variables that define them
+var envSources map[string]string
+
+// LoadEnvContext builds the environment profile from the FSOC_* environment variables, for
+// environments like CI where fsoc should be used without a config file. The profile is defined
+// if the FSOC_URL environment variable is set; it becomes the current profile unless a profile
+// is selected explicitly (with --profile or FSOC_PROFILE). If the authentication method is not
+// set in FSOC_AUTH_METHOD, it is inferred from the credentials provided.
+func LoadEnvContext() error {
+	envContext, envSources = nil, nil
+
+	url := os.Getenv(FSOC_URL_ENVVAR)
+	if url == "" {
+		return nil
+	}
+
+	ctx := &Context{
+		Name:             EnvContextName,
+		URL:              url,
+		AuthMethod:       os.Getenv(FSOC_AUTH_METHOD_ENVVAR),
+		Tenant:           os.Getenv(FSOC_TENANT_ENVVAR),
+		SecretFile:       os.Getenv(FSOC_SECRET_FILE_ENVVAR),
+		ClientID:         os.Getenv(FSOC_CLIENT_ID_ENVVAR),
+		ClientSecret:     os.Getenv(FSOC_CLIENT_SECRET_ENVVAR),
+		Token:            os.Getenv(FSOC_TOKEN_ENVVAR),
+		SubsystemConfigs: map[string]map[string]any{},
+	}
+	sources := map[string]string{}
+	for setting, envVar := range map[string]string{
+		"url":           FSOC_URL_ENVVAR,
+		"auth":          FSOC_AUTH_METHOD_ENVVAR,
+		"tenant":        FSOC_TENANT_ENVVAR,
+		"secret-file":   FSOC_SECRET_FILE_ENVVAR,
+		"client-id":     FSOC_CLIENT_ID_ENVVAR,
+		"client-secret": FSOC_CLIENT_SECRET_ENVVAR,
+		"token":         FSOC_TOKEN_ENVVAR,
+	} {
+		if os.Getenv(envVar) != "" {
+			sources[setting] = envVar
+		}
+	}
+
+	if (ctx.ClientID == "") != (ctx.ClientSecret == "") {
+		return fmt.Errorf("the %v and %v environment variables must be set together", FSOC_CLIENT_ID_ENVVAR, FSOC_CLIENT_SECRET_ENVVAR)
+	}
+	if ctx.ClientID != "" && ctx.SecretFile != "" {
+		return fmt.Errorf("the %v and %v environment variables cannot be set together", FSOC_CLIENT_ID_ENVVAR, FSOC_SECRET_FILE_ENVVAR)
+	}
+	if ctx.AuthMethod == "" {
+		switch {
+		case ctx.Token != "":
+			ctx.AuthMethod = AuthMethodJWT
+		case ctx.ClientID != "" || ctx.SecretFile != "":
+			ctx.AuthMethod = AuthMethodServicePrincipal
+		default:
+			return fmt.Errorf("cannot determine the authentication method of the environment profile; please set it in the %v environment variable", FSOC_AUTH_METHOD_ENVVAR)
+		}
+		sources["auth"] = "inferred from the credentials"
+	}
+
+	envContext, envSources = ctx, sources
+	log.WithFields(log.Fields{"url": ctx.URL, "auth_method": ctx.AuthMethod}).Info("Using the profile defined by environment variables")
+	return nil
+}
+
+// HasEnvContext returns true if the environment profile is defined (see LoadEnvContext)
+func HasEnvContext() bool {
+	return envContext != nil
+}
+
+// IsEnvContext returns true if the named profile is the environment profile (see LoadEnvContext)
+func IsEnvContext(name string) bool {
+	return envContext != nil && name == envContext.Name
+}
+
+// getEnvContext returns a copy of the environment profile, so that it can be modified only via updateContext
+func getEnvContext() *Context {
+	ctx := *envContext
+	return &ctx
+}
+
+// SettingSources returns where each of the profile's settings comes from, keyed by the setting
+// name (as in "fsoc config set", or SUBSYSTEM.SETTING for subsystem settings): the environment
+// variable for the environment profile, the secret store for secrets not kept in the config file
+// or the profile from which the setting is inherited. Settings not in the map come from the config file.
+func SettingSources(ctx *Context) map[string]string {
+	sources := map[string]string{}
+	if IsEnvContext(ctx.Name) {
+		for setting, source := range envSources {
+			sources[setting] = source
+		}
+		return sources
+	}
+	if ctx.SecretStore != "" && ctx.SecretStore != SecretStorePlaintext {
+		for _, setting := range []string{"token", "refresh-token", "secret-file"} {
+			sources[setting] = ctx.SecretStore + " secret store"
+		}
+	}
+	if ctx.Extends != "" {
+		for setting, profile := range inheritanceSources(getConfig().Contexts, ctx.Name) {
+			sources[setting] = fmt.Sprintf("inherited from %q", profile)
+		}
+	}
+	return sources
+}
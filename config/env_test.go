@@ -0,0 +1,95 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setEnvProfile(t *testing.T, vars map[string]string) {
+	for _, name := range []string{FSOC_URL_ENVVAR, FSOC_AUTH_METHOD_ENVVAR, FSOC_TENANT_ENVVAR, FSOC_SECRET_FILE_ENVVAR,
+		FSOC_CLIENT_ID_ENVVAR, FSOC_CLIENT_SECRET_ENVVAR, FSOC_TOKEN_ENVVAR} {
+		t.Setenv(name, vars[name])
+	}
+}
+
+func TestLoadEnvContext(t *testing.T) {
+	defer func() { envContext, envSources = nil, nil }()
+
+	tests := []struct {
+		vars       map[string]string
+		authMethod string
+		errText    string
+	}{
+		{map[string]string{}, "", ""}, // not defined
+		{map[string]string{FSOC_URL_ENVVAR: "https://t1.example.com", FSOC_TOKEN_ENVVAR: "token"}, AuthMethodJWT, ""},
+		{map[string]string{FSOC_URL_ENVVAR: "https://t1.example.com", FSOC_SECRET_FILE_ENVVAR: "creds.json"}, AuthMethodServicePrincipal, ""},
+		{map[string]string{FSOC_URL_ENVVAR: "https://t1.example.com", FSOC_CLIENT_ID_ENVVAR: "id", FSOC_CLIENT_SECRET_ENVVAR: "secret"}, AuthMethodServicePrincipal, ""},
+		{map[string]string{FSOC_URL_ENVVAR: "https://t1.example.com", FSOC_AUTH_METHOD_ENVVAR: AuthMethodOAuth}, AuthMethodOAuth, ""},
+		{map[string]string{FSOC_URL_ENVVAR: "https://t1.example.com"}, "", FSOC_AUTH_METHOD_ENVVAR},
+		{map[string]string{FSOC_URL_ENVVAR: "https://t1.example.com", FSOC_CLIENT_ID_ENVVAR: "id"}, "", FSOC_CLIENT_SECRET_ENVVAR},
+	}
+	for _, tt := range tests {
+		setEnvProfile(t, tt.vars)
+		err := LoadEnvContext()
+		if tt.errText != "" {
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errText)
+			assert.False(t, HasEnvContext())
+			continue
+		}
+		require.NoError(t, err)
+		if tt.authMethod == "" {
+			assert.False(t, HasEnvContext())
+			continue
+		}
+		require.True(t, HasEnvContext())
+		assert.Equal(t, tt.authMethod, envContext.AuthMethod)
+		assert.Equal(t, FSOC_URL_ENVVAR, SettingSources(envContext)["url"])
+	}
+}
+
+func TestEnvContextNotPersisted(t *testing.T) {
+	defer func() { envContext, envSources = nil, nil }()
+
+	configPath := filepath.Join(t.TempDir(), "fsoc.yaml")
+	viper.Reset()
+	defer viper.Reset()
+	viper.SetConfigFile(configPath)
+	viper.SetConfigType("yaml")
+
+	setEnvProfile(t, map[string]string{FSOC_URL_ENVVAR: "https://t1.example.com", FSOC_CLIENT_ID_ENVVAR: "id", FSOC_CLIENT_SECRET_ENVVAR: "secret"})
+	require.NoError(t, LoadEnvContext())
+
+	// the environment profile is current and can be updated (e.g., with refreshed tokens) in memory
+	assert.Equal(t, EnvContextName, GetCurrentProfileName())
+	ctx := GetCurrentContext()
+	require.NotNil(t, ctx)
+	assert.Equal(t, "id", ctx.ClientID)
+	ctx.Token = "access-token"
+	ReplaceCurrentContext(ctx)
+	assert.Equal(t, "access-token", GetCurrentContext().Token)
+
+	// ... without creating the config file
+	_, err := os.Stat(configPath)
+	assert.True(t, os.IsNotExist(err))
+	assert.Empty(t, ListAllContexts())
+}
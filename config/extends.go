@@ -0,0 +1,336 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"golang.org/x/exp/maps"
+)
+
+// notInherited lists the Context fields that a profile never inherits from the profile it extends:
+// its identity and its session (each profile logs in on its own)
+var notInherited = map[string]bool{
+	"Name":             true,
+	"Extends":          true,
+	"Cleared":          true,
+	"User":             true,
+	"Token":            true,
+	"RefreshToken":     true,
+	"TokenExpiry":      true,
+	"ClientID":         true,
+	"ClientSecret":     true,
+	"SecretStore":      true, // where the profile's own secrets are kept
+	"SubsystemConfigs": true, // merged by setting, see inheritFrom
+}
+
+// fieldSettings maps Context fields to the setting names used by "fsoc config set", for reporting
+// the sources of inherited settings; fields not in the map are reported by their lowercase field name
+var fieldSettings = map[string]string{
+	"AuthMethod":         "auth",
+	"URL":                "url",
+	"Tenant":             "tenant",
+	"OAuthFlow":          "oauth-flow",
+	"SecretFile":         "secret-file",
+	"CredentialsSource":  "credentials",
+	"EnvType":            "envtype",
+	"LocalAuthOptions":   "local-auth",
+	"CAFile":             "ca-file",
+	"InsecureSkipVerify": "insecure-skip-verify",
+	"ClientCertFile":     "client-cert",
+	"ClientKeyFile":      "client-key",
+	"ProxyURL":           "proxy",
+	"NoProxy":            "no-proxy",
+	"DisableKeepAlive":   "keep-alive",
+}
+
+// settingName returns the "fsoc config set" setting name of a Context field
+func settingName(field string) string {
+	if setting, found := fieldSettings[field]; found {
+		return setting
+	}
+	return strings.ToLower(field)
+}
+
+// isCleared returns true if the context explicitly clears the field, rather than inheriting it
+func isCleared(ctx *Context, field string) bool {
+	return slices.Contains(ctx.Cleared, settingName(field))
+}
+
+// inheritedFields returns the names of the Context fields that are inherited
+func inheritedFields() []string {
+	fields := []string{}
+	for _, field := range reflect.VisibleFields(reflect.TypeOf(Context{})) {
+		if !notInherited[field.Name] {
+			fields = append(fields, field.Name)
+		}
+	}
+	return fields
+}
+
+// findContext returns the named context in the config file's contexts, nil if not found
+func findContext(contexts []Context, name string) *Context {
+	for i := range contexts {
+		if contexts[i].Name == name {
+			return &contexts[i]
+		}
+	}
+	return nil
+}
+
+// extendsChain returns the profiles that the named profile extends, directly or indirectly, starting
+// with the one it extends directly. It fails if a profile in the chain doesn't exist or the chain has a cycle.
+func extendsChain(contexts []Context, name string) ([]*Context, error) {
+	chain := []*Context{}
+	visited := map[string]bool{name: true}
+	ctx := findContext(contexts, name)
+	for ctx != nil && ctx.Extends != "" {
+		if visited[ctx.Extends] {
+			return nil, fmt.Errorf("profile %q has an inheritance cycle: %q extends %q", name, ctx.Name, ctx.Extends)
+		}
+		visited[ctx.Extends] = true
+		base := findContext(contexts, ctx.Extends)
+		if base == nil {
+			return nil, fmt.Errorf("profile %q extends %q: %w", ctx.Name, ctx.Extends, ErrProfileNotFound)
+		}
+		chain = append(chain, base)
+		ctx = base
+	}
+	return chain, nil
+}
+
+// resolveContext returns the effective settings of the named profile, with the settings it inherits
+// from the profiles it extends and the secrets from the secret stores of each profile in the chain
+func resolveContext(contexts []Context, name string) (*Context, error) {
+	raw := findContext(contexts, name)
+	if raw == nil {
+		return nil, nil
+	}
+	chain, err := extendsChain(contexts, name)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := copyContext(raw)
+	loadSecrets(ctx)
+	for _, base := range chain {
+		baseCtx := copyContext(base)
+		loadSecrets(baseCtx)
+		inheritFrom(ctx, baseCtx)
+	}
+	return ctx, nil
+}
+
+// copyContext returns a copy of the context that doesn't share subsystem settings with the original
+func copyContext(ctx *Context) *Context {
+	newCtx := *ctx
+	newCtx.Cleared = slices.Clone(ctx.Cleared)
+	newCtx.SubsystemConfigs = map[string]map[string]any{}
+	for subsystem, settings := range ctx.SubsystemConfigs {
+		newCtx.SubsystemConfigs[subsystem] = maps.Clone(settings)
+	}
+	return &newCtx
+}
+
+// inheritFrom fills in the context's unset fields and subsystem settings from the base context. Fields
+// that the context clears are not inherited; fields that the base clears are cleared in the context
+// too, so that they are not inherited from the profiles further up the chain either.
+func inheritFrom(ctx *Context, base *Context) {
+	ctxValue := reflect.ValueOf(ctx).Elem()
+	baseValue := reflect.ValueOf(base).Elem()
+	for _, field := range inheritedFields() {
+		value := ctxValue.FieldByName(field)
+		if !value.IsZero() || isCleared(ctx, field) {
+			continue
+		}
+		value.Set(baseValue.FieldByName(field))
+		if value.IsZero() && isCleared(base, field) {
+			ctx.Cleared = append(ctx.Cleared, settingName(field))
+		}
+	}
+
+	if ctx.SubsystemConfigs == nil {
+		ctx.SubsystemConfigs = map[string]map[string]any{}
+	}
+	for subsystem, baseSettings := range base.SubsystemConfigs {
+		settings := ctx.SubsystemConfigs[subsystem]
+		if settings == nil {
+			settings = map[string]any{}
+			ctx.SubsystemConfigs[subsystem] = settings
+		}
+		for key, value := range baseSettings {
+			if _, found := settings[key]; !found {
+				settings[key] = value
+			}
+		}
+	}
+}
+
+// removeInherited clears the context's fields and subsystem settings that have the same values as
+// in the base context, leaving only the settings the context defines itself (the reverse of inheritFrom).
+// Cleared settings are kept only if the base context sets them.
+func removeInherited(ctx *Context, base *Context) {
+	ctxValue := reflect.ValueOf(ctx).Elem()
+	baseValue := reflect.ValueOf(base).Elem()
+	cleared := []string{}
+	for _, field := range inheritedFields() {
+		value := ctxValue.FieldByName(field)
+		baseField := baseValue.FieldByName(field)
+		if isCleared(ctx, field) && value.IsZero() && !baseField.IsZero() {
+			cleared = append(cleared, settingName(field))
+		}
+		if reflect.DeepEqual(value.Interface(), baseField.Interface()) {
+			value.Set(reflect.Zero(value.Type()))
+		}
+	}
+	ctx.Cleared = nil
+	if len(cleared) > 0 {
+		ctx.Cleared = cleared
+	}
+
+	for subsystem, settings := range ctx.SubsystemConfigs {
+		baseSettings := base.SubsystemConfigs[subsystem]
+		for key, value := range settings {
+			if baseValue, found := baseSettings[key]; found && reflect.DeepEqual(value, baseValue) {
+				delete(settings, key)
+			}
+		}
+		if len(settings) == 0 {
+			delete(ctx.SubsystemConfigs, subsystem)
+		}
+	}
+}
+
+// SetExtends makes the context extend the base profile, replacing the settings it inherits from the
+// profile it extended before, if any, with those of the new base. If base is empty, the context stops
+// extending a profile and keeps the settings it has inherited as its own. It fails if the base profile
+// doesn't exist or extending it would create an inheritance cycle.
+func SetExtends(ctx *Context, base string) error {
+	if base == "" {
+		ctx.Extends = ""
+		ctx.Cleared = nil
+		return nil
+	}
+	contexts := getConfig().Contexts
+
+	// remove the settings inherited so far
+	if ctx.Extends != "" {
+		if oldBase, err := resolveContext(contexts, ctx.Extends); err == nil && oldBase != nil {
+			removeInherited(ctx, oldBase)
+		}
+	}
+	ctx.Extends = base
+
+	// check the new base and inherit its settings
+	if base == ctx.Name {
+		return fmt.Errorf("profile %q cannot extend itself", ctx.Name)
+	}
+	if findContext(contexts, base) == nil {
+		return fmt.Errorf("cannot extend profile %q: %w", base, ErrProfileNotFound)
+	}
+	chain, err := extendsChain(contexts, base)
+	if err != nil {
+		return fmt.Errorf("cannot extend profile %q: %w", base, err)
+	}
+	for _, c := range chain {
+		if c.Name == ctx.Name {
+			return fmt.Errorf("cannot extend profile %q: it extends %q, which would create an inheritance cycle", base, ctx.Name)
+		}
+	}
+	baseCtx, err := resolveContext(contexts, base)
+	if err != nil {
+		return err
+	}
+	inheritFrom(ctx, baseCtx)
+	return nil
+}
+
+// inheritanceSources returns the profiles from which the named profile inherits its settings, keyed
+// by setting name: see fieldSettings for the core settings and SUBSYSTEM.SETTING for subsystem settings
+func inheritanceSources(contexts []Context, name string) map[string]string {
+	sources := map[string]string{}
+	raw := findContext(contexts, name)
+	chain, err := extendsChain(contexts, name)
+	if raw == nil || err != nil {
+		return sources
+	}
+
+	rawValue := reflect.ValueOf(raw).Elem()
+	for _, field := range inheritedFields() {
+		if !rawValue.FieldByName(field).IsZero() || isCleared(raw, field) {
+			continue
+		}
+		for _, base := range chain {
+			if !reflect.ValueOf(base).Elem().FieldByName(field).IsZero() {
+				sources[settingName(field)] = base.Name
+				break
+			}
+			if isCleared(base, field) {
+				break
+			}
+		}
+	}
+
+	for i := len(chain) - 1; i >= 0; i-- { // nearest base last, to override
+		for subsystem, settings := range chain[i].SubsystemConfigs {
+			for key := range settings {
+				sources[subsystem+"."+key] = chain[i].Name
+			}
+		}
+	}
+	for subsystem, settings := range raw.SubsystemConfigs {
+		for key := range settings {
+			delete(sources, subsystem+"."+key)
+		}
+	}
+	return sources
+}
+
+// UpdateCleared records which of the specified settings, just set on a profile that extends another
+// one, are empty or false, so that they override the inherited values instead of being inherited.
+// Settings that are not core settings (see fieldSettings) are ignored.
+func UpdateCleared(ctx *Context, settings []string) {
+	if ctx.Extends == "" {
+		return
+	}
+	ctxValue := reflect.ValueOf(ctx).Elem()
+	for _, field := range inheritedFields() {
+		setting := settingName(field)
+		if !slices.Contains(settings, setting) {
+			continue
+		}
+		ctx.Cleared = slices.DeleteFunc(ctx.Cleared, func(s string) bool { return s == setting })
+		if ctxValue.FieldByName(field).IsZero() {
+			ctx.Cleared = append(ctx.Cleared, setting)
+		}
+	}
+}
+
+// CloneContext returns a copy of the context's effective settings, without its session (tokens), for
+// creating a new profile with the specified name. The copy doesn't extend any profile.
+func CloneContext(ctx *Context, name string) *Context {
+	clone := copyContext(ctx)
+	clone.Name = name
+	clone.Extends = ""
+	clone.Cleared = nil
+	clone.User = ""
+	clone.Token = ""
+	clone.RefreshToken = ""
+	clone.TokenExpiry = 0
+	return clone
+}
@@ -0,0 +1,185 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setTestConfigFile(t *testing.T, contents string) string {
+	configPath := filepath.Join(t.TempDir(), "fsoc.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(contents), 0600))
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.SetConfigFile(configPath)
+	viper.SetConfigType("yaml")
+	require.NoError(t, viper.ReadInConfig())
+	return configPath
+}
+
+const extendsTestConfig = `
+contexts:
+    - name: base
+      auth_method: oauth
+      url: https://base.example.com
+      token: base-token
+      proxy_url: http://proxy.example.com:8080
+      subsystems:
+        knowledge:
+            apiver: v1
+            layer: tenant
+    - name: eu
+      extends: base
+      url: https://eu.example.com
+      subsystems:
+        knowledge:
+            apiver: v2
+    - name: eu-staging
+      extends: eu
+      tenant: staging
+current_context: base
+`
+
+func TestExtends(t *testing.T) {
+	configPath := setTestConfigFile(t, extendsTestConfig)
+
+	// settings are inherited through the chain, except for the session
+	ctx, err := GetContext("eu-staging")
+	require.NoError(t, err)
+	assert.Equal(t, "eu", ctx.Extends)
+	assert.Equal(t, AuthMethodOAuth, ctx.AuthMethod)
+	assert.Equal(t, "https://eu.example.com", ctx.URL)
+	assert.Equal(t, "staging", ctx.Tenant)
+	assert.Equal(t, "http://proxy.example.com:8080", ctx.ProxyURL)
+	assert.Empty(t, ctx.Token)
+	assert.Equal(t, map[string]any{"apiver": "v2", "layer": "tenant"}, ctx.SubsystemConfigs["knowledge"])
+
+	sources := SettingSources(ctx)
+	assert.Equal(t, `inherited from "base"`, sources["auth"])
+	assert.Equal(t, `inherited from "eu"`, sources["url"])
+	assert.Equal(t, `inherited from "eu"`, sources["knowledge.apiver"])
+	assert.Equal(t, `inherited from "base"`, sources["knowledge.layer"])
+	assert.NotContains(t, sources, "tenant")
+
+	// updating the profile saves only its own settings
+	ctx.Token = "staging-token"
+	ctx.NoProxy = "localhost"
+	require.NoError(t, UpsertContext(ctx))
+	cfg := getConfig()
+	raw := findContext(cfg.Contexts, "eu-staging")
+	require.NotNil(t, raw)
+	assert.Equal(t, Context{Name: "eu-staging", Extends: "eu", Tenant: "staging", Token: "staging-token", NoProxy: "localhost", SubsystemConfigs: map[string]map[string]any{}}, *raw)
+
+	// extending a different profile replaces the inherited settings
+	require.NoError(t, SetExtends(ctx, "base"))
+	assert.Equal(t, "https://base.example.com", ctx.URL)
+	assert.Equal(t, "localhost", ctx.NoProxy)
+	assert.Equal(t, "v1", ctx.SubsystemConfigs["knowledge"]["apiver"])
+
+	// cycles are rejected
+	base, err := GetContext("base")
+	require.NoError(t, err)
+	assert.ErrorContains(t, SetExtends(base, "eu-staging"), "cycle")
+	assert.ErrorContains(t, SetExtends(base, "base"), "itself")
+	assert.ErrorIs(t, SetExtends(base, "missing"), ErrProfileNotFound)
+
+	// extended profiles cannot be deleted
+	assert.ErrorContains(t, DeleteContext("eu"), `profile "eu-staging" extends it`)
+
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "proxy_url:"))
+	assert.Equal(t, 1, strings.Count(string(data), "layer: tenant"))
+}
+
+func TestExtendsCycleInFile(t *testing.T) {
+	setTestConfigFile(t, `
+contexts:
+    - name: a
+      extends: b
+    - name: b
+      extends: a
+`)
+	_, err := resolveContext(getConfig().Contexts, "a")
+	assert.ErrorContains(t, err, "inheritance cycle")
+
+	// the error is returned to the caller rather than terminating fsoc
+	ForceSetActiveProfileName("a")
+	t.Cleanup(func() { ForceSetActiveProfileName("") })
+	_, err = GetContext("a")
+	assert.ErrorContains(t, err, `invalid profile "a"`)
+	assert.NotErrorIs(t, err, ErrProfileNotFound)
+	assert.Nil(t, GetCurrentContext())
+	_, err = GetContext("c")
+	assert.ErrorIs(t, err, ErrProfileNotFound)
+}
+
+func TestExtendsCleared(t *testing.T) {
+	setTestConfigFile(t, `
+contexts:
+    - name: dev
+      auth_method: oauth
+      url: https://dev.example.com
+      insecure_skip_verify: true
+      proxy_url: http://proxy.example.com:8080
+    - name: prod
+      extends: dev
+      url: https://prod.example.com
+    - name: prod-eu
+      extends: prod
+current_context: prod
+`)
+
+	// a profile can set an inherited setting back to false or empty
+	ctx, err := GetContext("prod")
+	require.NoError(t, err)
+	assert.True(t, ctx.InsecureSkipVerify)
+	ctx.InsecureSkipVerify = false
+	ctx.ProxyURL = ""
+	UpdateCleared(ctx, []string{"insecure-skip-verify", "proxy", "url"})
+	require.NoError(t, UpsertContext(ctx))
+
+	raw := findContext(getConfig().Contexts, "prod")
+	require.NotNil(t, raw)
+	assert.ElementsMatch(t, []string{"insecure-skip-verify", "proxy"}, raw.Cleared)
+
+	for _, name := range []string{"prod", "prod-eu"} { // also for the profiles that extend it
+		ctx, err := GetContext(name)
+		require.NoError(t, err)
+		assert.False(t, ctx.InsecureSkipVerify, name)
+		assert.Empty(t, ctx.ProxyURL, name)
+		assert.Equal(t, "https://prod.example.com", ctx.URL, name)
+		sources := SettingSources(ctx)
+		assert.NotContains(t, sources, "insecure-skip-verify", name)
+		assert.NotContains(t, sources, "proxy", name)
+	}
+
+	// setting a value again stops clearing it
+	ctx, err = GetContext("prod")
+	require.NoError(t, err)
+	ctx.InsecureSkipVerify = true
+	UpdateCleared(ctx, []string{"insecure-skip-verify"})
+	require.NoError(t, UpsertContext(ctx))
+	raw = findContext(getConfig().Contexts, "prod")
+	require.NotNil(t, raw)
+	assert.Equal(t, []string{"proxy"}, raw.Cleared)
+}
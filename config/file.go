@@ -0,0 +1,159 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// fileMutex serializes config file updates within the process (the file lock serializes them
+// across processes), also protecting the in-memory configuration, which is not goroutine-safe
+var fileMutex sync.Mutex
+
+// configFilePath returns the absolute path of the config file, resolving "~" and symlinks
+// (so that a symlinked config file is updated in place rather than replaced)
+func configFilePath() string {
+	fileLoc := viper.ConfigFileUsed()
+	if fileLoc == "" {
+		fileLoc = DefaultConfigFile
+	}
+	if strings.HasPrefix(fileLoc, "~/") {
+		homeDir, _ := os.UserHomeDir()
+		fileLoc = strings.Replace(fileLoc, "~", homeDir, 1)
+	}
+	configPath, err := filepath.Abs(fileLoc)
+	if err != nil {
+		configPath = fileLoc
+	}
+	if resolved, err := filepath.EvalSymlinks(configPath); err == nil {
+		configPath = resolved
+	}
+	return configPath
+}
+
+// updateConfigFile modifies the config file safely when multiple fsoc processes use it concurrently
+// (e.g., parallel CI jobs refreshing their access tokens). It locks the config file, re-reads it so that
+// changes made by other processes since this one loaded its configuration are not lost, applies the
+// update function to the re-read contents and writes the result to a temporary file that atomically
+// replaces the config file. The update function should change only what it means to change (e.g.,
+// a single context), so that the changes merge with other processes' changes. The in-memory
+// configuration is updated with the result as well.
+func updateConfigFile(update func(cfg *configFileContents) error) error {
+	fileMutex.Lock()
+	defer fileMutex.Unlock()
+
+	configPath := configFilePath()
+
+	unlock, err := lockForUpdate(configPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// re-read the current file contents; a missing file is the same as an empty one
+	fileViper := viper.New()
+	fileViper.SetConfigType("yaml")
+	fileViper.SetConfigFile(configPath)
+	if err := fileViper.ReadInConfig(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read config file %q: %w", configPath, err)
+	}
+	var cfg configFileContents
+	if err := fileViper.Unmarshal(&cfg); err != nil {
+		return fmt.Errorf("failed to parse config file %q: %w", configPath, err)
+	}
+
+	// apply the change
+	if err := update(&cfg); err != nil {
+		return err
+	}
+	fileViper.Set("contexts", cfg.Contexts)
+	if cfg.CurrentContext != "" {
+		fileViper.Set("current_context", cfg.CurrentContext)
+	}
+
+	// write the file (keeping any other settings it contains)
+	data, err := yaml.Marshal(fileViper.AllSettings())
+	if err != nil {
+		return fmt.Errorf("failed to encode config file %q: %w", configPath, err)
+	}
+	if err := writeFileAtomic(configPath, data, 0600); err != nil { // o=rw
+		return fmt.Errorf("failed to write config file %q: %w", configPath, err)
+	}
+
+	// update the in-memory configuration
+	viper.SetConfigFile(configPath)
+	viper.Set("contexts", cfg.Contexts)
+	if cfg.CurrentContext != "" {
+		viper.Set("current_context", cfg.CurrentContext)
+	}
+
+	return nil
+}
+
+// lockForUpdate acquires an exclusive lock for updating a file (the config file or the secrets vault),
+// waiting for other processes to complete their updates. The lock is held on a separate lock file,
+// since the file itself is replaced on update. It returns the function to release the lock.
+func lockForUpdate(path string) (func(), error) {
+	lockPath := path + ".lock"
+	f, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file %q: %w", lockPath, err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock file %q: %w", path, err)
+	}
+	return func() {
+		_ = unlockFile(f)
+		f.Close()
+	}, nil
+}
+
+// writeFileAtomic writes the data to a temporary file in the same directory and renames it
+// to the target file, so that readers never see a partially written file
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName) // no-op once renamed
+
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpName, path)
+}
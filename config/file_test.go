@@ -0,0 +1,128 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	upsertWorkerEnvVar = "FSOC_TEST_UPSERT_WORKER"
+	upsertProcesses    = 6
+	upsertGoroutines   = 5
+)
+
+// TestConcurrentUpsertContext runs multiple processes, each upserting contexts from multiple goroutines,
+// against the same config file and verifies that no context or token is lost
+func TestConcurrentUpsertContext(t *testing.T) {
+	if os.Getenv(upsertWorkerEnvVar) != "" {
+		t.Skip("running as a worker")
+	}
+
+	configPath := filepath.Join(t.TempDir(), "fsoc.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+contexts:
+    - name: shared
+      auth_method: oauth
+      url: https://mytenant.observe.appdynamics.com
+current_context: shared
+`), 0600))
+
+	var wg sync.WaitGroup
+	errs := make([]error, upsertProcesses)
+	for i := 0; i < upsertProcesses; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cmd := exec.Command(os.Args[0], "-test.run=^TestUpsertContextWorker$")
+			cmd.Env = append(os.Environ(), upsertWorkerEnvVar+"="+strconv.Itoa(i), FSOC_CONFIG_ENVVAR+"="+configPath)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				errs[i] = fmt.Errorf("worker %d failed: %w\n%s", i, err, out)
+			}
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(configPath)
+	v.SetConfigType("yaml")
+	require.NoError(t, v.ReadInConfig())
+	var cfg configFileContents
+	require.NoError(t, v.Unmarshal(&cfg))
+
+	assert.Equal(t, "shared", cfg.CurrentContext)
+	names := map[string]Context{}
+	for _, c := range cfg.Contexts {
+		names[c.Name] = c
+	}
+	assert.Len(t, cfg.Contexts, 1+upsertProcesses*upsertGoroutines)
+	assert.Contains(t, names, "shared")
+	for i := 0; i < upsertProcesses; i++ {
+		for j := 0; j < upsertGoroutines; j++ {
+			name := fmt.Sprintf("p%d-g%d", i, j)
+			if assert.Contains(t, names, name) {
+				assert.Equal(t, "refresh-"+name, names[name].RefreshToken)
+			}
+		}
+	}
+
+	info, err := os.Stat(configPath)
+	require.NoError(t, err)
+	if os.PathSeparator == '/' {
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+}
+
+// TestUpsertContextWorker is the worker process for TestConcurrentUpsertContext
+func TestUpsertContextWorker(t *testing.T) {
+	worker := os.Getenv(upsertWorkerEnvVar)
+	if worker == "" {
+		t.Skip("not running as a worker")
+	}
+
+	viper.SetConfigFile(os.Getenv(FSOC_CONFIG_ENVVAR))
+	viper.SetConfigType("yaml")
+	require.NoError(t, viper.ReadInConfig())
+
+	var wg sync.WaitGroup
+	for j := 0; j < upsertGoroutines; j++ {
+		wg.Add(1)
+		go func(j int) {
+			defer wg.Done()
+			name := fmt.Sprintf("p%s-g%d", worker, j)
+			ctx := &Context{Name: name, AuthMethod: AuthMethodOAuth, URL: "https://mytenant.observe.appdynamics.com"}
+			assert.NoError(t, UpsertContext(ctx))
+
+			// update the new context, as a token refresh would
+			ctx.Token = "token-" + name
+			ctx.RefreshToken = "refresh-" + name
+			assert.NoError(t, UpsertContext(ctx))
+		}(j)
+	}
+	wg.Wait()
+}
@@ -42,18 +42,26 @@ func ListContexts(prefix string) []string {
 
 // GetCurrentContext returns the context (access profile) selected by the user
 // for the particular invocation of the fsoc utility. Returns nil if no current context is defined (and the
-// only commands allowed in this state are `config create|set`, which will create the context).
+// only commands allowed in this state are `config create|set`, which will create the context) or if
+// the context is invalid; use GetContext(GetCurrentProfileName()) to get the reason.
 // Note that GetCurrentContext returns a pointer into the config file's overall configuration; it can be
 // modified and then updated using ReplaceCurrentContext().
 func GetCurrentContext() *Context {
 	profileName := GetCurrentProfileName()
-	c := getContext(profileName)
+	c, err := getContext(profileName)
+	if err != nil {
+		return nil
+	}
 	return c
 }
 
-// GetContext
+// GetContext returns the named context with its inherited settings resolved. It fails with
+// ErrProfileNotFound if there is no such context, or with the reason the context is invalid.
 func GetContext(name string) (*Context, error) {
-	ctx := getContext(name)
+	ctx, err := getContext(name)
+	if err != nil {
+		return nil, err
+	}
 	if ctx == nil {
 		return nil, fmt.Errorf("%q: %w", name, ErrProfileNotFound)
 	}
@@ -64,45 +72,52 @@ func GetContext(name string) (*Context, error) {
 // UpsertContext updates or adds a context and updates the file
 // The context pointer may or may not have been returned by GetContext()/GetCurrentContext()
 func UpsertContext(ctx *Context) error {
-	updateContext(ctx)
-	return nil
+	return updateContext(ctx)
 }
 
 // DeleteContext deletes specified profile and updates the config file
 // If the deleted context is the default one, xxx
 func DeleteContext(name string) error {
-	// find profile
-	cfg := getConfig()
-	profileIdx := -1
-	for idx, c := range cfg.Contexts {
-		if name == c.Name {
-			profileIdx = idx
-			break
+	var secretStore string
+	err := updateConfigFile(func(cfg *configFileContents) error {
+		// find profile
+		profileIdx := -1
+		for idx, c := range cfg.Contexts {
+			if name == c.Name {
+				profileIdx = idx
+				break
+			}
+		}
+		if profileIdx == -1 {
+			return fmt.Errorf("%q: %w", name, ErrProfileNotFound)
+		}
+		for _, c := range cfg.Contexts {
+			if c.Extends == name {
+				return fmt.Errorf("profile %q cannot be deleted because profile %q extends it", name, c.Name)
+			}
 		}
-	}
-	if profileIdx == -1 {
-		return fmt.Errorf("%q: %w", name, ErrProfileNotFound)
-	}
 
-	// Delete context from config
-	newContexts := append(cfg.Contexts[:profileIdx], cfg.Contexts[profileIdx+1:]...)
-	update := map[string]interface{}{"contexts": newContexts}
-	log.Infof("Deleted profile %q", name)
+		// Delete context from config
+		secretStore = cfg.Contexts[profileIdx].SecretStore
+		cfg.Contexts = append(cfg.Contexts[:profileIdx], cfg.Contexts[profileIdx+1:]...)
+		log.Infof("Deleted profile %q", name)
 
-	// Reassign the current profile setting to an existing (or the default) profile
-	if cfg.CurrentContext == name {
-		var newCurrentContext string
-		if len(newContexts) > 0 {
-			newCurrentContext = newContexts[0].Name
-		} else {
-			newCurrentContext = DefaultContext
+		// Reassign the current profile setting to an existing (or the default) profile
+		if cfg.CurrentContext == name {
+			if len(cfg.Contexts) > 0 {
+				cfg.CurrentContext = cfg.Contexts[0].Name
+			} else {
+				cfg.CurrentContext = DefaultContext
+			}
+			log.Infof("Setting current profile to %q", cfg.CurrentContext)
 		}
-		update["current_context"] = newCurrentContext
-		log.Infof("Setting current profile to %q", newCurrentContext)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	// Update config file
-	updateConfigFile(update)
-
+	// Remove the context's secrets from its secret store
+	eraseSecrets(name, secretStore)
 	return nil
 }
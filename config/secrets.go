@@ -0,0 +1,205 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/apex/log"
+)
+
+// Secret store names, as used in the secret_store setting of a context
+const (
+	SecretStorePlaintext = "plaintext" // secrets are kept in the config file (the default)
+	SecretStoreVault     = "vault"     // secrets are kept in an encrypted vault file, see vaultStore
+	SecretStoreHelper    = "helper"    // secrets are kept by an external credential helper, used as "helper:NAME"
+)
+
+// Names of the secret settings of a context, as used by secret stores
+const (
+	SecretKeyToken        = "token"
+	SecretKeyRefreshToken = "refresh_token"
+	SecretKeyFile         = "secret_file"
+)
+
+// SecretStore keeps the secrets of contexts (access and refresh tokens and secret file paths)
+// outside of the config file. The secrets of each context are keyed by the secret setting
+// name (see SecretKeyToken, SecretKeyRefreshToken and SecretKeyFile).
+type SecretStore interface {
+	// Get returns the context's secrets; the map is empty if there are none
+	Get(profile string) (map[string]string, error)
+
+	// Store replaces the context's secrets
+	Store(profile string, secrets map[string]string) error
+
+	// Erase removes the context's secrets, if any
+	Erase(profile string) error
+}
+
+// plaintextStore keeps the secrets in the config file's context, as fsoc has always done.
+// The config file itself takes care of storing them, so the store's operations do nothing.
+type plaintextStore struct{}
+
+func (plaintextStore) Get(profile string) (map[string]string, error) {
+	return map[string]string{}, nil
+}
+
+func (plaintextStore) Store(profile string, secrets map[string]string) error {
+	return nil
+}
+
+func (plaintextStore) Erase(profile string) error {
+	return nil
+}
+
+// GetSecretStore returns the secret store by its name, as used in the secret_store setting:
+// "plaintext" (or empty), "vault" or "helper:NAME"
+func GetSecretStore(name string) (SecretStore, error) {
+	switch {
+	case name == "" || name == SecretStorePlaintext:
+		return plaintextStore{}, nil
+	case name == SecretStoreVault:
+		return vaultStore{}, nil
+	case strings.HasPrefix(name, SecretStoreHelper+":"):
+		helper := strings.TrimPrefix(name, SecretStoreHelper+":")
+		if helper == "" || strings.ContainsAny(helper, `/\ `) {
+			return nil, fmt.Errorf("invalid credential helper name %q", helper)
+		}
+		return credentialHelperStore{name: helper}, nil
+	}
+	return nil, fmt.Errorf("unknown secret store %q; valid stores are %q, %q and \"%v:NAME\"", name, SecretStorePlaintext, SecretStoreVault, SecretStoreHelper)
+}
+
+// isPlaintext returns true if the context's secrets are kept in the config file
+func isPlaintext(store SecretStore) bool {
+	_, ok := store.(plaintextStore)
+	return ok
+}
+
+// secretsOf returns the non-empty secrets of a context
+func secretsOf(ctx *Context) map[string]string {
+	secrets := map[string]string{}
+	for key, value := range map[string]string{
+		SecretKeyToken:        ctx.Token,
+		SecretKeyRefreshToken: ctx.RefreshToken,
+		SecretKeyFile:         ctx.SecretFile,
+	} {
+		if value != "" {
+			secrets[key] = value
+		}
+	}
+	return secrets
+}
+
+// setSecrets sets the secrets of a context, clearing any secrets that are not in the map
+func setSecrets(ctx *Context, secrets map[string]string) {
+	ctx.Token = secrets[SecretKeyToken]
+	ctx.RefreshToken = secrets[SecretKeyRefreshToken]
+	ctx.SecretFile = secrets[SecretKeyFile]
+}
+
+// loadWarnings tracks the profiles whose secrets failed to load, to warn only once per profile
+var loadWarnings sync.Map
+
+// loadSecrets fills in the context's secrets from its secret store. Failure to obtain
+// the secrets is not fatal: the context is used without them (requiring a new login).
+func loadSecrets(ctx *Context) {
+	store, err := GetSecretStore(ctx.SecretStore)
+	if err == nil {
+		if isPlaintext(store) {
+			return
+		}
+		var secrets map[string]string
+		if secrets, err = store.Get(ctx.Name); err == nil {
+			setSecrets(ctx, secrets)
+			return
+		}
+	}
+	if _, warned := loadWarnings.LoadOrStore(ctx.Name, true); !warned {
+		log.Warnf("Cannot obtain the secrets of profile %q from the %q secret store: %v", ctx.Name, ctx.SecretStore, err)
+	}
+}
+
+// saveSecrets stores the context's secrets in its secret store and removes them from
+// the context, so that they are not written to the config file
+func saveSecrets(ctx *Context) error {
+	store, err := GetSecretStore(ctx.SecretStore)
+	if err != nil {
+		return fmt.Errorf("cannot store the secrets of profile %q: %w", ctx.Name, err)
+	}
+	if isPlaintext(store) {
+		return nil
+	}
+	secrets := secretsOf(ctx)
+	if len(secrets) == 0 {
+		err = store.Erase(ctx.Name)
+	} else {
+		err = store.Store(ctx.Name, secrets)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot store the secrets of profile %q in the %v secret store: %w", ctx.Name, ctx.SecretStore, err)
+	}
+	setSecrets(ctx, nil)
+	return nil
+}
+
+// eraseSecrets removes the secrets of a deleted context from its secret store
+func eraseSecrets(profile string, storeName string) {
+	store, err := GetSecretStore(storeName)
+	if err == nil {
+		err = store.Erase(profile)
+	}
+	if err != nil {
+		log.Warnf("Failed to remove the secrets of profile %q from the %v secret store: %v", profile, storeName, err)
+	}
+}
+
+// MigrateSecrets moves the secrets of a context to another secret store and updates
+// the context to use it. Secrets are removed from the old store once the context is updated.
+func MigrateSecrets(profile string, storeName string) error {
+	if storeName == "" {
+		storeName = SecretStorePlaintext
+	}
+	if _, err := GetSecretStore(storeName); err != nil {
+		return err
+	}
+
+	ctx, err := GetContext(profile) // loads the secrets from the current store
+	if err != nil {
+		return err
+	}
+	oldStoreName := ctx.SecretStore
+	if oldStoreName == "" {
+		oldStoreName = SecretStorePlaintext
+	}
+	if oldStoreName == storeName {
+		log.WithField("profile", profile).Infof("Secrets are already in the %v secret store", storeName)
+		return nil
+	}
+
+	ctx.SecretStore = storeName
+	if storeName == SecretStorePlaintext {
+		ctx.SecretStore = "" // default
+	}
+	if err := updateContext(ctx); err != nil {
+		return err
+	}
+	eraseSecrets(profile, oldStoreName)
+
+	log.WithFields(log.Fields{"profile": profile, "from": oldStoreName, "to": storeName}).Info("Migrated secrets")
+	return nil
+}
@@ -0,0 +1,218 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resetVaultCache ensures that the vault is re-read from disk
+func resetVaultCache() {
+	vaultCache.Lock()
+	vaultCache.secrets = nil
+	vaultCache.Unlock()
+}
+
+// resetCredentialHelperCache ensures that credential helpers are invoked again
+func resetCredentialHelperCache() {
+	credentialHelperCache.Lock()
+	credentialHelperCache.secrets = map[string]map[string]string{}
+	credentialHelperCache.Unlock()
+}
+
+func TestVaultStore(t *testing.T) {
+	vaultPath := filepath.Join(t.TempDir(), "vault")
+	t.Setenv(FSOC_VAULT_FILE_ENVVAR, vaultPath)
+	t.Setenv(FSOC_VAULT_KEY_ENVVAR, "")
+	t.Setenv(FSOC_VAULT_PASSPHRASE_ENVVAR, "correct horse battery staple")
+	defer func(iterations int) { vaultIterations = iterations }(vaultIterations)
+	vaultIterations = 1000
+	resetVaultCache()
+	defer resetVaultCache()
+
+	store, err := GetSecretStore(SecretStoreVault)
+	require.NoError(t, err)
+
+	secrets, err := store.Get("dev")
+	require.NoError(t, err)
+	assert.Empty(t, secrets)
+
+	require.NoError(t, store.Store("dev", map[string]string{SecretKeyToken: "t1", SecretKeyRefreshToken: "refresh-token-1"}))
+	require.NoError(t, store.Store("prod", map[string]string{SecretKeyFile: "/secrets/prod.json"}))
+
+	data, err := os.ReadFile(vaultPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "refresh-token-1")
+	assert.NotContains(t, string(data), "prod.json")
+
+	resetVaultCache()
+	secrets, err = store.Get("dev")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{SecretKeyToken: "t1", SecretKeyRefreshToken: "refresh-token-1"}, secrets)
+
+	require.NoError(t, store.Erase("dev"))
+	resetVaultCache()
+	secrets, err = store.Get("dev")
+	require.NoError(t, err)
+	assert.Empty(t, secrets)
+	secrets, err = store.Get("prod")
+	require.NoError(t, err)
+	assert.Equal(t, "/secrets/prod.json", secrets[SecretKeyFile])
+
+	// wrong passphrase
+	t.Setenv(FSOC_VAULT_PASSPHRASE_ENVVAR, "wrong")
+	resetVaultCache()
+	_, err = store.Get("prod")
+	assert.ErrorContains(t, err, "failed to decrypt")
+
+	// raw key: the vault is re-encrypted with the key on update
+	t.Setenv(FSOC_VAULT_PASSPHRASE_ENVVAR, "correct horse battery staple")
+	t.Setenv(FSOC_VAULT_KEY_ENVVAR, "not a key")
+	assert.ErrorContains(t, store.Store("prod", map[string]string{}), "base64-encoded 32-byte key")
+	t.Setenv(FSOC_VAULT_KEY_ENVVAR, "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=")
+	require.NoError(t, store.Store("ci", map[string]string{SecretKeyToken: "t2"}))
+	resetVaultCache()
+	t.Setenv(FSOC_VAULT_PASSPHRASE_ENVVAR, "")
+	secrets, err = store.Get("ci")
+	require.NoError(t, err)
+	assert.Equal(t, "t2", secrets[SecretKeyToken])
+}
+
+func TestCredentialHelperStore(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test credential helper is a shell script")
+	}
+	resetCredentialHelperCache()
+	defer resetCredentialHelperCache()
+
+	// the helper records its input and returns prepared secrets
+	dir := t.TempDir()
+	script := "#!/bin/sh\n" +
+		"case \"$1\" in\n" +
+		"get) cat > " + dir + "/get.in; cat " + dir + "/get.json ;;\n" +
+		"store) cat > " + dir + "/store.in ;;\n" +
+		"erase) cat > " + dir + "/erase.in ;;\n" +
+		"*) echo \"unknown action $1\" >&2; exit 1 ;;\n" +
+		"esac\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, credentialHelperPrefix+"test"), []byte(script), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "get.json"), []byte(`{"token": "t1", "refresh_token": "r1"}`), 0600))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	store, err := GetSecretStore("helper:test")
+	require.NoError(t, err)
+
+	secrets, err := store.Get("dev")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{SecretKeyToken: "t1", SecretKeyRefreshToken: "r1"}, secrets)
+	input, err := os.ReadFile(filepath.Join(dir, "get.in"))
+	require.NoError(t, err)
+	assert.Equal(t, "dev", string(input))
+
+	require.NoError(t, store.Store("dev", map[string]string{SecretKeyToken: "t2"}))
+	input, err = os.ReadFile(filepath.Join(dir, "store.in"))
+	require.NoError(t, err)
+	var stored struct {
+		Profile string            `json:"profile"`
+		Secrets map[string]string `json:"secrets"`
+	}
+	require.NoError(t, json.Unmarshal(input, &stored))
+	assert.Equal(t, "dev", stored.Profile)
+	assert.Equal(t, map[string]string{SecretKeyToken: "t2"}, stored.Secrets)
+
+	// served from the cache, updated by the store
+	secrets, err = store.Get("dev")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{SecretKeyToken: "t2"}, secrets)
+
+	require.NoError(t, store.Erase("dev"))
+	input, err = os.ReadFile(filepath.Join(dir, "erase.in"))
+	require.NoError(t, err)
+	assert.Equal(t, "dev", string(input))
+
+	_, err = GetSecretStore("helper:")
+	assert.Error(t, err)
+	_, err = GetSecretStore("keychain")
+	assert.Error(t, err)
+	_, err = GetSecretStore("helper:missing")
+	require.NoError(t, err)
+	_, err = credentialHelperStore{name: "missing"}.Get("dev")
+	assert.ErrorContains(t, err, "fsoc-credential-missing")
+}
+
+func TestMigrateSecrets(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "fsoc.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+contexts:
+    - name: dev
+      auth_method: oauth
+      url: https://mytenant.observe.appdynamics.com
+      token: access-token
+      refresh_token: refresh-token
+current_context: dev
+`), 0600))
+	t.Setenv(FSOC_VAULT_FILE_ENVVAR, filepath.Join(dir, "vault"))
+	t.Setenv(FSOC_VAULT_KEY_ENVVAR, "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=")
+	resetVaultCache()
+	defer resetVaultCache()
+
+	viper.Reset()
+	defer viper.Reset()
+	viper.SetConfigFile(configPath)
+	viper.SetConfigType("yaml")
+	require.NoError(t, viper.ReadInConfig())
+
+	require.NoError(t, MigrateSecrets("dev", SecretStoreVault))
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "access-token")
+	assert.NotContains(t, string(data), "refresh-token")
+	assert.Contains(t, string(data), "secret_store: vault")
+
+	// secrets are loaded transparently, and stored in the vault on update
+	resetVaultCache()
+	ctx, err := GetContext("dev")
+	require.NoError(t, err)
+	assert.Equal(t, "access-token", ctx.Token)
+	assert.Equal(t, "refresh-token", ctx.RefreshToken)
+
+	ctx.Token = "new-access-token"
+	require.NoError(t, UpsertContext(ctx))
+	data, err = os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "new-access-token")
+	resetVaultCache()
+	ctx, err = GetContext("dev")
+	require.NoError(t, err)
+	assert.Equal(t, "new-access-token", ctx.Token)
+
+	// back to plaintext, removing the secrets from the vault
+	require.NoError(t, MigrateSecrets("dev", SecretStorePlaintext))
+	data, err = os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "new-access-token")
+	assert.NotContains(t, string(data), "secret_store")
+	secrets, err := vaultStore{}.Get("dev")
+	require.NoError(t, err)
+	assert.Empty(t, secrets)
+}
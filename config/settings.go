@@ -0,0 +1,126 @@
+// Copyright 2023 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// SubsystemSetting describes a subsystem-specific setting, as introspected from the subsystem's
+// registered config structure (see RegisterSubsystemConfigStorage)
+type SubsystemSetting struct {
+	Name   string   // setting name, from the field's mapstructure tag
+	Help   string   // description, from the field's fsoc-help tag
+	Values []string // suggested values, e.g., for completion; may be empty
+}
+
+// quotedValueRegExp finds the values quoted in a setting's help, e.g., `The default is "v1".`
+var quotedValueRegExp = regexp.MustCompile(`"([^"\s]+)"`)
+
+// GetSubsystemSettings returns the settings that a subsystem supports, in the order they are defined.
+// Suggested values are "true" and "false" for boolean settings and the values quoted in the setting's help
+// for other settings.
+func GetSubsystemSettings(subsystemName string) ([]SubsystemSetting, error) {
+	template, ok := subsystemConfigs[subsystemName]
+	if !ok {
+		return nil, &ErrSubsystemNotFound{subsystemName}
+	}
+
+	settings := []SubsystemSetting{}
+	typ := reflect.TypeOf(template).Elem()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if name == "" {
+			continue
+		}
+		setting := SubsystemSetting{Name: name, Help: field.Tag.Get("fsoc-help")}
+		if field.Type.Kind() == reflect.Bool {
+			setting.Values = []string{"true", "false"}
+		} else {
+			for _, match := range quotedValueRegExp.FindAllStringSubmatch(setting.Help, -1) {
+				if !slices.Contains(setting.Values, match[1]) {
+					setting.Values = append(setting.Values, match[1])
+				}
+			}
+		}
+		settings = append(settings, setting)
+	}
+	return settings, nil
+}
+
+// validateSubsystemSetting checks that the subsystem supports the setting and that the value
+// can be parsed into the setting's type, including the type's own validation (see Validator)
+func validateSubsystemSetting(subsystemName string, settingName string, value any) error {
+	settings, err := GetSubsystemSettings(subsystemName)
+	if err != nil {
+		return err
+	}
+	names := make([]string, len(settings))
+	for i, setting := range settings {
+		names[i] = setting.Name
+	}
+	if !slices.Contains(names, settingName) {
+		if suggestion := closestName(settingName, names); suggestion != "" {
+			return fmt.Errorf("unknown setting %q for subsystem %q; did you mean %q?", settingName, subsystemName, suggestion)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown setting %q for subsystem %q; valid setting(s): %v", settingName, subsystemName, strings.Join(names, ", "))
+	}
+
+	// parse the value into a scratch copy of the subsystem's config structure
+	scratch := reflect.New(reflect.TypeOf(subsystemConfigs[subsystemName]).Elem()).Interface()
+	if err := newSubsystemDecoder(scratch).Decode(map[string]any{settingName: value}); err != nil {
+		return &ErrSubsystemParsingError{subsystemName, err}
+	}
+	return nil
+}
+
+// closestName returns the name closest to the (likely mistyped) name, or "" if none is close enough
+func closestName(name string, names []string) string {
+	best, bestDistance := "", len(name)/2+1 // more edits than half of the name's length is not a typo
+	for _, candidate := range names {
+		if d := editDistance(name, candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// editDistance computes the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
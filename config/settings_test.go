@@ -0,0 +1,78 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSettingMode string
+
+func (m *testSettingMode) ValidateAndSet(v any) error {
+	s, ok := v.(string)
+	if !ok || (s != "fast" && s != "safe") {
+		return fmt.Errorf(`mode %q is not supported; valid values: "fast", "safe"`, v)
+	}
+	*m = testSettingMode(s)
+	return nil
+}
+
+type testSubsystemConfig struct {
+	Mode    *testSettingMode `mapstructure:"mode,omitempty" fsoc-help:"Processing mode, \"fast\" or \"safe\". The default is \"safe\"."`
+	Verbose bool             `mapstructure:"verbose,omitempty" fsoc-help:"Set to \"true\" for more output."`
+	Label   string           `mapstructure:"label,omitempty"`
+}
+
+func init() {
+	if err := RegisterSubsystemConfigStorage("settingstest", &testSubsystemConfig{}); err != nil {
+		panic(err)
+	}
+}
+
+func TestGetSubsystemSettings(t *testing.T) {
+	settings, err := GetSubsystemSettings("settingstest")
+	require.NoError(t, err)
+	require.Len(t, settings, 3)
+	assert.Equal(t, "mode", settings[0].Name)
+	assert.Equal(t, []string{"fast", "safe"}, settings[0].Values)
+	assert.Equal(t, []string{"true", "false"}, settings[1].Values)
+	assert.Empty(t, settings[2].Values)
+
+	_, err = GetSubsystemSettings("nosuchsubsystem")
+	assert.ErrorAs(t, err, new(*ErrSubsystemNotFound))
+}
+
+func TestSetSubsystemSettingValidation(t *testing.T) {
+	ctx := &Context{}
+	require.NoError(t, SetSubsystemSetting(ctx, "settingstest", "mode", "fast"))
+	require.NoError(t, SetSubsystemSetting(ctx, "settingstest", "verbose", true))
+	assert.Equal(t, map[string]any{"mode": "fast", "verbose": true}, ctx.SubsystemConfigs["settingstest"])
+
+	err := SetSubsystemSetting(ctx, "settingstest", "mdoe", "fast")
+	assert.ErrorContains(t, err, `did you mean "mode"?`)
+	err = SetSubsystemSetting(ctx, "settingstest", "something", "x")
+	assert.ErrorContains(t, err, "valid setting(s): label, mode, verbose")
+	err = SetSubsystemSetting(ctx, "settingstest", "mode", "slow")
+	assert.ErrorContains(t, err, `mode "slow" is not supported`)
+	err = SetSubsystemSetting(ctx, "settingstest", "verbose", []string{"yes"})
+	assert.Error(t, err)
+
+	// failed settings are not recorded
+	assert.Equal(t, map[string]any{"mode": "fast", "verbose": true}, ctx.SubsystemConfigs["settingstest"])
+}
@@ -17,6 +17,7 @@ package config
 import (
 	"fmt"
 	"reflect"
+	"slices"
 
 	"github.com/apex/log"
 	"github.com/mitchellh/mapstructure"
@@ -49,9 +50,11 @@ func RegisterSubsystemConfigStorage(subsystemName string, store any) error {
 	return nil
 }
 
-// GetRegisteredSubsystems returns the names of subsystems that have registered a config template
+// GetRegisteredSubsystems returns the names of subsystems that have registered a config template, sorted
 func GetRegisteredSubsystems() []string {
-	return maps.Keys(subsystemConfigs)
+	names := maps.Keys(subsystemConfigs)
+	slices.Sort(names)
+	return names
 }
 
 // GetSubsytemConfig returns a pointer to config storage for a given subsystem
@@ -74,11 +77,15 @@ func SetSubsystemSetting(ctx *Context, subsystemName string, settingName string,
 		return &ErrSubsystemNotFound{subsystemName}
 	}
 
-	// TODO: add a check for the setting name, so that unknown settings can be caught here rather than
-	// on parse. Doing this will require some additional work to extract the mapstructure names of the
-	// fields to check against
+	// check the setting name and value, so that errors are caught here rather than on parse
+	if err := validateSubsystemSetting(subsystemName, settingName, value); err != nil {
+		return err
+	}
 
 	// add value to the context (without parsing or validation, as the structure may not be final)
+	if ctx.SubsystemConfigs == nil {
+		ctx.SubsystemConfigs = map[string]map[string]any{}
+	}
 	ssmap, ok := ctx.SubsystemConfigs[subsystemName]
 	if !ok {
 		ssmap = map[string]any{settingName: value}
@@ -131,18 +138,8 @@ func UpdateSubsystemConfigs(ctx *Context) error {
 			continue
 		}
 
-		// create a decoder with the desired options & decode
-		decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
-			// DecodeHook: mapstructure.ComposeDecodeHookFunc([]mapstructure.DecodeHookFunc{}...),
-			DecodeHook:  mapstructure.ComposeDecodeHookFunc(decodeHooks...),
-			ErrorUnused: true,         // no extra settings that are not recognized by the subsystem; this is mostly to avoid typos
-			ZeroFields:  true,         // on re-parsing/re-loading, ensure that any maps start from empty (although we currently support only atomic types)
-			Result:      configStruct, // target which will be used for introspection and result storage
-		})
-		if err != nil {
-			log.Fatalf("(bug) failed to create mapstrucure decoder: %v", err) // nb: likely not subsystem-specific, so no need to print name
-		}
-		parseErr := decoder.Decode(config)
+		// decode into the subsystem's storage
+		parseErr := newSubsystemDecoder(configStruct).Decode(config)
 		if parseErr != nil {
 			err := &ErrSubsystemParsingError{name, parseErr}
 			errlist = append(errlist, err)
@@ -165,6 +162,20 @@ func UpdateSubsystemConfigs(ctx *Context) error {
 	return nil
 }
 
+// newSubsystemDecoder creates a decoder for subsystem-specific settings into the result structure
+func newSubsystemDecoder(result any) *mapstructure.Decoder {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:  mapstructure.ComposeDecodeHookFunc(decodeHooks...),
+		ErrorUnused: true,   // no extra settings that are not recognized by the subsystem; this is mostly to avoid typos
+		ZeroFields:  true,   // on re-parsing/re-loading, ensure that any maps start from empty (although we currently support only atomic types)
+		Result:      result, // target which will be used for introspection and result storage
+	})
+	if err != nil {
+		log.Fatalf("(bug) failed to create mapstrucure decoder: %v", err) // nb: likely not subsystem-specific, so no need to print name
+	}
+	return decoder
+}
+
 // RegisterTypeDecodeHook registers a mapstructure type decode hook for subsystem-
 // specific configuration types, primarily to enforce formats and parse directly
 // into types that are convenient for the subsystems to use.
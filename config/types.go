@@ -16,6 +16,7 @@ package config
 
 import (
 	"fmt"
+	"strings"
 )
 
 const (
@@ -44,6 +45,26 @@ const (
 	AuthMethodLocal = "local"
 )
 
+// Supported OAuth login flows (for the oauth authentication method)
+const (
+	// Authorization code flow with PKCE, using a browser on the same machine (default)
+	OAuthFlowBrowser = "browser"
+	// Device authorization grant (RFC 8628), for machines without a browser
+	OAuthFlowDevice = "device"
+)
+
+// Sources of service and agent principal credentials (see Context.CredentialsSource)
+const (
+	// The secret file (default)
+	CredentialsSourceFile = "file"
+	// The FSOC_CLIENT_ID and FSOC_CLIENT_SECRET environment variables
+	CredentialsSourceEnv = "env"
+	// JSON credentials read from the standard input
+	CredentialsSourceStdin = "stdin"
+	// JSON credentials output by a command, used as "exec:COMMAND [ARGS...]"
+	CredentialsSourceExec = "exec"
+)
+
 const (
 	AnnotationForConfigBypass = "config/bypass-check"
 )
@@ -52,19 +73,34 @@ const (
 // field contains the name of the context (which is unique within the config file);
 // the remaining fields define the access profile.
 type Context struct {
-	Name             string                    `json:"name" yaml:"name" mapstructure:"name"`
-	AuthMethod       string                    `json:"auth_method" yaml:"auth_method" mapstructure:"auth_method"`
-	Server           string                    `json:"server,omitempty" yaml:"server,omitempty" mapstructure:"server,omitempty"` // deprecated
-	URL              string                    `json:"url" yaml:"url" mapstructure:"url"`
-	Tenant           string                    `json:"tenant,omitempty" yaml:"tenant,omitempty" mapstructure:"tenant,omitempty"`
-	User             string                    `json:"user,omitempty" yaml:"user,omitempty" mapstructure:"user,omitempty"`
-	Token            string                    `json:"token,omitempty" yaml:"token,omitempty" mapstructure:"token,omitempty"` // access token
-	RefreshToken     string                    `json:"refresh_token,omitempty" yaml:"refresh_token,omitempty" mapstructure:"refresh_token,omitempty"`
-	CsvFile          string                    `json:"csv_file,omitempty" yaml:"csv_file,omitempty" mapstructure:"csv_file,omitempty"`
-	SecretFile       string                    `json:"secret_file,omitempty" yaml:"secret_file,omitempty" mapstructure:"secret_file,omitempty"`
-	EnvType          string                    `json:"env_type,omitempty" yaml:"env_type,omitempty" mapstructure:"env_type,omitempty"`
-	LocalAuthOptions LocalAuthOptions          `json:"auth-options,omitempty" yaml:"auth-options,omitempty" mapstructure:"auth-options,omitempty"`
-	SubsystemConfigs map[string]map[string]any `json:"subsystems,omitempty" yaml:"subsystems,omitempty" mapstructure:"subsystems,omitempty"`
+	Name               string                    `json:"name" yaml:"name" mapstructure:"name"`
+	Extends            string                    `json:"extends,omitempty" yaml:"extends,omitempty" mapstructure:"extends,omitempty"` // profile whose settings are inherited, see SetExtends
+	Cleared            []string                  `json:"cleared,omitempty" yaml:"cleared,omitempty" mapstructure:"cleared,omitempty"` // inherited settings that this profile sets to empty or false, see UpdateCleared
+	AuthMethod         string                    `json:"auth_method" yaml:"auth_method" mapstructure:"auth_method"`
+	Server             string                    `json:"server,omitempty" yaml:"server,omitempty" mapstructure:"server,omitempty"` // deprecated
+	URL                string                    `json:"url" yaml:"url" mapstructure:"url"`
+	Tenant             string                    `json:"tenant,omitempty" yaml:"tenant,omitempty" mapstructure:"tenant,omitempty"`
+	User               string                    `json:"user,omitempty" yaml:"user,omitempty" mapstructure:"user,omitempty"`
+	Token              string                    `json:"token,omitempty" yaml:"token,omitempty" mapstructure:"token,omitempty"` // access token
+	RefreshToken       string                    `json:"refresh_token,omitempty" yaml:"refresh_token,omitempty" mapstructure:"refresh_token,omitempty"`
+	TokenExpiry        int64                     `json:"token_expiry,omitempty" yaml:"token_expiry,omitempty" mapstructure:"token_expiry,omitempty"` // access token's expiration time, Unix seconds
+	OAuthFlow          string                    `json:"oauth_flow,omitempty" yaml:"oauth_flow,omitempty" mapstructure:"oauth_flow,omitempty"`       // browser (default) or device
+	CsvFile            string                    `json:"csv_file,omitempty" yaml:"csv_file,omitempty" mapstructure:"csv_file,omitempty"`
+	SecretFile         string                    `json:"secret_file,omitempty" yaml:"secret_file,omitempty" mapstructure:"secret_file,omitempty"`
+	ClientID           string                    `json:"-" yaml:"-" mapstructure:"-"` // inline service principal credentials, never persisted (see LoadEnvContext)
+	ClientSecret       string                    `json:"-" yaml:"-" mapstructure:"-"`
+	CredentialsSource  string                    `json:"credentials_source,omitempty" yaml:"credentials_source,omitempty" mapstructure:"credentials_source,omitempty"`
+	SecretStore        string                    `json:"secret_store,omitempty" yaml:"secret_store,omitempty" mapstructure:"secret_store,omitempty"` // where token, refresh_token and secret_file are kept, see GetSecretStore
+	EnvType            string                    `json:"env_type,omitempty" yaml:"env_type,omitempty" mapstructure:"env_type,omitempty"`
+	LocalAuthOptions   LocalAuthOptions          `json:"auth-options,omitempty" yaml:"auth-options,omitempty" mapstructure:"auth-options,omitempty"`
+	CAFile             string                    `json:"ca_file,omitempty" yaml:"ca_file,omitempty" mapstructure:"ca_file,omitempty"`                                        // additional CA certificates (PEM)
+	InsecureSkipVerify bool                      `json:"insecure_skip_verify,omitempty" yaml:"insecure_skip_verify,omitempty" mapstructure:"insecure_skip_verify,omitempty"` // dev only
+	ClientCertFile     string                    `json:"client_cert_file,omitempty" yaml:"client_cert_file,omitempty" mapstructure:"client_cert_file,omitempty"`
+	ClientKeyFile      string                    `json:"client_key_file,omitempty" yaml:"client_key_file,omitempty" mapstructure:"client_key_file,omitempty"`
+	ProxyURL           string                    `json:"proxy_url,omitempty" yaml:"proxy_url,omitempty" mapstructure:"proxy_url,omitempty"`
+	NoProxy            string                    `json:"no_proxy,omitempty" yaml:"no_proxy,omitempty" mapstructure:"no_proxy,omitempty"` // comma-separated, like NO_PROXY
+	DisableKeepAlive   bool                      `json:"disable_keep_alive,omitempty" yaml:"disable_keep_alive,omitempty" mapstructure:"disable_keep_alive,omitempty"`
+	SubsystemConfigs   map[string]map[string]any `json:"subsystems,omitempty" yaml:"subsystems,omitempty" mapstructure:"subsystems,omitempty"`
 	// Note: when adding fields, remember to add display for them in get.go
 }
 
@@ -74,6 +110,29 @@ type LocalAuthOptions struct {
 	AppdPid string `json:"appd-pid" yaml:"appd-pid" mapstructure:"appd-pid"`
 }
 
+// ParseCredentialsSource parses the credentials source setting of a profile, returning the source
+// (one of the CredentialsSource* values) and, for CredentialsSourceExec, the command and its arguments.
+// An empty setting selects CredentialsSourceFile.
+func ParseCredentialsSource(setting string) (source string, command []string, err error) {
+	source, arg, _ := strings.Cut(setting, ":")
+	switch source {
+	case "":
+		return CredentialsSourceFile, nil, nil
+	case CredentialsSourceFile, CredentialsSourceEnv, CredentialsSourceStdin:
+		if arg != "" {
+			break
+		}
+		return source, nil, nil
+	case CredentialsSourceExec:
+		command = strings.Fields(arg)
+		if len(command) == 0 {
+			return "", nil, fmt.Errorf(`the %q credentials source requires a command, e.g., "%v:get-fsoc-credentials --json"`, source, source)
+		}
+		return source, command, nil
+	}
+	return "", nil, fmt.Errorf(`invalid credentials source %q; must be %q, %q, %q or "%v:COMMAND"`, setting, CredentialsSourceFile, CredentialsSourceEnv, CredentialsSourceStdin, CredentialsSourceExec)
+}
+
 func (o *LocalAuthOptions) String() string {
 	if o.AppdPid == "" && o.AppdTid == "" && o.AppdPty == "" {
 		return ""
@@ -0,0 +1,50 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCredentialsSource(t *testing.T) {
+	tests := []struct {
+		setting string
+		source  string
+		command []string
+		valid   bool
+	}{
+		{"", CredentialsSourceFile, nil, true},
+		{"file", CredentialsSourceFile, nil, true},
+		{"env", CredentialsSourceEnv, nil, true},
+		{"stdin", CredentialsSourceStdin, nil, true},
+		{"exec:get-creds --json  prod", CredentialsSourceExec, []string{"get-creds", "--json", "prod"}, true},
+		{"exec:", "", nil, false},
+		{"env:x", "", nil, false},
+		{"keychain", "", nil, false},
+	}
+	for _, tt := range tests {
+		source, command, err := ParseCredentialsSource(tt.setting)
+		if !tt.valid {
+			assert.Error(t, err, tt.setting)
+			continue
+		}
+		require.NoError(t, err, tt.setting)
+		assert.Equal(t, tt.source, source)
+		assert.Equal(t, tt.command, command)
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// Environment variables for the vault secret store
+const (
+	FSOC_VAULT_FILE_ENVVAR       = "FSOC_VAULT_FILE"       // vault file location, default is the config file's path + ".vault"
+	FSOC_VAULT_KEY_ENVVAR        = "FSOC_VAULT_KEY"        // base64-encoded 256-bit key
+	FSOC_VAULT_PASSPHRASE_ENVVAR = "FSOC_VAULT_PASSPHRASE" // passphrase, used if no key is provided
+)
+
+const (
+	vaultVersion     = 1
+	vaultKdfNone     = "none"          // the key is used as is
+	vaultKdfPbkdf2   = "pbkdf2-sha256" // the key is derived from a passphrase
+	vaultKeySize     = 32              // AES-256
+	vaultSaltSize    = 16
+	vaultAssociation = "fsoc-vault-v1" // additional authenticated data
+)
+
+// vaultIterations is the PBKDF2 iteration count for new vaults (a variable, so that tests can lower it)
+var vaultIterations = 600000
+
+// vaultFile is the on-disk form of the vault: the secrets are encrypted with AES-256-GCM
+// using a key provided in the environment or derived from a passphrase provided in the environment
+type vaultFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations,omitempty"`
+	Salt       []byte `json:"salt,omitempty"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+// vaultSecrets are the decrypted contents of the vault: profile name -> secret name -> value
+type vaultSecrets map[string]map[string]string
+
+// vaultCache keeps the decrypted vault contents, so that it is decrypted once per invocation
+var vaultCache struct {
+	sync.Mutex
+	path    string
+	secrets vaultSecrets
+}
+
+// vaultStore keeps secrets in a file-based vault, encrypted with a key or passphrase
+// provided in the environment (see FSOC_VAULT_KEY and FSOC_VAULT_PASSPHRASE)
+type vaultStore struct{}
+
+func (vaultStore) Get(profile string) (map[string]string, error) {
+	vaultCache.Lock()
+	defer vaultCache.Unlock()
+
+	path := vaultFilePath()
+	if vaultCache.secrets == nil || vaultCache.path != path {
+		secrets, err := readVault(path)
+		if err != nil {
+			return nil, err
+		}
+		vaultCache.path, vaultCache.secrets = path, secrets
+	}
+
+	secrets := map[string]string{}
+	for key, value := range vaultCache.secrets[profile] {
+		secrets[key] = value
+	}
+	return secrets, nil
+}
+
+func (vaultStore) Store(profile string, secrets map[string]string) error {
+	return updateVault(func(v vaultSecrets) {
+		v[profile] = secrets
+	})
+}
+
+func (vaultStore) Erase(profile string) error {
+	return updateVault(func(v vaultSecrets) {
+		delete(v, profile)
+	})
+}
+
+// vaultFilePath returns the location of the vault file
+func vaultFilePath() string {
+	if path := os.Getenv(FSOC_VAULT_FILE_ENVVAR); path != "" {
+		return path
+	}
+	return configFilePath() + ".vault"
+}
+
+// updateVault modifies the vault under lock, re-reading it first so that concurrent changes are kept
+func updateVault(update func(v vaultSecrets)) error {
+	vaultCache.Lock()
+	defer vaultCache.Unlock()
+
+	path := vaultFilePath()
+	unlock, err := lockForUpdate(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	secrets, err := readVault(path)
+	if err != nil {
+		return err
+	}
+	update(secrets)
+	if err := writeVault(path, secrets); err != nil {
+		return err
+	}
+
+	vaultCache.path, vaultCache.secrets = path, secrets
+	return nil
+}
+
+// readVault reads and decrypts the vault file; a missing file is an empty vault
+func readVault(path string) (vaultSecrets, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return vaultSecrets{}, nil
+		}
+		return nil, fmt.Errorf("failed to read vault file %q: %w", path, err)
+	}
+
+	var vf vaultFile
+	if err := json.Unmarshal(data, &vf); err != nil {
+		return nil, fmt.Errorf("failed to parse vault file %q: %w", path, err)
+	}
+	if vf.Version != vaultVersion {
+		return nil, fmt.Errorf("unsupported vault file version %v in %q", vf.Version, path)
+	}
+	key, err := vaultKey(vf.KDF, vf.Salt, vf.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newVaultCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, vf.Nonce, vf.Data, []byte(vaultAssociation))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt vault file %q (wrong key or passphrase?)", path)
+	}
+
+	secrets := vaultSecrets{}
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, fmt.Errorf("failed to parse the contents of vault file %q: %w", path, err)
+	}
+	return secrets, nil
+}
+
+// writeVault encrypts the secrets and writes the vault file, using a new salt and nonce
+func writeVault(path string, secrets vaultSecrets) error {
+	vf := vaultFile{Version: vaultVersion, KDF: vaultKdfNone}
+	if os.Getenv(FSOC_VAULT_KEY_ENVVAR) == "" {
+		vf.KDF = vaultKdfPbkdf2
+		vf.Iterations = vaultIterations
+		vf.Salt = make([]byte, vaultSaltSize)
+		if _, err := rand.Read(vf.Salt); err != nil {
+			return err
+		}
+	}
+	key, err := vaultKey(vf.KDF, vf.Salt, vf.Iterations)
+	if err != nil {
+		return err
+	}
+	gcm, err := newVaultCipher(key)
+	if err != nil {
+		return err
+	}
+	vf.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(vf.Nonce); err != nil {
+		return err
+	}
+
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+	vf.Data = gcm.Seal(nil, vf.Nonce, plaintext, []byte(vaultAssociation))
+
+	data, err := json.MarshalIndent(vf, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write vault file %q: %w", path, err)
+	}
+	return nil
+}
+
+// vaultKey obtains the vault key from the environment, as required by the vault's key derivation method
+func vaultKey(kdf string, salt []byte, iterations int) ([]byte, error) {
+	switch kdf {
+	case vaultKdfNone:
+		encoded := os.Getenv(FSOC_VAULT_KEY_ENVVAR)
+		if encoded == "" {
+			return nil, fmt.Errorf("the vault requires a key; please, set it in the %v environment variable", FSOC_VAULT_KEY_ENVVAR)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != vaultKeySize {
+			return nil, fmt.Errorf("the %v environment variable must contain a base64-encoded %d-byte key (e.g., from \"openssl rand -base64 %d\")", FSOC_VAULT_KEY_ENVVAR, vaultKeySize, vaultKeySize)
+		}
+		return key, nil
+	case vaultKdfPbkdf2:
+		passphrase := os.Getenv(FSOC_VAULT_PASSPHRASE_ENVVAR)
+		if passphrase == "" {
+			return nil, fmt.Errorf("the vault requires a passphrase; please, set it in the %v environment variable", FSOC_VAULT_PASSPHRASE_ENVVAR)
+		}
+		if iterations <= 0 {
+			return nil, fmt.Errorf("invalid vault key derivation iteration count %v", iterations)
+		}
+		return pbkdf2.Key([]byte(passphrase), salt, iterations, vaultKeySize, sha256.New), nil
+	}
+	return nil, fmt.Errorf("unsupported vault key derivation method %q", kdf)
+}
+
+func newVaultCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/apex/log/handlers/cli"
 
 	"github.com/cisco-open/fsoc/cmd"
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
 )
 
 func main() {
@@ -34,8 +35,8 @@ func realMain() int {
 	log.SetHandler(cli.New(os.Stderr))
 
 	if err := cmd.Execute(ctx); err != nil {
-		log.WithFields(log.Fields{"error": err}).Error("command failed")
-		return 1
+		cmd.PrintError(err)
+		return clierror.ExitCode(err)
 	}
 	return 0
 }
@@ -0,0 +1,123 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+)
+
+// isDelimitedFormat returns true for the formats that display the table as delimited text
+func isDelimitedFormat(format string) bool {
+	return format == "csv" || format == "tsv"
+}
+
+// printDelimited prints a table as CSV (RFC 4180) or TSV. For TSV, tabs and line breaks within
+// values are replaced with spaces, since TSV has no quoting.
+func printDelimited(cmd *cobra.Command, t *Table, format string, withHeaders bool) error {
+	if t == nil {
+		return nil
+	}
+	rows := t.Lines
+	if withHeaders {
+		rows = append([][]string{t.Headers}, rows...)
+	}
+
+	w := GetOutWriter(cmd)
+	if format == "tsv" {
+		escaper := strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+		for _, row := range rows {
+			fields := make([]string, len(row))
+			for i, field := range row {
+				fields[i] = escaper.Replace(field)
+			}
+			if _, err := fmt.Fprintln(w, strings.Join(fields, "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	csvWriter := csv.NewWriter(w)
+	if err := csvWriter.WriteAll(rows); err != nil {
+		return err
+	}
+	return csvWriter.Error()
+}
+
+// printTemplate displays the output using a Go template, provided either inline (template=TEMPLATE)
+// or in a file (template-file=FILE). The template is applied to the JSON form of the output data,
+// so field names are the same as in the JSON output (e.g., {{range .items}}{{.id}}{{"\n"}}{{end}}).
+// Referencing a field that is not present in the data is an error, rather than printing "<no value>".
+func printTemplate(pr printRequest, v any) error {
+	text := pr.formatArg
+	if pr.format == "template-file" {
+		if pr.formatArg == "" {
+			return fmt.Errorf("a template file must be specified, e.g., -o template-file=FILE")
+		}
+		data, err := os.ReadFile(pr.formatArg)
+		if err != nil {
+			return fmt.Errorf("failed to read template file: %w", err)
+		}
+		text = string(data)
+	} else if text == "" {
+		return fmt.Errorf(`a template must be specified, e.g., -o template='{{.name}}'`)
+	}
+
+	tmpl, err := template.New("output").Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	// convert to generic form, as JSON parse would produce it
+	data, err := toGenericData(v)
+	if err != nil {
+		return err
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return err
+	}
+	if s := out.String(); s != "" && !strings.HasSuffix(s, "\n") {
+		out.WriteString("\n")
+	}
+	print(pr.cmd, out.String())
+	return nil
+}
+
+// toGenericData converts the value into generic maps, slices and scalars via JSON
+func toGenericData(v any) (any, error) {
+	tmp, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert output data to JSON: %w", err)
+	}
+	var data any
+	if err := json.Unmarshal(tmp, &data); err != nil {
+		return nil, fmt.Errorf("failed to convert output data from JSON: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
+	"github.com/cisco-open/fsoc/test"
+)
+
+var formatTestData = map[string]any{
+	"items": []any{
+		map[string]any{"id": "1", "name": "first, \"quoted\""},
+		map[string]any{"id": "2", "name": "second\twith tab"},
+	},
+	"total": 2,
+}
+
+func TestPrintCSV(t *testing.T) {
+	outActual := test.CaptureConsoleOutput(func() {
+		assert.NoError(t, printCmdOutputCustom(printRequest{format: "csv", fields: "id:.id, name:.name"}, formatTestData, nil))
+	}, t)
+	assert.Equal(t, "id,name\n1,\"first, \"\"quoted\"\"\"\n2,second\twith tab\n", outActual)
+}
+
+func TestPrintTSV(t *testing.T) {
+	outActual := test.CaptureConsoleOutput(func() {
+		assert.NoError(t, printCmdOutputCustom(printRequest{format: "tsv", fields: "id:.id, name:.name"}, formatTestData, nil))
+	}, t)
+	assert.Equal(t, "id\tname\n1\tfirst, \"quoted\"\n2\tsecond with tab\n", outActual)
+}
+
+func TestPrintCSVWithTable(t *testing.T) {
+	table := &Table{
+		Headers: []string{"A", "B"},
+		Lines:   [][]string{{"x", "y"}},
+	}
+	outActual := test.CaptureConsoleOutput(func() {
+		assert.NoError(t, printCmdOutputCustom(printRequest{format: "csv"}, nil, table))
+	}, t)
+	assert.Equal(t, "A,B\nx,y\n", outActual)
+}
+
+func TestPrintTemplate(t *testing.T) {
+	outActual := test.CaptureConsoleOutput(func() {
+		assert.NoError(t, printCmdOutputCustom(printRequest{format: `template={{range .items}}{{.id}}={{.name | json}};{{end}}`}, formatTestData, nil))
+	}, t)
+	assert.Equal(t, "1=\"first, \\\"quoted\\\"\";2=\"second\\twith tab\";\n", outActual)
+}
+
+func TestPrintTemplateMissingKey(t *testing.T) {
+	outActual := test.CaptureConsoleOutput(func() {
+		err := printCmdOutputCustom(printRequest{format: `template={{range .items}}{{.bogus}}{{end}}`}, formatTestData, nil)
+		assert.ErrorContains(t, err, "bogus")
+		assert.Equal(t, clierror.Usage, clierror.KindOf(err))
+	}, t)
+	assert.NotContains(t, outActual, "<no value>")
+}
+
+func TestPrintTemplateFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "out.tmpl")
+	assert.NoError(t, os.WriteFile(file, []byte("total: {{.total}}\n"), 0o600))
+
+	outActual := test.CaptureConsoleOutput(func() {
+		assert.NoError(t, printCmdOutputCustom(printRequest{format: "template-file=" + file}, formatTestData, nil))
+	}, t)
+	assert.Equal(t, "total: 2\n", outActual)
+}
+
+func TestStreamPrinterCSV(t *testing.T) {
+	sp := &StreamPrinter{pr: printRequest{format: "csv", fields: "id:.id"}}
+
+	outActual := test.CaptureConsoleOutput(func() {
+		assert.NoError(t, sp.PrintItems([]any{map[string]any{"id": "a"}}))
+		assert.NoError(t, sp.PrintItems([]any{map[string]any{"id": "b"}}))
+	}, t)
+	assert.Equal(t, "id\na\nb\n", outActual)
+}
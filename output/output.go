@@ -29,6 +29,8 @@ import (
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
+
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
 )
 
 const (
@@ -65,8 +67,10 @@ type Table struct {
 type printRequest struct {
 	cmd         *cobra.Command
 	format      string
+	formatArg   string // argument of the format, for formats like template=TEMPLATE
 	fields      string
 	annotations map[string]string
+	table       tableOptions
 }
 
 func print(cmd *cobra.Command, a ...any) {
@@ -111,6 +115,17 @@ func WriteJson(obj interface{}, w io.Writer) error {
 	return err
 }
 
+// WriteJsonLine writes the object as compact JSON on a single line
+func WriteJsonLine(obj interface{}, w io.Writer) error {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	_, err = w.Write(data)
+	return err
+}
+
 func WriteYaml(obj interface{}, w io.Writer) error {
 	data, err := yaml.Marshal(obj)
 	if err != nil {
@@ -126,6 +141,25 @@ func PrintJson(cmd *cobra.Command, v any) error {
 	return WriteJson(v, GetOutWriter(cmd))
 }
 
+// PrintJsonLines displays the output as JSON lines, one compact JSON document per line for
+// each item of the collection (a single line if the output is not a collection)
+func PrintJsonLines(cmd *cobra.Command, v any) error {
+	data, ok := canonicalizeData(v).(map[string]any)
+	if !ok {
+		return WriteJsonLine(v, GetOutWriter(cmd))
+	}
+	items, ok := data["items"].([]any)
+	if !ok {
+		return WriteJsonLine(data["items"], GetOutWriter(cmd))
+	}
+	for _, item := range items {
+		if err := WriteJsonLine(item, GetOutWriter(cmd)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // PrintYaml displays the output in YAML
 func PrintYaml(cmd *cobra.Command, v any) error {
 	data, err := yaml.Marshal(v)
@@ -150,8 +184,9 @@ func PrintCmdStatus(cmd *cobra.Command, s string) {
 // If cmd is not provided or it has no `output` flag, human is assumed
 // If human format is requested/assumed but no table is provided, displays YAML
 // If the object cannot be converted to the desired format, shows the object in Go's %+v format
-func PrintCmdOutput(cmd *cobra.Command, v any) {
-	PrintCmdOutputCustom(cmd, v, nil)
+// It fails if the output cannot be displayed in the selected format (e.g., a template error).
+func PrintCmdOutput(cmd *cobra.Command, v any) error {
+	return PrintCmdOutputCustom(cmd, v, nil)
 }
 
 // PrintCmdOutputCustom displays the output of a command in the user-selected output format
@@ -159,7 +194,13 @@ func PrintCmdOutput(cmd *cobra.Command, v any) {
 // If cmd is not provided or it has no `output` flag, human is assumed
 // If human format is requested/assumed but no table is provided, displays YAML
 // If the object cannot be converted to the desired format, shows the object in Go's %+v format
-func PrintCmdOutputCustom(cmd *cobra.Command, v any, table *Table) {
+// It fails if the output cannot be displayed in the selected format (e.g., a template error).
+func PrintCmdOutputCustom(cmd *cobra.Command, v any, table *Table) error {
+	return printCmdOutputCustom(newPrintRequest(cmd), v, table)
+}
+
+// newPrintRequest extracts the output format and fields flags for the command
+func newPrintRequest(cmd *cobra.Command) printRequest {
 	// extract format, assume default if no command or no -o flag
 	format := ""
 	if cmd != nil {
@@ -172,32 +213,12 @@ func PrintCmdOutputCustom(cmd *cobra.Command, v any, table *Table) {
 	//        - for human outputs only, get the fields spec from the command annotations (if set)
 	//        - for machine formats, don't filter by fields
 	fields, _ := cmd.Flags().GetString("fields") // since --fields doesn't have default, non-empty means explicitly set
-	pr := printRequest{cmd: cmd, format: format, fields: fields, annotations: cmd.Annotations}
-	printCmdOutputCustom(pr, v, table)
+	return printRequest{cmd: cmd, format: format, fields: fields, annotations: cmd.Annotations, table: newTableOptions(cmd)}
 }
 
-func printCmdOutputCustom(pr printRequest, v any, table *Table) {
-	// if no field spec is given on the command line and built-in specs are available, use them
-	// as long as there is no custom table
-	if pr.fields == "" && pr.annotations != nil && (table == nil || table.Headers == nil) {
-		// choose which annotations to use and in what priority order
-		annotations := []string{} // names of annotations to use for fields, in priority order
-		switch pr.format {
-		case "", "auto", "table":
-			annotations = []string{TableFieldsAnnotation, DetailFieldsAnnotation}
-		case "detail":
-			annotations = []string{DetailFieldsAnnotation, TableFieldsAnnotation}
-			// all others, keep empty list
-		}
-
-		// get the first available fields specification
-		for _, name := range annotations {
-			if spec := pr.annotations[name]; spec != "" {
-				pr.fields = spec
-				break
-			}
-		}
-	}
+func printCmdOutputCustom(pr printRequest, v any, table *Table) error {
+	pr.splitFormat()
+	resolveFields(&pr, table)
 
 	// adjust format to yaml if not enough info to produce human output (nb: the criteria may change
 	// in the future as the auto format capabilities improve)
@@ -210,27 +231,40 @@ func printCmdOutputCustom(pr printRequest, v any, table *Table) {
 
 	// transform data according to the fields query (if provided and should be used)
 	if pr.fields != "" {
-		v = transformFields(v, pr.fields)
+		var err error
+		if v, err = transformFields(v, pr.fields); err != nil {
+			return err
+		}
 	}
 
 	// print according to format and presence of table
 	switch pr.format {
 	case "json":
 		if err := PrintJson(pr.cmd, v); err != nil {
-			log.Fatalf("Failed to convert output to JSON: %v (%+v)", err, v)
+			return fmt.Errorf("failed to convert output to JSON: %w", err)
 		}
-		return
+		return nil
+	case "jsonl":
+		if err := PrintJsonLines(pr.cmd, v); err != nil {
+			return fmt.Errorf("failed to convert output to JSON lines: %w", err)
+		}
+		return nil
 	case "yaml":
 		if err := PrintYaml(pr.cmd, v); err != nil {
-			log.Fatalf("Failed to convert output to YAML: %v (%+v)", err, v)
+			return fmt.Errorf("failed to convert output to YAML: %w", err)
 		}
-		return
+		return nil
+	case "template", "template-file":
+		if err := printTemplate(pr, v); err != nil {
+			return clierror.Wrap(clierror.Usage, fmt.Errorf("failed to display output with template: %w", err))
+		}
+		return nil
 	}
 
 	// display simple values
 	if strVal, ok := v.(string); ok {
 		printSimple(pr.cmd, strVal)
-		return
+		return nil
 	}
 
 	// prepare lines if builder provided
@@ -241,6 +275,11 @@ func printCmdOutputCustom(pr printRequest, v any, table *Table) {
 		}
 	}
 
+	// delimited formats create the table from all fields if no fields spec or custom table is provided
+	if isDelimitedFormat(pr.format) && pr.fields == "" && (table == nil || len(table.Headers) == 0) {
+		v = canonicalizeData(v)
+	}
+
 	// format table if a transform is provided or there is no custom table
 	if pr.fields != "" || table == nil || len(table.Headers) == 0 {
 		var err error
@@ -248,18 +287,61 @@ func printCmdOutputCustom(pr printRequest, v any, table *Table) {
 		if err != nil {
 			log.Warnf("Failed to convert output data to a table: %v; reverting to YAML output", err)
 			if err := PrintYaml(pr.cmd, v); err != nil {
-				log.Fatalf("Failed to convert output to YAML: %v (%+v)", err, v)
+				return fmt.Errorf("failed to convert output to YAML: %w", err)
 			}
-			return
+			return nil
 		}
 	}
 
+	// apply the user's sort, filter and column selection
+	table, err := pr.table.apply(table)
+	if err != nil {
+		return fmt.Errorf("failed to display output: %w", err)
+	}
+
 	// display table
-	if table.Detail || pr.format == "detail" {
+	switch {
+	case isDelimitedFormat(pr.format):
+		if err := printDelimited(pr.cmd, table, pr.format, !table.OmitHeaders); err != nil {
+			return fmt.Errorf("failed to display output as %v: %w", strings.ToUpper(pr.format), err)
+		}
+	case table.Detail || pr.format == "detail":
 		printDetail(pr.cmd, table)
-	} else {
+	default:
 		printTable(pr.cmd, table)
 	}
+	return nil
+}
+
+// splitFormat separates the argument from formats that take one (e.g., template=TEMPLATE)
+func (pr *printRequest) splitFormat() {
+	if format, arg, found := strings.Cut(pr.format, "="); found {
+		pr.format, pr.formatArg = format, arg
+	}
+}
+
+// resolveFields selects the built-in fields specification from the command annotations
+// if no field spec is given on the command line, as long as there is no custom table
+func resolveFields(pr *printRequest, table *Table) {
+	if pr.fields == "" && pr.annotations != nil && (table == nil || table.Headers == nil) {
+		// choose which annotations to use and in what priority order
+		annotations := []string{} // names of annotations to use for fields, in priority order
+		switch pr.format {
+		case "", "auto", "table", "csv", "tsv":
+			annotations = []string{TableFieldsAnnotation, DetailFieldsAnnotation}
+		case "detail":
+			annotations = []string{DetailFieldsAnnotation, TableFieldsAnnotation}
+			// all others, keep empty list
+		}
+
+		// get the first available fields specification
+		for _, name := range annotations {
+			if spec := pr.annotations[name]; spec != "" {
+				pr.fields = spec
+				break
+			}
+		}
+	}
 }
 
 func buildLines(in any, builderFunc func(any) []string) ([][]string, bool) {
@@ -384,7 +466,7 @@ func createTable(v any, fields string, template *Table) (*Table, error) {
 	jqExpression := "(.items[0]|keys),.items[]|to_entries|map(.value|tostring)"
 	query, err := gojq.Parse(jqExpression)
 	if err != nil {
-		log.Fatalf("Failed to parse jq expression: %q: %v; likely a bug; please use --output yaml or json for now", jqExpression, err)
+		return nil, fmt.Errorf("(bug) failed to parse jq expression %q: %w", jqExpression, err)
 	}
 	iter := query.Run(v)
 	for index := 0; true; index++ {
@@ -464,7 +546,7 @@ func makeFieldOrderIndex(fieldsCommaList string) []int {
 // then we reconstitute the original {items, total} object with items now having their fields filtered
 // We only need to use this iterator once since it's a single object to single object
 // jq expression and we just overwrite the v that came in
-func transformFields(v any, fieldsCommaList string) any {
+func transformFields(v any, fieldsCommaList string) (any, error) {
 	// canonicalize format (we use JQ, so it must be map[string]interface{})
 	v = canonicalizeData(v)
 
@@ -474,13 +556,13 @@ func transformFields(v any, fieldsCommaList string) any {
 		qStr := fmt.Sprintf(". as $root|.items|{items: map({%s}),total:$root.total}", fieldsCommaList)
 		query, err := gojq.Parse(qStr)
 		if err != nil {
-			log.Fatalf("Failed to parse field list %q as a jq expression %q: %v", fieldsCommaList, qStr, err)
+			return nil, clierror.New(clierror.Usage, "failed to parse field list %q as a jq expression %q: %v", fieldsCommaList, qStr, err)
 		}
 		iter := query.Run(v)
 
 		v, _ = iter.Next()
 	}
-	return v
+	return v, nil
 }
 
 // canonicalizeData ensures that the data is in a uniform, expected format, converting any possible input
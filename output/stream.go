@@ -0,0 +1,122 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/spf13/cobra"
+)
+
+// StreamPrinter displays the items of a collection incrementally, as pages of the
+// collection are received, rather than after the whole collection has been retrieved.
+// Only formats that produce independent lines or rows can be streamed (table, detail, CSV, TSV
+// and JSON lines), and only if the rows are not sorted (--sort-by); the table's column widths
+// are computed separately for each page.
+type StreamPrinter struct {
+	pr           printRequest
+	pageCount    int
+	headersShown bool
+}
+
+// NewStreamPrinter returns a printer for streaming the collection output of a command in the
+// user-selected output format. It returns false if the selected format requires the complete
+// collection (e.g., JSON and YAML), the rows must be sorted (--sort-by) or there is not enough
+// information to produce a table (no fields specification); use PrintCmdOutput with the complete
+// collection in this case.
+func NewStreamPrinter(cmd *cobra.Command) (*StreamPrinter, bool) {
+	if cmd == nil {
+		return nil, false
+	}
+	pr := newPrintRequest(cmd)
+	pr.splitFormat()
+	resolveFields(&pr, nil)
+
+	if pr.table.sortBy != "" {
+		return nil, false // sorting requires the complete collection
+	}
+
+	switch pr.format {
+	case "jsonl":
+		// always streamable
+	case "", "auto", "table", "detail", "csv", "tsv":
+		if pr.fields == "" {
+			return nil, false // auto format would fall back to YAML; table/detail need fields to create the table
+		}
+	default:
+		return nil, false
+	}
+
+	return &StreamPrinter{pr: pr}, true
+}
+
+// PrintItems displays a page of collection items
+func (sp *StreamPrinter) PrintItems(items []any) error {
+	sp.pageCount++
+
+	if items == nil {
+		items = []any{}
+	}
+	var v any = map[string]any{"items": items, "total": len(items)}
+	if sp.pr.fields != "" {
+		var err error
+		if v, err = transformFields(v, sp.pr.fields); err != nil {
+			return err
+		}
+	}
+
+	if sp.pr.format == "jsonl" {
+		if err := PrintJsonLines(sp.pr.cmd, v); err != nil {
+			return fmt.Errorf("failed to convert output to JSON lines: %w", err)
+		}
+		return nil
+	}
+
+	// skip empty pages after the first one, so that headers are not repeated
+	if len(items) == 0 && sp.pageCount > 1 {
+		return nil
+	}
+
+	table, err := createTable(v, sp.pr.fields, nil)
+	if err != nil {
+		log.Warnf("Failed to convert output data to a table: %v; reverting to YAML output", err)
+		if err := PrintYaml(sp.pr.cmd, v); err != nil {
+			return fmt.Errorf("failed to convert output to YAML: %w", err)
+		}
+		return nil
+	}
+
+	table, err = sp.pr.table.apply(table)
+	if err != nil {
+		return fmt.Errorf("failed to display output: %w", err)
+	}
+
+	if sp.pr.format == "detail" {
+		printDetail(sp.pr.cmd, table)
+		return nil
+	}
+	table.OmitHeaders = table.OmitHeaders || sp.headersShown
+	sp.headersShown = true
+	if isDelimitedFormat(sp.pr.format) {
+		if err := printDelimited(sp.pr.cmd, table, sp.pr.format, !table.OmitHeaders); err != nil {
+			return fmt.Errorf("failed to display output as %v: %w", strings.ToUpper(sp.pr.format), err)
+		}
+		return nil
+	}
+	printTable(sp.pr.cmd, table)
+	return nil
+}
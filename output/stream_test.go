@@ -0,0 +1,64 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cisco-open/fsoc/test"
+)
+
+func TestStreamPrinterJsonLines(t *testing.T) {
+	sp := &StreamPrinter{pr: printRequest{format: "jsonl"}}
+
+	outActual := test.CaptureConsoleOutput(func() {
+		assert.NoError(t, sp.PrintItems([]any{map[string]any{"id": "a"}, map[string]any{"id": "b"}}))
+		assert.NoError(t, sp.PrintItems([]any{map[string]any{"id": "c"}}))
+	}, t)
+	assert.Equal(t, "{\"id\":\"a\"}\n{\"id\":\"b\"}\n{\"id\":\"c\"}\n", outActual)
+}
+
+func TestStreamPrinterTable(t *testing.T) {
+	sp := &StreamPrinter{pr: printRequest{format: "table", fields: "id:.id, name:.name"}}
+
+	outActual := test.CaptureConsoleOutput(func() {
+		assert.NoError(t, sp.PrintItems([]any{map[string]any{"id": "1", "name": "first"}}))
+		assert.NoError(t, sp.PrintItems([]any{}))
+		assert.NoError(t, sp.PrintItems([]any{map[string]any{"id": "2", "name": "second"}}))
+	}, t)
+	lines := strings.Split(strings.TrimSpace(outActual), "\n")
+	assert.Equal(t, 1, strings.Count(outActual, "ID"), "headers must be shown once:\n%v", outActual)
+	assert.Contains(t, lines[len(lines)-2], "first")
+	assert.Contains(t, lines[len(lines)-1], "second")
+}
+
+func TestNewStreamPrinterSortBy(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("output", "table", "")
+	cmd.Flags().String("fields", "id:.id", "")
+	cmd.Flags().String("sort-by", "", "")
+
+	_, ok := NewStreamPrinter(cmd)
+	assert.True(t, ok)
+
+	// sorting requires the complete collection
+	assert.NoError(t, cmd.Flags().Set("sort-by", "id"))
+	_, ok = NewStreamPrinter(cmd)
+	assert.False(t, ok)
+}
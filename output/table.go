@@ -0,0 +1,210 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
+)
+
+// tableOptions are the user's adjustments of the table output (the global --sort-by,
+// --filter-rows, --columns, --no-headers and --wide flags). They apply to the table, detail,
+// CSV and TSV formats, after the table has been created.
+type tableOptions struct {
+	sortBy     string   // column to sort the rows by
+	descending bool     // sort in descending order
+	filters    []string // column=glob conditions that the rows must match (all of them)
+	columns    []string // columns to display, in order
+	noHeaders  bool     // don't display the headers
+	wide       bool     // don't wrap long values
+}
+
+// newTableOptions extracts the table options from the command's flags. Flags that are not defined
+// for the command (or are redefined by it with a different meaning) are ignored.
+func newTableOptions(cmd *cobra.Command) tableOptions {
+	var opts tableOptions
+	if cmd == nil {
+		return opts
+	}
+
+	if sortBy, err := cmd.Flags().GetString("sort-by"); err == nil && sortBy != "" {
+		column, order, _ := strings.Cut(sortBy, ",")
+		opts.sortBy = strings.TrimSpace(column)
+		opts.descending = strings.EqualFold(strings.TrimSpace(order), "desc")
+	}
+	if filters, err := cmd.Flags().GetStringArray("filter-rows"); err == nil {
+		opts.filters = filters
+	}
+	if columns, err := cmd.Flags().GetString("columns"); err == nil && columns != "" {
+		for _, column := range strings.Split(columns, ",") {
+			if column = strings.TrimSpace(column); column != "" {
+				opts.columns = append(opts.columns, column)
+			}
+		}
+	}
+	opts.noHeaders, _ = cmd.Flags().GetBool("no-headers")
+	opts.wide, _ = cmd.Flags().GetBool("wide")
+
+	return opts
+}
+
+// ValidateTableFlags checks the syntax of the --sort-by, --filter-rows and --columns flags, so that
+// malformed values are reported as usage errors before the command runs. Column names can only be
+// checked when the output is displayed.
+func ValidateTableFlags(cmd *cobra.Command) error {
+	if sortBy, err := cmd.Flags().GetString("sort-by"); err == nil && sortBy != "" {
+		column, order, hasOrder := strings.Cut(sortBy, ",")
+		order = strings.TrimSpace(order)
+		if strings.TrimSpace(column) == "" || (hasOrder && !strings.EqualFold(order, "asc") && !strings.EqualFold(order, "desc")) {
+			return clierror.New(clierror.Usage, "invalid --sort-by %q, expected COLUMN[,asc|desc], e.g., --sort-by name,desc", sortBy)
+		}
+	}
+
+	opts := newTableOptions(cmd)
+	for _, filter := range opts.filters {
+		if _, _, err := parseFilter(filter); err != nil {
+			return clierror.Wrap(clierror.Usage, err)
+		}
+	}
+	if columns, err := cmd.Flags().GetString("columns"); err == nil && columns != "" && len(opts.columns) == 0 {
+		return clierror.New(clierror.Usage, "invalid --columns %q, expected a comma-separated list of column names, e.g., --columns name,url", columns)
+	}
+
+	return nil
+}
+
+// parseFilter splits a --filter-rows condition into the column name and the glob pattern
+func parseFilter(filter string) (column string, pattern string, err error) {
+	column, pattern, found := strings.Cut(filter, "=")
+	column = strings.TrimSpace(column)
+	if !found || column == "" {
+		return "", "", fmt.Errorf("invalid filter %q, expected COLUMN=GLOB, e.g., --filter-rows 'name=*prod*'", filter)
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return "", "", fmt.Errorf("invalid filter pattern %q: %w", pattern, err)
+	}
+	return column, pattern, nil
+}
+
+// apply returns a copy of the table with the options applied: rows are filtered first, then sorted,
+// and finally the columns are selected, so that rows can be filtered and sorted by columns that are
+// not displayed. Column names are case-insensitive. Errors are usage errors, since they are caused by
+// the flag values (e.g., an unknown column name).
+func (opts *tableOptions) apply(in *Table) (*Table, error) {
+	if in == nil {
+		return nil, nil
+	}
+	t := *in
+	t.Lines = append([][]string{}, in.Lines...)
+
+	for _, filter := range opts.filters {
+		column, pattern, err := parseFilter(filter)
+		if err != nil {
+			return nil, clierror.Wrap(clierror.Usage, err)
+		}
+		index, err := columnIndex(&t, column)
+		if err != nil {
+			return nil, err
+		}
+		lines := [][]string{}
+		for _, line := range t.Lines {
+			if matched, _ := path.Match(pattern, cell(line, index)); matched {
+				lines = append(lines, line)
+			}
+		}
+		t.Lines = lines
+	}
+
+	if opts.sortBy != "" {
+		index, err := columnIndex(&t, opts.sortBy)
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(t.Lines, func(i, j int) bool {
+			if opts.descending {
+				return lessValue(cell(t.Lines[j], index), cell(t.Lines[i], index))
+			}
+			return lessValue(cell(t.Lines[i], index), cell(t.Lines[j], index))
+		})
+	}
+
+	if len(opts.columns) > 0 {
+		indices := make([]int, len(opts.columns))
+		for i, column := range opts.columns {
+			index, err := columnIndex(&t, column)
+			if err != nil {
+				return nil, err
+			}
+			indices[i] = index
+		}
+		t.Headers = selectColumns(t.Headers, indices)
+		for i, line := range t.Lines {
+			t.Lines[i] = selectColumns(line, indices)
+		}
+		t.ColumnMinWidths = nil // column positions no longer apply
+	}
+
+	if opts.noHeaders {
+		t.OmitHeaders = true
+	}
+	if opts.wide {
+		t.DisableAutoWrapText = true
+	}
+
+	return &t, nil
+}
+
+// columnIndex returns the index of the named column in the table
+func columnIndex(t *Table, column string) (int, error) {
+	for i, header := range t.Headers {
+		if strings.EqualFold(header, column) {
+			return i, nil
+		}
+	}
+	return -1, clierror.New(clierror.Usage, "unknown column %q; available columns: %v", column, strings.Join(t.Headers, ", "))
+}
+
+// cell returns the value in the specified column of a row, empty if the row is short
+func cell(line []string, index int) string {
+	if index < len(line) {
+		return line[index]
+	}
+	return ""
+}
+
+func selectColumns(line []string, indices []int) []string {
+	selected := make([]string, len(indices))
+	for i, index := range indices {
+		selected[i] = cell(line, index)
+	}
+	return selected
+}
+
+// lessValue compares two table values, numerically if both are numbers
+func lessValue(a, b string) bool {
+	aNum, aErr := strconv.ParseFloat(a, 64)
+	bNum, bErr := strconv.ParseFloat(b, 64)
+	if aErr == nil && bErr == nil {
+		return aNum < bNum
+	}
+	return a < b
+}
@@ -0,0 +1,115 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cisco-open/fsoc/cmdkit/clierror"
+)
+
+func newOptionsTestTable() *Table {
+	return &Table{
+		Headers: []string{"name", "count", "url"},
+		Lines: [][]string{
+			{"prod-b", "10", "https://b"},
+			{"dev", "9", "https://d"},
+			{"prod-a", "100", "https://a"},
+		},
+	}
+}
+
+func TestTableOptionsSort(t *testing.T) {
+	in := newOptionsTestTable()
+
+	opts := tableOptions{sortBy: "COUNT"}
+	table, err := opts.apply(in)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dev", "prod-b", "prod-a"}, column(table, 0), "numeric sort")
+
+	opts = tableOptions{sortBy: "name", descending: true}
+	table, err = opts.apply(in)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prod-b", "prod-a", "dev"}, column(table, 0))
+
+	// the original table is not modified
+	assert.Equal(t, newOptionsTestTable(), in)
+}
+
+func TestTableOptionsFilterAndColumns(t *testing.T) {
+	opts := tableOptions{filters: []string{"name=prod-*"}, columns: []string{"url", "Name"}, noHeaders: true, wide: true}
+	table, err := opts.apply(newOptionsTestTable())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"url", "name"}, table.Headers)
+	assert.Equal(t, [][]string{{"https://b", "prod-b"}, {"https://a", "prod-a"}}, table.Lines)
+	assert.True(t, table.OmitHeaders)
+	assert.True(t, table.DisableAutoWrapText)
+}
+
+func TestTableOptionsErrors(t *testing.T) {
+	for _, opts := range []tableOptions{
+		{sortBy: "bogus"},
+		{filters: []string{"name"}},
+		{filters: []string{"bogus=*"}},
+		{filters: []string{"name=[a"}},
+		{columns: []string{"name", "bogus"}},
+	} {
+		_, err := opts.apply(newOptionsTestTable())
+		assert.Error(t, err, "options %+v", opts)
+		assert.Equal(t, clierror.Usage, clierror.KindOf(err), "options %+v", opts)
+	}
+}
+
+func TestValidateTableFlags(t *testing.T) {
+	for _, test := range []struct {
+		args  []string
+		valid bool
+	}{
+		{[]string{}, true},
+		{[]string{"--sort-by", "name,desc", "--filter-rows", "name=prod-*", "--columns", "name,url"}, true},
+		{[]string{"--sort-by", "bogus"}, true}, // column names are checked when displaying
+		{[]string{"--sort-by", ",desc"}, false},
+		{[]string{"--sort-by", "name,sideways"}, false},
+		{[]string{"--filter-rows", "name"}, false},
+		{[]string{"--filter-rows", "=prod"}, false},
+		{[]string{"--filter-rows", "name=[a"}, false},
+		{[]string{"--columns", " , "}, false},
+	} {
+		cmd := &cobra.Command{Use: "test"}
+		cmd.Flags().String("sort-by", "", "")
+		cmd.Flags().StringArray("filter-rows", nil, "")
+		cmd.Flags().String("columns", "", "")
+		require.NoError(t, cmd.ParseFlags(test.args))
+
+		err := ValidateTableFlags(cmd)
+		if test.valid {
+			assert.NoError(t, err, "args %q", test.args)
+		} else {
+			assert.Equal(t, clierror.Usage, clierror.KindOf(err), "args %q", test.args)
+		}
+	}
+}
+
+func column(table *Table, index int) []string {
+	values := []string{}
+	for _, line := range table.Lines {
+		values = append(values, line[index])
+	}
+	return values
+}
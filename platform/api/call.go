@@ -27,6 +27,7 @@ import (
 	"os"
 	"slices"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/apex/log"
@@ -53,8 +54,19 @@ type Options struct {
 	// Quiet suppresses the interactive spinner and display of request
 	Quiet bool
 
-	// Context provides a Go context for the API call (nil is accepted and will be replaced with a default context)
+	// Context provides a Go context for the API call (nil is accepted and will be replaced with the base context, see SetBaseContext)
 	Context context.Context
+
+	// Timeout sets a deadline for the API call, including any login and retries (0 for no deadline other than the context's)
+	Timeout time.Duration
+
+	// Retry overrides the retry policy for transient failures (nil uses the profile settings, see RetryPolicy)
+	Retry *RetryPolicy
+
+	// Profile is the name of the profile (config context) to use for the call instead of the current one,
+	// allowing a command to access more than one tenant. The profile's session (access token) is used and
+	// updated like the current profile's. Subsystem settings (e.g., the retry policy) are always those of the current profile.
+	Profile string
 }
 
 // JSONGet performs a GET request and parses the response as JSON
@@ -132,7 +144,7 @@ func prepareHTTPRequest(ctx *callContext, client *http.Client, method string, pa
 	path, query, _ := strings.Cut(path, "?")
 	uri, err := url.Parse(cfg.URL)
 	if err != nil {
-		log.Fatalf("Failed to parse the url provided in context (%q): %v", cfg.URL, err)
+		return nil, fmt.Errorf("failed to parse the url provided in context (%q): %v", cfg.URL, err)
 	}
 	// Create the full path again ensuring that we aren't double escaping characters in the path
 	joinedPath, err := url.JoinPath(uri.String(), path)
@@ -214,77 +226,108 @@ func httpRequest(method string, path string, body any, out any, options *Options
 		options = &Options{}
 	}
 
-	callCtx := newCallContext(options.Context, options.Quiet)
+	callCtx, err := newCallContext(options.Context, options.Quiet, options.Profile)
+	if err != nil {
+		return err
+	}
 	defer callCtx.stopSpinner(false) // ensure the spinner is not running when returning (belt & suspenders)
 
-	// force login if no token
-	if callCtx.cfg.Token == "" {
-		log.Info("No auth token available, trying to log in")
+	// apply the call's deadline, if requested
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx.goContext, cancel = context.WithTimeoutCause(callCtx.goContext, options.Timeout, fmt.Errorf("platform API call timed out after %v", options.Timeout))
+		defer cancel()
+	}
+
+	// force login if no token or the token is about to expire
+	if needsLogin(callCtx.cfg) {
+		if callCtx.cfg.Token == "" {
+			log.Info("No auth token available, trying to log in")
+		} else {
+			log.WithField("expiry", tokenExpiry(callCtx.cfg)).Info("Auth token expires soon, refreshing it")
+		}
 		if err := login(callCtx); err != nil {
 			return err
 		}
 		// note: callCtx.cfg has been updated by login()
 	}
 
-	// create http client for the request
+	// create http client for the request, using the profile's network settings
+	transport, err := httpTransport(callCtx.cfg)
+	if err != nil {
+		return err
+	}
 	client := &http.Client{
+		Transport: cassetteTransport(transport), // records/replays the calls, if requested
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse
 		},
 	}
 
-	// build HTTP request
-	req, err := prepareHTTPRequest(callCtx, client, method, path, body, options.Headers)
-	if err != nil {
-		return err // assume error messages provide sufficient info
-	}
-
-	// execute request, speculatively, assuming the auth token is valid
-	callCtx.startSpinner(fmt.Sprintf("Platform API call (%v %v)", req.Method, urlDisplayPath(req.URL)))
-	resp, err := client.Do(req)
-	if err != nil {
-		// nb: spinner will be stopped by defer
-		return fmt.Errorf("%v request to %q failed: %w", method, req.URL.String(), err)
-	}
-
-	// collect response body (whether success or error)
+	// execute request, speculatively, assuming the auth token is valid; retry with
+	// a fresh token if rejected and with backoff on transient failures
+	retryPolicy := effectiveRetryPolicy(options.Retry)
+	loggedIn := false
+	var resp *http.Response
 	var respBytes []byte
-	defer resp.Body.Close()
-	respBytes, err = io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed reading response to %v to %q (status %v): %w", method, req.URL.String(), resp.StatusCode, err)
-	}
-
-	// handle special case when access token needs to be refreshed and request retried
-	if resp.StatusCode == http.StatusForbidden {
-		callCtx.stopSpinnerHide()
-		log.Warn("Current token is no longer valid; trying to refresh")
-		err := login(callCtx)
+	for attempt := 1; ; attempt++ {
+		// build HTTP request (rebuilt on each attempt, as the body reader is consumed and the token may change)
+		req, err := prepareHTTPRequest(callCtx, client, method, path, body, options.Headers)
 		if err != nil {
-			return fmt.Errorf("failed to login: %w", err)
+			return err // assume error messages provide sufficient info
 		}
 
-		// note: callCtx.cfg has been updated by login()
-
-		// retry the request
-		log.Info("Retrying the request with the refreshed token")
-		req, err = prepareHTTPRequest(callCtx, client, method, path, body, options.Headers)
-		if err != nil {
-			return err // error should have enough context
-		}
-		callCtx.startSpinner(fmt.Sprintf("Platform API call, retry after login (%v %v)", req.Method, urlDisplayPath(req.URL)))
+		// execute request
+		callCtx.startSpinner(fmt.Sprintf("Platform API call (%v %v)", req.Method, urlDisplayPath(req.URL)))
 		resp, err = client.Do(req)
-		// leave the spinner until the outcome is finalized, return will stop/fail it
 		if err != nil {
-			return fmt.Errorf("%v request to %q failed: %w", method, req.URL.String(), err)
+			if retryPolicy.shouldRetryError(method, attempt, err) {
+				if _, err := waitBeforeRetry(callCtx, &retryPolicy, attempt, "", err.Error()); err != nil {
+					return err
+				}
+				continue
+			}
+			// nb: spinner will be stopped by defer
+			return requestError(callCtx, req, err)
 		}
 
 		// collect response body (whether success or error)
-		defer resp.Body.Close()
 		respBytes, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
 		if err != nil {
 			return fmt.Errorf("failed reading response to %v to %q (status %v): %w", method, req.URL.String(), resp.StatusCode, err)
 		}
+
+		// handle special case when access token needs to be refreshed and request retried
+		// (once only; this doesn't count as an attempt). A token that is known to be valid
+		// is not refreshed: the principal is not authorized for the request.
+		if resp.StatusCode == http.StatusForbidden && !loggedIn && !hasValidToken(callCtx.cfg) {
+			callCtx.stopSpinnerHide()
+			log.Warn("Current token is no longer valid; trying to refresh")
+			err := login(callCtx)
+			if err != nil {
+				return fmt.Errorf("failed to login: %w", err)
+			}
+			// note: callCtx.cfg has been updated by login()
+
+			log.Info("Retrying the request with the refreshed token")
+			loggedIn = true
+			attempt--
+			continue
+		}
+
+		// handle transient failures
+		if retryPolicy.shouldRetryStatus(method, attempt, resp.StatusCode) {
+			retry, err := waitBeforeRetry(callCtx, &retryPolicy, attempt, resp.Header.Get("Retry-After"), resp.Status)
+			if err != nil {
+				return err
+			}
+			if retry {
+				continue
+			}
+		}
+
+		break
 	}
 
 	// return if API call response indicates error
@@ -296,7 +339,11 @@ func httpRequest(method string, path string, body any, out any, options *Options
 		} else {
 			log.WithFields(log.Fields{"status": resp.StatusCode}).Error("Platform API call failed")
 		}
-		return parseIntoError(resp, respBytes)
+		err := parseIntoError(resp, respBytes)
+		if resp.StatusCode == http.StatusForbidden && hasValidToken(callCtx.cfg) {
+			return authorizationError(callCtx.cfg, err)
+		}
+		return err
 	}
 
 	// ensure spinner is stopped, API call has succeeded
@@ -338,10 +385,46 @@ func httpRequest(method string, path string, body any, out any, options *Options
 	return nil
 }
 
+// requestError creates an error for a request that failed without a response, explaining
+// whether it was cancelled or timed out rather than failed
+func requestError(callCtx *callContext, req *http.Request, err error) error {
+	if callCtx.goContext.Err() != nil {
+		return fmt.Errorf("%v request to %q aborted: %w", req.Method, req.URL.String(), context.Cause(callCtx.goContext))
+	}
+	return fmt.Errorf("%v request to %q failed: %w", req.Method, req.URL.String(), err)
+}
+
+// waitBeforeRetry logs the transient failure and waits for the backoff period before the next attempt.
+// It returns false, without waiting, if the server asked to wait longer than MaxRetryAfter.
+func waitBeforeRetry(callCtx *callContext, policy *RetryPolicy, attempt int, retryAfter string, reason string) (bool, error) {
+	callCtx.stopSpinnerHide()
+	delay, ok := policy.backoff(attempt, retryAfter)
+	if !ok {
+		log.WithFields(log.Fields{
+			"reason":      reason,
+			"retry_after": retryAfter,
+		}).Warnf("Platform API call failed with a transient error; not retrying, since the platform asked to wait for more than %v", MaxRetryAfter)
+		return false, nil
+	}
+	log.WithFields(log.Fields{
+		"reason":       reason,
+		"attempt":      attempt,
+		"max_attempts": policy.MaxAttempts,
+		"delay":        delay.String(),
+	}).Warn("Platform API call failed with a transient error; retrying")
+	if err := sleepWithContext(callCtx.goContext, delay); err != nil {
+		return false, fmt.Errorf("retry of the platform API call aborted: %w", err)
+	}
+	return true, nil
+}
+
 // parseError creates an HttpStatusError error from HTTP response data
 // This method creates either a simple error with the status code and response body
 // or a wrapped Problem struct in case the response is of type "application/problem+json"
 func parseIntoError(resp *http.Response, respBytes []byte) error {
+	// keep the trace response (W3C trace context), so that the failure can be traced on the platform
+	traceResponse := resp.Header.Get("traceresponse")
+
 	// try various strategies for humanizing the error output, from the most
 	// specific to the generic
 
@@ -354,14 +437,14 @@ func parseIntoError(resp *http.Response, respBytes []byte) error {
 		if problem.Status == 0 {
 			problem.Status = resp.StatusCode
 		}
-		return &HttpStatusError{Message: http.StatusText(resp.StatusCode), StatusCode: resp.StatusCode, WrappedErr: &problem}
+		return &HttpStatusError{Message: http.StatusText(resp.StatusCode), StatusCode: resp.StatusCode, TraceResponse: traceResponse, WrappedErr: &problem}
 	}
 
 	// attempt to parse response as a generic JSON object
 	var errobj any
 	err = json.Unmarshal(respBytes, &errobj)
 	if err == nil {
-		return &HttpStatusError{Message: fmt.Sprintf("status %d, error response: %+v", resp.StatusCode, errobj), StatusCode: resp.StatusCode}
+		return &HttpStatusError{Message: fmt.Sprintf("status %d, error response: %+v", resp.StatusCode, errobj), StatusCode: resp.StatusCode, TraceResponse: traceResponse}
 	}
 
 	// fallback to code + response text data; if no text is provided, use the standard status text instead
@@ -369,7 +452,7 @@ func parseIntoError(resp *http.Response, respBytes []byte) error {
 	if text == "" {
 		text = http.StatusText(resp.StatusCode)
 	}
-	return &HttpStatusError{Message: fmt.Sprintf("status: %d %v", resp.StatusCode, text), StatusCode: resp.StatusCode}
+	return &HttpStatusError{Message: fmt.Sprintf("status: %d %v", resp.StatusCode, text), StatusCode: resp.StatusCode, TraceResponse: traceResponse}
 }
 
 // urlDisplayPath returns the URL path in a display-friendly form (may be abbreviated)
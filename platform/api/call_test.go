@@ -3,11 +3,14 @@ package api
 import (
 	"context"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/cisco-open/fsoc/config"
+	"github.com/cisco-open/fsoc/test"
 )
 
 func TestPrepareHTTPRequest(t *testing.T) {
@@ -23,3 +26,28 @@ func TestPrepareHTTPRequest(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, "http://localhost:8080/test/path/1", req.URL.String())
 }
+
+func TestCallWithProfile(t *testing.T) {
+	newServer := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"server": "` + name + `"}`))
+		}))
+	}
+	current := newServer("current")
+	defer current.Close()
+	other := newServer("other")
+	defer other.Close()
+	defer test.SetActiveConfigProfileServer(current.URL)()
+	require.NoError(t, config.UpsertContext(&config.Context{Name: "other", AuthMethod: config.AuthMethodNone, URL: other.URL}))
+	defer func() { _ = config.DeleteContext("other") }()
+
+	var out map[string]string
+	require.NoError(t, JSONGet("test", &out, nil))
+	assert.Equal(t, "current", out["server"])
+	require.NoError(t, JSONGet("test", &out, &Options{Profile: "other"}))
+	assert.Equal(t, "other", out["server"])
+
+	err := JSONGet("test", &out, &Options{Profile: "nosuchprofile"})
+	assert.ErrorIs(t, err, config.ErrProfileNotFound)
+}
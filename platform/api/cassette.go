@@ -0,0 +1,325 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/apex/log"
+)
+
+// Cassette mode records the platform API calls made by httpRequest into a directory, one
+// JSON file per request/response pair ("interaction"), and later replays them without
+// a live tenant. Requests are matched on method, path, query and a hash of the body;
+// identical requests are served in the order they were recorded (the last recorded response
+// is repeated once they are exhausted). Auth tokens and other secrets are scrubbed before
+// the interactions are saved, and login is skipped when replaying.
+
+const redactedValue = "REDACTED"
+
+// scrubbedHeaders lists the headers whose values are never saved in a cassette
+var scrubbedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// scrubbedBodyFields matches JSON string fields whose values are never saved in a cassette
+var scrubbedBodyFields = regexp.MustCompile(`("(?:access_token|refresh_token|id_token|token|client_secret|secret|password)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// cassetteInteraction is a recorded request/response pair, as stored in the cassette directory
+type cassetteInteraction struct {
+	Request  cassetteRequest  `json:"request"`
+	Response cassetteResponse `json:"response"`
+}
+
+type cassetteRequest struct {
+	Method   string      `json:"method"`
+	Path     string      `json:"path"`
+	Query    string      `json:"query,omitempty"`
+	Headers  http.Header `json:"headers,omitempty"`
+	Body     string      `json:"body,omitempty"` // for reference only, matching uses BodyHash
+	BodyHash string      `json:"bodyHash"`
+}
+
+type cassetteResponse struct {
+	StatusCode   int         `json:"statusCode"`
+	Headers      http.Header `json:"headers,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"bodyEncoding,omitempty"` // "base64" for binary bodies, empty for text
+}
+
+// cassette is an active recording or replay
+type cassette struct {
+	dir       string
+	replay    bool
+	mu        sync.Mutex
+	sequence  int                               // number of interactions recorded so far (record mode)
+	recorded  map[string][]*cassetteInteraction // interactions by match key (replay mode)
+	nextIndex map[string]int                    // next interaction to serve, by match key (replay mode)
+}
+
+// activeCassette is the current recording or replay, nil if cassette mode is not active
+var activeCassette *cassette
+
+// EnableRecording starts recording all platform API calls into the specified directory,
+// which is created if needed (global --record flag)
+func EnableRecording(dir string) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create recording directory %q: %w", dir, err)
+	}
+	activeCassette = &cassette{dir: dir}
+	log.WithField("dir", dir).Info("Recording platform API calls")
+	return nil
+}
+
+// EnableReplay loads the interactions recorded in the specified directory and serves all
+// platform API calls from them instead of the platform (global --replay flag)
+func EnableReplay(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return fmt.Errorf("failed to list recordings in %q: %w", dir, err)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no recordings found in %q", dir)
+	}
+	sort.Strings(files) // file names start with the sequence number
+
+	c := &cassette{
+		dir:       dir,
+		replay:    true,
+		recorded:  map[string][]*cassetteInteraction{},
+		nextIndex: map[string]int{},
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read recording %q: %w", file, err)
+		}
+		var interaction cassetteInteraction
+		if err := json.Unmarshal(data, &interaction); err != nil {
+			return fmt.Errorf("failed to parse recording %q: %w", file, err)
+		}
+		r := interaction.Request
+		key := cassetteKey(r.Method, r.Path, r.Query, r.BodyHash)
+		c.recorded[key] = append(c.recorded[key], &interaction)
+	}
+	activeCassette = c
+	log.WithFields(log.Fields{"dir": dir, "interactions": len(files)}).Info("Replaying recorded platform API calls")
+	return nil
+}
+
+// DisableCassette stops recording or replaying platform API calls
+func DisableCassette() {
+	activeCassette = nil
+}
+
+// isReplaying returns true if API calls are served from recordings
+func isReplaying() bool {
+	return activeCassette != nil && activeCassette.replay
+}
+
+// cassetteTransport returns a transport that records or replays requests if cassette mode
+// is active, or the provided transport otherwise
+func cassetteTransport(next http.RoundTripper) http.RoundTripper {
+	if activeCassette == nil {
+		return next
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &cassetteRoundTripper{cassette: activeCassette, next: next}
+}
+
+type cassetteRoundTripper struct {
+	cassette *cassette
+	next     http.RoundTripper
+}
+
+func (t *cassetteRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	bodyHash := requestBodyHash(req.Header.Get("Content-Type"), body)
+
+	if t.cassette.replay {
+		return t.cassette.replayResponse(req, bodyHash)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	if err := t.cassette.record(req, body, bodyHash, resp, respBody); err != nil {
+		log.Warnf("Failed to record platform API call: %v", err)
+	}
+	return resp, nil
+}
+
+// record saves a request/response pair as the next interaction in the cassette directory
+func (c *cassette) record(req *http.Request, body []byte, bodyHash string, resp *http.Response, respBody []byte) error {
+	interaction := cassetteInteraction{
+		Request: cassetteRequest{
+			Method:   req.Method,
+			Path:     req.URL.Path,
+			Query:    canonicalQuery(req.URL.RawQuery),
+			Headers:  scrubHeaders(req.Header),
+			BodyHash: bodyHash,
+		},
+		Response: cassetteResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    scrubHeaders(resp.Header),
+		},
+	}
+	if utf8.Valid(body) {
+		interaction.Request.Body = scrubBody(string(body))
+	}
+	if utf8.Valid(respBody) {
+		interaction.Response.Body = scrubBody(string(respBody))
+	} else {
+		interaction.Response.Body = base64.StdEncoding.EncodeToString(respBody)
+		interaction.Response.BodyEncoding = "base64"
+	}
+
+	data, err := json.MarshalIndent(interaction, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sequence++
+	name := fmt.Sprintf("%04d-%v%v.json", c.sequence, strings.ToLower(req.Method), fileNameSuffix(req.URL.Path))
+	return os.WriteFile(filepath.Join(c.dir, name), data, 0o600)
+}
+
+// replayResponse returns the recorded response for the request
+func (c *cassette) replayResponse(req *http.Request, bodyHash string) (*http.Response, error) {
+	key := cassetteKey(req.Method, req.URL.Path, canonicalQuery(req.URL.RawQuery), bodyHash)
+
+	c.mu.Lock()
+	interactions := c.recorded[key]
+	index := c.nextIndex[key]
+	if index < len(interactions)-1 {
+		c.nextIndex[key] = index + 1
+	}
+	c.mu.Unlock()
+
+	if len(interactions) == 0 {
+		return nil, fmt.Errorf("no recorded response for %v %v in %q", req.Method, req.URL.RequestURI(), c.dir)
+	}
+	recorded := interactions[index].Response
+
+	body := []byte(recorded.Body)
+	if recorded.BodyEncoding == "base64" {
+		var err error
+		body, err = base64.StdEncoding.DecodeString(recorded.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode recorded response for %v %v: %w", req.Method, req.URL.RequestURI(), err)
+		}
+	}
+	header := recorded.Headers
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// cassetteKey returns the key on which recorded interactions are matched
+func cassetteKey(method, path, query, bodyHash string) string {
+	return strings.Join([]string{method, path, query, bodyHash}, " ")
+}
+
+// canonicalQuery sorts the query parameters, so that their order doesn't affect matching
+func canonicalQuery(rawQuery string) string {
+	parts := strings.Split(rawQuery, "&")
+	sort.Strings(parts)
+	return strings.Trim(strings.Join(parts, "&"), "&")
+}
+
+// requestBodyHash returns a hash of the request body. The random boundary of multipart bodies
+// (e.g., solution uploads) is replaced, so that the same content always has the same hash.
+func requestBodyHash(contentType string, body []byte) string {
+	if mediaType, params, err := mime.ParseMediaType(contentType); err == nil && strings.HasPrefix(mediaType, "multipart/") {
+		if boundary := params["boundary"]; boundary != "" {
+			body = bytes.ReplaceAll(body, []byte(boundary), []byte("BOUNDARY"))
+		}
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+func scrubHeaders(header http.Header) http.Header {
+	scrubbed := header.Clone()
+	for _, name := range scrubbedHeaders {
+		if scrubbed.Get(name) != "" {
+			scrubbed.Set(name, redactedValue)
+		}
+	}
+	return scrubbed
+}
+
+func scrubBody(body string) string {
+	return scrubbedBodyFields.ReplaceAllString(body, `${1}"`+redactedValue+`"`)
+}
+
+// fileNameSuffix converts a URL path into a readable file name suffix
+func fileNameSuffix(path string) string {
+	suffix := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '.' {
+			return r
+		}
+		return '_'
+	}, path)
+	if len(suffix) > 80 {
+		suffix = suffix[len(suffix)-80:]
+	}
+	return strings.TrimRight("-"+strings.Trim(suffix, "_"), "-")
+}
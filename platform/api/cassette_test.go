@@ -0,0 +1,71 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cisco-open/fsoc/test"
+)
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "POST" {
+			_, _ = w.Write([]byte(`{"created": true, "access_token": "s3cr3t"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"name": "` + r.URL.Query().Get("name") + `"}`))
+	}))
+	defer test.SetActiveConfigProfileServer(server.URL)()
+	dir := t.TempDir()
+	defer DisableCassette()
+
+	// record
+	require.NoError(t, EnableRecording(dir))
+	options := &Options{Headers: map[string]string{"Authorization": "Bearer s3cr3t"}}
+	var out map[string]any
+	require.NoError(t, JSONGet("/things?name=a&x=1", &out, options))
+	assert.Equal(t, "a", out["name"])
+	require.NoError(t, JSONPost("/things", map[string]any{"name": "b"}, &out, options))
+	assert.Equal(t, 2, calls)
+	server.Close()
+
+	// secrets are not saved
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "s3cr3t")
+	}
+
+	// replay, with query parameters in a different order
+	require.NoError(t, EnableReplay(dir))
+	out = nil
+	require.NoError(t, JSONGet("/things?x=1&name=a", &out, nil))
+	assert.Equal(t, "a", out["name"])
+	out = nil
+	require.NoError(t, JSONPost("/things", map[string]any{"name": "b"}, &out, nil))
+	assert.Equal(t, true, out["created"])
+	assert.Equal(t, redactedValue, out["access_token"])
+
+	// requests that were not recorded fail
+	err = JSONPost("/things", map[string]any{"name": "c"}, &out, &Options{Retry: &RetryPolicy{MaxAttempts: 1}})
+	assert.ErrorContains(t, err, "no recorded response")
+}
+
+func TestRequestBodyHashMultipart(t *testing.T) {
+	h1 := requestBodyHash("multipart/form-data; boundary=abc123", []byte("--abc123\r\ndata\r\n--abc123--"))
+	h2 := requestBodyHash("multipart/form-data; boundary=xyz789", []byte("--xyz789\r\ndata\r\n--xyz789--"))
+	assert.Equal(t, h1, h2)
+	assert.NotEqual(t, h1, requestBodyHash("application/json", []byte("{}")))
+}
@@ -0,0 +1,144 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+
+	"github.com/apex/log"
+	"golang.org/x/net/http/httpproxy"
+
+	"github.com/cisco-open/fsoc/config"
+)
+
+// transportSettings are the profile settings that affect the HTTP transport
+type transportSettings struct {
+	caFile             string
+	insecureSkipVerify bool
+	clientCertFile     string
+	clientKeyFile      string
+	proxyURL           string
+	noProxy            string
+	disableKeepAlive   bool
+}
+
+// transports caches the HTTP transports by their settings, so that connections are reused
+// across the API calls made for the same profile
+var (
+	transports     = map[transportSettings]*http.Transport{}
+	transportsLock sync.Mutex
+)
+
+// newHTTPClient returns an HTTP client for making requests using the network settings of
+// the profile (CA bundle, client certificate, proxy, etc.)
+func newHTTPClient(cfg *config.Context) (*http.Client, error) {
+	transport, err := httpTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// httpTransport returns the shared HTTP transport for the profile's network settings,
+// creating it if needed
+func httpTransport(cfg *config.Context) (*http.Transport, error) {
+	settings := transportSettings{
+		caFile:             cfg.CAFile,
+		insecureSkipVerify: cfg.InsecureSkipVerify,
+		clientCertFile:     cfg.ClientCertFile,
+		clientKeyFile:      cfg.ClientKeyFile,
+		proxyURL:           cfg.ProxyURL,
+		noProxy:            cfg.NoProxy,
+		disableKeepAlive:   cfg.DisableKeepAlive,
+	}
+
+	transportsLock.Lock()
+	defer transportsLock.Unlock()
+	if transport, found := transports[settings]; found {
+		return transport, nil
+	}
+	transport, err := newTransport(&settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up network access for profile %q: %w", cfg.Name, err)
+	}
+	transports[settings] = transport
+	return transport, nil
+}
+
+func newTransport(settings *transportSettings) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DisableKeepAlives = settings.disableKeepAlive
+
+	// TLS settings
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if settings.insecureSkipVerify {
+		log.Warn("TLS certificate verification is disabled for this profile (insecure_skip_verify); use only for development")
+		tlsConfig.InsecureSkipVerify = true
+	}
+	if settings.caFile != "" {
+		pem, err := os.ReadFile(settings.caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file %q: %w", settings.caFile, err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			log.Warnf("Failed to load the system's CA certificates (%v); using only the certificates from %q", err, settings.caFile)
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid PEM certificates found in CA file %q", settings.caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if settings.clientCertFile != "" || settings.clientKeyFile != "" {
+		if settings.clientCertFile == "" || settings.clientKeyFile == "" {
+			return nil, fmt.Errorf("both a client certificate and a client key file must be specified")
+		}
+		cert, err := tls.LoadX509KeyPair(settings.clientCertFile, settings.clientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate %q and key %q: %w", settings.clientCertFile, settings.clientKeyFile, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	// proxy settings: the profile's proxy (if any) replaces the HTTP(S)_PROXY environment
+	// variables and the profile's no-proxy list (if any) replaces NO_PROXY
+	if settings.proxyURL != "" || settings.noProxy != "" {
+		proxyConfig := httpproxy.FromEnvironment()
+		if settings.proxyURL != "" {
+			if _, err := url.Parse(settings.proxyURL); err != nil {
+				return nil, fmt.Errorf("invalid proxy URL %q: %w", settings.proxyURL, err)
+			}
+			proxyConfig.HTTPProxy = settings.proxyURL
+			proxyConfig.HTTPSProxy = settings.proxyURL
+		}
+		if settings.noProxy != "" {
+			proxyConfig.NoProxy = settings.noProxy
+		}
+		proxyFunc := proxyConfig.ProxyFunc()
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+	}
+
+	return transport, nil
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cisco-open/fsoc/config"
+)
+
+func TestHTTPTransportCAFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// the server's self-signed certificate is not trusted by default
+	client, err := newHTTPClient(&config.Context{Name: "test"})
+	require.NoError(t, err)
+	_, err = client.Get(server.URL)
+	assert.Error(t, err)
+
+	// trusted when provided as a CA file
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, caPem, 0o600))
+	client, err = newHTTPClient(&config.Context{Name: "test", CAFile: caFile})
+	require.NoError(t, err)
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestHTTPTransportSharedAndValidated(t *testing.T) {
+	cfg := &config.Context{Name: "test", ProxyURL: "http://proxy.example.com:8080", NoProxy: "localhost"}
+	t1, err := httpTransport(cfg)
+	require.NoError(t, err)
+	t2, err := httpTransport(&config.Context{Name: "other", ProxyURL: "http://proxy.example.com:8080", NoProxy: "localhost"})
+	require.NoError(t, err)
+	assert.Same(t, t1, t2, "transports must be shared for the same settings")
+
+	req, _ := http.NewRequest("GET", "https://tenant.example.com/x", nil)
+	proxyUrl, err := t1.Proxy(req)
+	require.NoError(t, err)
+	assert.Equal(t, "proxy.example.com:8080", proxyUrl.Host)
+	req, _ = http.NewRequest("GET", "http://localhost/x", nil)
+	proxyUrl, err = t1.Proxy(req)
+	require.NoError(t, err)
+	assert.Nil(t, proxyUrl)
+
+	_, err = httpTransport(&config.Context{Name: "test", ClientCertFile: "cert.pem"})
+	assert.ErrorContains(t, err, "client key")
+	_, err = httpTransport(&config.Context{Name: "test", CAFile: filepath.Join(t.TempDir(), "missing.pem")})
+	assert.ErrorContains(t, err, "failed to read CA file")
+}
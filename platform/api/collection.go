@@ -17,6 +17,8 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"strings"
@@ -30,74 +32,180 @@ const (
 	nextRelName    = "next"
 )
 
+// ErrStopIteration can be returned by a ForEachPage or ForEachItem callback to stop
+// iterating over the collection early; the iteration then returns nil
+var ErrStopIteration = errors.New("stop iteration")
+
+// CollectionPage is a single page of a collection, as provided to the ForEachPage callback
+type CollectionPage[T any] struct {
+	CollectionResult[T]
+
+	// Number is the sequence number of the page, starting from 1 at the path provided to ForEachPage
+	Number int
+
+	// Cursor is the path of the next page, which can be passed to ForEachPage in order to resume
+	// the iteration from where it stopped; empty if this is the last page
+	Cursor string
+}
+
+// CollectionPageError is returned by ForEachPage and ForEachItem when retrieving a page of the collection
+// fails. Pages before it have already been provided to the callback; the iteration can be resumed by
+// calling ForEachPage/ForEachItem with Cursor as the path.
+type CollectionPageError struct {
+	Number int    // sequence number of the page that failed, starting from 1
+	Cursor string // path of the page that failed
+	Err    error
+}
+
+func (e *CollectionPageError) Error() string {
+	return fmt.Sprintf("error retrieving page #%v of collection at %q: %v", e.Number, e.Cursor, e.Err)
+}
+
+func (e *CollectionPageError) Unwrap() error {
+	return e.Err
+}
+
 // JSONGetCollection performs a GET request and parses the response as JSON,
 // handling pagination per https://www.rfc-editor.org/rfc/rfc5988,
 // https://developer.cisco.com/api-guidelines/#rest-style/API.REST.STYLE.25 and
 // https://developer.cisco.com/api-guidelines/#rest-style/API.REST.STYLE.24
+// All items are accumulated in memory; use ForEachPage or ForEachItem to process large
+// collections page by page instead.
 func JSONGetCollection[T any](path string, out *CollectionResult[T], options *Options) (err error) {
+	err = ForEachPage(path, options, func(page *CollectionPage[T]) error {
+		// handle case where out.Items is uninitialized (nil) and page.Items is an initialized but empty slice
+		// append results in a nil slice instead of an empty slice in this case
+		if out.Items == nil && page.Items != nil {
+			out.Items = page.Items
+		} else {
+			out.Items = append(out.Items, page.Items...)
+		}
+		return nil
+	})
+	if err != nil {
+		var pageErr *CollectionPageError
+		if errors.As(err, &pageErr) && pageErr.Number > 1 {
+			return fmt.Errorf("Error retrieving non-first page #%v in collection at %q: %w. All data discarded", pageErr.Number, pageErr.Cursor, pageErr.Err)
+		}
+		return err
+	}
+
+	out.Total = len(out.Items)
+	return nil
+}
 
+// ForEachItem performs a GET request for a collection and calls fn for each item in it, requesting
+// subsequent pages of the collection as needed (see ForEachPage). Return ErrStopIteration from fn to
+// stop early.
+func ForEachItem[T any](path string, options *Options, fn func(item T) error) error {
+	return ForEachPage(path, options, func(page *CollectionPage[T]) error {
+		for _, item := range page.Items {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ForEachPage performs a GET request for a collection and calls fn for each page of it as it arrives,
+// handling pagination the same way as JSONGetCollection but without accumulating the items in memory.
+// Return ErrStopIteration from fn to stop early. Any other error returned by fn stops the iteration and
+// is returned as is. If a page cannot be retrieved, a *CollectionPageError is returned, whose Cursor
+// can be used to resume the iteration; the same applies to the Cursor of the last page provided to fn.
+func ForEachPage[T any](path string, options *Options, fn func(page *CollectionPage[T]) error) error {
 	subOptions := Options{}
 	if options != nil {
 		subOptions = *options // shallow copy
 	}
 
-	var pageNo, pageItemsCount, pageTotalCount int
-	for pageNo = 0; true; pageNo += 1 {
-		var page CollectionResult[T]
-		// request collection
-		err := httpRequest("GET", path, nil, &page, &subOptions)
+	// apply the deadline to the whole collection rather than to each page
+	subOptions.Context = contextOrBase(subOptions.Context)
+	if subOptions.Timeout > 0 {
+		var cancel context.CancelFunc
+		subOptions.Context, cancel = context.WithTimeoutCause(subOptions.Context, subOptions.Timeout, fmt.Errorf("collection retrieval timed out after %v", subOptions.Timeout))
+		defer cancel()
+		subOptions.Timeout = 0
+	}
+
+	var pageNo, itemsCount int
+	var page CollectionPage[T]
+	for pageNo = 1; true; pageNo += 1 {
+		// stop if cancelled or timed out between pages
+		if pageNo > 1 && subOptions.Context.Err() != nil {
+			return &CollectionPageError{Number: pageNo, Cursor: path, Err: fmt.Errorf("aborted: %w", context.Cause(subOptions.Context))}
+		}
+
+		// request collection page
+		page = CollectionPage[T]{Number: pageNo}
+		err := httpRequest("GET", path, nil, &page.CollectionResult, &subOptions)
 		if err != nil {
-			if pageNo > 0 {
-				return fmt.Errorf("Error retrieving non-first page #%v in collection at %q: %v. All data discarded", pageNo+1, path, err)
+			if pageNo > 1 {
+				return &CollectionPageError{Number: pageNo, Cursor: path, Err: err}
 			}
 			return err
 		}
+		itemsCount += len(page.Items)
 
-		// handle case where out.Items is uninitialized (nil) and page.Items is an initialized but empty slice
-		// append results in a nil slice instead of an empty slice in this case
-		if out.Items == nil && page.Items != nil {
-			out.Items = page.Items
+		// determine the path of the next page, if any
+		page.Cursor, err = nextPagePath(path, subOptions.ResponseHeaders)
+		if err != nil {
+			return err
+		}
+		if page.Cursor != "" {
+			log.Infof("Collection page #%v at %q returned %v items and indicated that more are available at %q for a total of %v", pageNo, path, len(page.Items), page.Cursor, page.Total)
 		} else {
-			out.Items = append(out.Items, page.Items...)
+			log.Infof("Collection page #%v at %q returned %v items (last page)", pageNo, path, len(page.Items))
 		}
 
-		pageItemsCount = len(page.Items)
-		pageTotalCount = page.Total
-
-		// break if no more pages (no response headers, no links or no next link)
-		if subOptions.ResponseHeaders == nil {
-			break
-		}
-		links, found := subOptions.ResponseHeaders[linkHeaderName]
-		if !found {
-			break
-		}
-		next, found := link.Parse(strings.Join(links, ", "))[nextRelName]
-		if !found {
-			break
+		// process the page
+		if err := fn(&page); err != nil {
+			if errors.Is(err, ErrStopIteration) {
+				return nil
+			}
+			return err
 		}
 
-		// compute path to the next page, working around incomplete paths usually returned by APIs
-		// This is done by keeping the original path up to the query string and just replacing the query string
-		log.Infof("Collection page #%v at %q returned %v items and indicated that more are available at %q for a total of %v", pageNo+1, path, len(page.Items), next, page.Total)
-		nextUrl, err := url.Parse(next.String())
-		if err != nil {
-			return fmt.Errorf("failed to parse collection iterator link(s) %v: %v ", links, err)
-		}
-		nextQuery := nextUrl.RawQuery
-		nextUrl, err = url.Parse(path)
-		if err != nil {
-			return fmt.Errorf("failed to parse path %q: %v", path, err)
+		if page.Cursor == "" {
+			break
 		}
-		nextUrl.RawQuery = nextQuery
-		path = nextUrl.String()
+		path = page.Cursor
 	}
-	log.Infof("Collection page #%v at %q returned %v items (last page)", pageNo+1, path, pageItemsCount)
 
-	out.Total = len(out.Items)
-	if out.Total != pageTotalCount {
-		log.Warnf("Collection at %q returned %v items vs. expected %v items", path, out.Total, pageTotalCount)
+	if itemsCount != page.Total {
+		log.Warnf("Collection at %q returned %v items vs. expected %v items", path, itemsCount, page.Total)
 	}
 
 	return nil
 }
+
+// nextPagePath returns the path to the next page of a collection, based on the response
+// headers of the current page, or an empty string if there are no more pages (no response
+// headers, no links or no next link)
+func nextPagePath(path string, headers map[string][]string) (string, error) {
+	if headers == nil {
+		return "", nil
+	}
+	links, found := headers[linkHeaderName]
+	if !found {
+		return "", nil
+	}
+	next, found := link.Parse(strings.Join(links, ", "))[nextRelName]
+	if !found {
+		return "", nil
+	}
+
+	// compute path to the next page, working around incomplete paths usually returned by APIs
+	// This is done by keeping the original path up to the query string and just replacing the query string
+	nextUrl, err := url.Parse(next.String())
+	if err != nil {
+		return "", fmt.Errorf("failed to parse collection iterator link(s) %v: %v ", links, err)
+	}
+	nextQuery := nextUrl.RawQuery
+	nextUrl, err = url.Parse(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse path %q: %v", path, err)
+	}
+	nextUrl.RawQuery = nextQuery
+	return nextUrl.String(), nil
+}
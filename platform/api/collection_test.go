@@ -0,0 +1,121 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cisco-open/fsoc/test"
+)
+
+// newCollectionServer serves a collection of 3 pages with 2 items each; failPage, if not 0, returns
+// a 400 error for that page
+func newCollectionServer(failPage int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := 1
+		if p := r.URL.Query().Get("page"); p != "" {
+			page, _ = strconv.Atoi(p)
+		}
+		if page == failPage {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if page < 3 {
+			w.Header().Set("Link", fmt.Sprintf(`</items?page=%v>; rel="next"`, page+1))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"items": [%v, %v], "total": 6}`, page*10+1, page*10+2)
+	}))
+}
+
+func TestForEachPage(t *testing.T) {
+	server := newCollectionServer(0)
+	defer server.Close()
+	defer test.SetActiveConfigProfileServer(server.URL)()
+
+	var items []int
+	var cursors []string
+	err := ForEachPage("/items", &Options{Retry: &RetryPolicy{MaxAttempts: 1}}, func(page *CollectionPage[int]) error {
+		items = append(items, page.Items...)
+		cursors = append(cursors, page.Cursor)
+		assert.Equal(t, len(cursors), page.Number)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{11, 12, 21, 22, 31, 32}, items)
+	assert.Equal(t, []string{"/items?page=2", "/items?page=3", ""}, cursors)
+}
+
+func TestForEachItemStopAndResume(t *testing.T) {
+	server := newCollectionServer(0)
+	defer server.Close()
+	defer test.SetActiveConfigProfileServer(server.URL)()
+
+	// stop after the first page
+	var cursor string
+	err := ForEachPage("/items", nil, func(page *CollectionPage[int]) error {
+		cursor = page.Cursor
+		return ErrStopIteration
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "/items?page=2", cursor)
+
+	// resume from the cursor
+	var items []int
+	err = ForEachItem(cursor, nil, func(item int) error {
+		items = append(items, item)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{21, 22, 31, 32}, items)
+}
+
+func TestForEachPageError(t *testing.T) {
+	server := newCollectionServer(2)
+	defer server.Close()
+	defer test.SetActiveConfigProfileServer(server.URL)()
+
+	var items []int
+	err := ForEachItem("/items", nil, func(item int) error {
+		items = append(items, item)
+		return nil
+	})
+	var pageErr *CollectionPageError
+	require.True(t, errors.As(err, &pageErr))
+	assert.Equal(t, 2, pageErr.Number)
+	assert.Equal(t, "/items?page=2", pageErr.Cursor)
+	assert.Equal(t, []int{11, 12}, items) // first page was delivered
+
+	// callback errors are returned as is
+	cbErr := errors.New("callback failed")
+	err = ForEachItem("/items", nil, func(item int) error { return cbErr })
+	assert.Equal(t, cbErr, err)
+}
+
+func TestJSONGetCollection(t *testing.T) {
+	server := newCollectionServer(0)
+	defer server.Close()
+	defer test.SetActiveConfigProfileServer(server.URL)()
+
+	var result CollectionResult[int]
+	require.NoError(t, JSONGetCollection("/items", &result, nil))
+	assert.Equal(t, []int{11, 12, 21, 22, 31, 32}, result.Items)
+	assert.Equal(t, 6, result.Total)
+
+	failServer := newCollectionServer(3)
+	defer failServer.Close()
+	defer test.SetActiveConfigProfileServer(failServer.URL)()
+
+	result = CollectionResult[int]{}
+	err := JSONGetCollection("/items", &result, nil)
+	assert.ErrorContains(t, err, "Error retrieving non-first page #3")
+	var statusErr *HttpStatusError
+	require.ErrorAs(t, err, &statusErr) // keeps the error chain, for classification of the failure
+	assert.Equal(t, http.StatusBadRequest, statusErr.StatusCode)
+}
@@ -16,6 +16,7 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"strings"
 	"time"
@@ -32,26 +33,54 @@ type callContext struct {
 	goContext context.Context
 	cfg       *config.Context
 	spinner   *spinner.Spinner
+	oauthFlow string // OAuth login flow overriding the profile's setting (see LoginOptions)
 }
 
+// baseContext is the Go context used for API calls that don't provide their own (see SetBaseContext)
+var baseContext = context.Background()
+
 var statusChar = map[bool]string{
 	false: color.RedString("\u00d7"),   // cross mark
 	true:  color.GreenString("\u2713"), // checkmark
 }
 
-func newCallContext(goContext context.Context, quiet bool) *callContext {
-	// get current config context
-	cfg := config.GetCurrentContext()
-	if cfg == nil {
-		log.Fatal(`Missing context; use "fsoc config create" to configure your context`)
-		panic("unreachable") // keep golintci happy (until it recognizes apex/log fatals)
+// SetBaseContext sets the Go context to use for all API calls that don't specify one
+// in Options.Context. This allows cancelling in-progress calls (e.g., on interrupt) and
+// applying command-wide deadlines (e.g., the --timeout flag).
+func SetBaseContext(ctx context.Context) {
+	if ctx == nil {
+		ctx = context.Background()
 	}
-	log.WithFields(log.Fields{"context": cfg.Name, "url": cfg.URL, "tenant": cfg.Tenant}).Info("Using context")
+	baseContext = ctx
+}
+
+// contextOrBase returns the provided Go context or, if nil, the base context
+func contextOrBase(ctx context.Context) context.Context {
+	if ctx == nil {
+		return baseContext
+	}
+	return ctx
+}
 
-	// use background if no context was provided
-	if goContext == nil {
-		goContext = context.Background()
+// newCallContext prepares the context for an API call, using the named profile or, if empty, the current one
+func newCallContext(goContext context.Context, quiet bool, profile string) (*callContext, error) {
+	// get config context
+	var cfg *config.Context
+	if profile != "" {
+		var err error
+		if cfg, err = config.GetContext(profile); err != nil {
+			return nil, fmt.Errorf("cannot use profile: %w", err)
+		}
+	} else {
+		cfg = config.GetCurrentContext()
+		if cfg == nil {
+			return nil, fmt.Errorf(`missing context; use "fsoc config create" to configure your context`)
+		}
 	}
+	log.WithFields(log.Fields{"context": cfg.Name, "url": cfg.URL, "tenant": cfg.Tenant}).Info("Using context")
+
+	// use the base context if no context was provided
+	goContext = contextOrBase(goContext)
 
 	// create spinner if needed
 	var spinnerObj *spinner.Spinner
@@ -61,12 +90,12 @@ func newCallContext(goContext context.Context, quiet bool) *callContext {
 
 	// prepare call context
 	callCtx := callContext{
-		goContext,
-		cfg,
-		spinnerObj,
+		goContext: goContext,
+		cfg:       cfg,
+		spinner:   spinnerObj,
 	}
 
-	return &callCtx
+	return &callCtx, nil
 }
 
 func (c *callContext) startSpinner(msg string) {
@@ -0,0 +1,151 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/apex/log"
+
+	"github.com/cisco-open/fsoc/config"
+)
+
+// stdinCredentials holds the credentials read from the standard input, which can be read only once
+// per process but may be needed for more than one login (e.g., when the access token expires)
+var stdinCredentials struct {
+	once sync.Once
+	data []byte
+	err  error
+}
+
+// principalCredentials obtains the service principal (or, if agent is true, agent principal) credentials
+// from the profile's credentials source (see config.ParseCredentialsSource). Credentials provided inline
+// in the profile (see config.LoadEnvContext) take precedence. Credentials obtained from the standard input
+// or a command are in the same JSON format as the respective credentials file.
+func principalCredentials(ctx *callContext, agent bool) (*credentialsStruct, error) {
+	cfg := ctx.cfg
+	if cfg.ClientID != "" {
+		return &credentialsStruct{ClientID: cfg.ClientID, Secret: cfg.ClientSecret}, nil
+	}
+
+	source, command, err := config.ParseCredentialsSource(cfg.CredentialsSource)
+	if err != nil {
+		return nil, err
+	}
+	log.WithField("source", cfg.CredentialsSource).Info("Obtaining principal credentials")
+
+	switch source {
+	case config.CredentialsSourceEnv:
+		return envCredentials()
+	case config.CredentialsSourceStdin:
+		data, err := readStdinCredentials()
+		if err != nil {
+			return nil, err
+		}
+		return parseCredentials(data, agent, "the standard input")
+	case config.CredentialsSourceExec:
+		data, err := execCredentials(ctx, command)
+		if err != nil {
+			return nil, err
+		}
+		return parseCredentials(data, agent, fmt.Sprintf("the output of %q", command[0]))
+	}
+
+	// read credentials file
+	var credentials *credentialsStruct
+	file := cfg.SecretFile
+	if agent {
+		credentials, err = readAgentCredentials(file)
+	} else {
+		if file == "" {
+			file = cfg.CsvFile // implicitly update config schema (backward compatibility)
+		}
+		credentials, err = readServiceCredentials(file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file %q: %v", file, err)
+	}
+	return credentials, nil
+}
+
+// usesSecretFile returns true if the profile's principal credentials are read from the secret file
+func usesSecretFile(cfg *config.Context) bool {
+	if cfg.ClientID != "" {
+		return false
+	}
+	source, _, err := config.ParseCredentialsSource(cfg.CredentialsSource)
+	return err != nil || source == config.CredentialsSourceFile // an invalid source is reported on login
+}
+
+func envCredentials() (*credentialsStruct, error) {
+	clientID := os.Getenv(config.FSOC_CLIENT_ID_ENVVAR)
+	secret := os.Getenv(config.FSOC_CLIENT_SECRET_ENVVAR)
+	if clientID == "" || secret == "" {
+		return nil, fmt.Errorf("the %v and %v environment variables must be set to provide the credentials", config.FSOC_CLIENT_ID_ENVVAR, config.FSOC_CLIENT_SECRET_ENVVAR)
+	}
+	return &credentialsStruct{ClientID: clientID, Secret: secret}, nil
+}
+
+func readStdinCredentials() ([]byte, error) {
+	stdinCredentials.once.Do(func() {
+		if fi, err := os.Stdin.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+			fmt.Fprintln(os.Stderr, "Enter the credentials JSON, followed by end of file (Ctrl-D):")
+		}
+		stdinCredentials.data, stdinCredentials.err = io.ReadAll(os.Stdin)
+		if stdinCredentials.err != nil {
+			stdinCredentials.err = fmt.Errorf("failed to read the credentials from the standard input: %w", stdinCredentials.err)
+		}
+	})
+	return stdinCredentials.data, stdinCredentials.err
+}
+
+func execCredentials(ctx *callContext, command []string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx.goContext, command[0], command[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return nil, fmt.Errorf("the credentials command %q failed: %w", command[0], err)
+		}
+		return nil, fmt.Errorf("the credentials command %q failed: %w: %v", command[0], err, msg)
+	}
+	return stdout.Bytes(), nil
+}
+
+// parseCredentials parses JSON credentials in the service or agent principal credentials file format
+func parseCredentials(data []byte, agent bool, source string) (*credentialsStruct, error) {
+	var credentials *credentialsStruct
+	var err error
+	if agent {
+		credentials, err = parseAgentJsonCredentials(data)
+	} else {
+		credentials, err = parseJsonCredentials(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the credentials from %v: %w", source, err)
+	}
+	if credentials.ClientID == "" || credentials.Secret == "" {
+		return nil, fmt.Errorf("the credentials from %v are missing the client ID or secret", source)
+	}
+	return credentials, nil
+}
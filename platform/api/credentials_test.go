@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cisco-open/fsoc/config"
+)
+
+func newCredentialsCallContext(source string) *callContext {
+	return &callContext{
+		goContext: context.Background(),
+		cfg:       &config.Context{Name: "test", AuthMethod: config.AuthMethodServicePrincipal, CredentialsSource: source},
+	}
+}
+
+func TestPrincipalCredentialsFromEnv(t *testing.T) {
+	t.Setenv(config.FSOC_CLIENT_ID_ENVVAR, "id")
+	t.Setenv(config.FSOC_CLIENT_SECRET_ENVVAR, "secret")
+	ctx := newCredentialsCallContext(config.CredentialsSourceEnv)
+	assert.False(t, usesSecretFile(ctx.cfg))
+
+	credentials, err := principalCredentials(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, &credentialsStruct{ClientID: "id", Secret: "secret"}, credentials)
+
+	t.Setenv(config.FSOC_CLIENT_SECRET_ENVVAR, "")
+	_, err = principalCredentials(ctx, false)
+	assert.ErrorContains(t, err, config.FSOC_CLIENT_SECRET_ENVVAR)
+}
+
+func TestPrincipalCredentialsFromCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test uses a shell script")
+	}
+	dir := t.TempDir()
+	script := filepath.Join(dir, "creds")
+	require.NoError(t, os.WriteFile(script, []byte(`#!/bin/sh
+case "$1" in
+service) echo '{"Tenant ID": "t1", "Client ID": "sp-id", "Secret": "sp-secret"}' ;;
+agent) echo '{"id": "agent-id", "clientSecret": "agent-secret"}' ;;
+empty) echo '{}' ;;
+*) echo "unknown principal $1" >&2; exit 1 ;;
+esac
+`), 0700))
+
+	credentials, err := principalCredentials(newCredentialsCallContext("exec:"+script+" service"), false)
+	require.NoError(t, err)
+	assert.Equal(t, &credentialsStruct{TenantID: "t1", ClientID: "sp-id", Secret: "sp-secret"}, credentials)
+
+	credentials, err = principalCredentials(newCredentialsCallContext("exec:"+script+" agent"), true)
+	require.NoError(t, err)
+	assert.Equal(t, &credentialsStruct{ClientID: "agent-id", Secret: "agent-secret"}, credentials)
+
+	_, err = principalCredentials(newCredentialsCallContext("exec:"+script+" empty"), false)
+	assert.ErrorContains(t, err, "missing the client ID or secret")
+
+	_, err = principalCredentials(newCredentialsCallContext("exec:"+script+" other"), false)
+	assert.ErrorContains(t, err, "unknown principal other")
+}
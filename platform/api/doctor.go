@@ -0,0 +1,286 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/cisco-open/fsoc/config"
+)
+
+// Diagnostic statuses
+const (
+	DiagnosticPass = "pass"
+	DiagnosticWarn = "warn"
+	DiagnosticFail = "fail"
+	DiagnosticSkip = "skip" // the check doesn't apply or depends on a check that failed
+)
+
+// diagnosticTimeout limits the time for each network check
+var diagnosticTimeout = 15 * time.Second
+
+// diagnosticPath is the API used to test an authenticated call; any principal with access to the tenant can read it
+const diagnosticPath = "knowledge-store/v1/objects/extensibility:solution?max=1"
+
+// Diagnostic is the result of a single profile check
+type Diagnostic struct {
+	Check   string `json:"check"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// Diagnose checks the current profile end to end, from its configuration through network access
+// to an authenticated API call, and returns the results of the checks in the order they were performed.
+// Checks that depend on a failed check are skipped. An authenticated call is made only if it doesn't
+// require an interactive login.
+func Diagnose(goContext context.Context) []Diagnostic {
+	results := []Diagnostic{}
+	add := func(check, status, format string, a ...any) {
+		results = append(results, Diagnostic{Check: check, Status: status, Message: fmt.Sprintf(format, a...)})
+	}
+	goContext = contextOrBase(goContext)
+
+	// profile configuration
+	cfg, err := config.GetContext(config.GetCurrentProfileName())
+	if errors.Is(err, config.ErrProfileNotFound) {
+		add("profile", DiagnosticFail, `no profile configured; use "fsoc config create" to create one`)
+		return results
+	} else if err != nil {
+		add("profile", DiagnosticFail, "%v", err)
+		return results
+	}
+	authErr := CheckConfigForAuth(cfg)
+	if authErr != nil {
+		add("profile", DiagnosticFail, "%v", authErr)
+	} else {
+		add("profile", DiagnosticPass, "profile %q, auth method %q", cfg.Name, cfg.AuthMethod)
+	}
+
+	// network access
+	uri, urlErr := parseProfileURL(cfg.URL)
+	if urlErr != nil {
+		add("url", DiagnosticFail, "%v", urlErr)
+	} else {
+		add("url", DiagnosticPass, "%v", uri)
+	}
+	if urlErr != nil || cfg.AuthMethod == config.AuthMethodLocal {
+		add("dns", DiagnosticSkip, "")
+		add("connection", DiagnosticSkip, "")
+	} else {
+		status, message := checkDNS(goContext, cfg, uri)
+		add("dns", status, "%v", message)
+		if status == DiagnosticFail {
+			add("connection", DiagnosticSkip, "")
+		} else {
+			status, message = checkConnection(goContext, cfg, uri)
+			add("connection", status, "%v", message)
+		}
+	}
+	networkOK := !hasFailed(results)
+
+	// tenant
+	switch {
+	case cfg.Tenant != "":
+		add("tenant", DiagnosticPass, "tenant %q configured", cfg.Tenant)
+	case cfg.AuthMethod != config.AuthMethodOAuth:
+		add("tenant", DiagnosticSkip, "not needed for the %q auth method", cfg.AuthMethod)
+	case !networkOK:
+		add("tenant", DiagnosticSkip, "")
+	default:
+		status, message := checkTenant(goContext, cfg)
+		add("tenant", status, "%v", message)
+	}
+
+	// token & authenticated call
+	status, message := checkToken(cfg)
+	add("token", status, "%v", message)
+	switch {
+	case hasFailed(results):
+		add("api call", DiagnosticSkip, "")
+	case status == DiagnosticWarn && cfg.AuthMethod == config.AuthMethodOAuth:
+		add("api call", DiagnosticSkip, `requires an interactive login; use "fsoc login" first`)
+	default:
+		status, message := checkAPICall(goContext)
+		add("api call", status, "%v", message)
+	}
+
+	// subsystem settings, including their validators
+	subsystems := make([]string, 0, len(cfg.SubsystemConfigs))
+	for name := range cfg.SubsystemConfigs {
+		subsystems = append(subsystems, name)
+	}
+	sort.Strings(subsystems)
+	for _, name := range subsystems {
+		single := &config.Context{SubsystemConfigs: map[string]map[string]any{name: cfg.SubsystemConfigs[name]}}
+		if err := config.UpdateSubsystemConfigs(single); err != nil {
+			add("subsystem "+name, DiagnosticFail, "%v", err)
+		} else {
+			add("subsystem "+name, DiagnosticPass, "%d setting(s)", len(cfg.SubsystemConfigs[name]))
+		}
+	}
+
+	return results
+}
+
+func hasFailed(results []Diagnostic) bool {
+	for _, result := range results {
+		if result.Status == DiagnosticFail {
+			return true
+		}
+	}
+	return false
+}
+
+func parseProfileURL(value string) (*url.URL, error) {
+	if value == "" {
+		return nil, fmt.Errorf("no URL configured")
+	}
+	uri, err := url.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %q: %w", value, err)
+	}
+	if uri.Scheme != "https" && uri.Scheme != "http" {
+		return nil, fmt.Errorf("invalid URL %q: the scheme must be https (or http)", value)
+	}
+	if uri.Hostname() == "" {
+		return nil, fmt.Errorf("invalid URL %q: no host name", value)
+	}
+	return uri, nil
+}
+
+// checkDNS resolves the profile's host name. A failure is only a warning if the profile
+// uses a proxy, since the proxy may resolve names that can't be resolved locally.
+func checkDNS(goContext context.Context, cfg *config.Context, uri *url.URL) (string, string) {
+	goContext, cancel := context.WithTimeout(goContext, diagnosticTimeout)
+	defer cancel()
+
+	addrs, err := net.DefaultResolver.LookupHost(goContext, uri.Hostname())
+	if err != nil {
+		if cfg.ProxyURL != "" || os.Getenv("HTTPS_PROXY") != "" || os.Getenv("https_proxy") != "" {
+			return DiagnosticWarn, fmt.Sprintf("%v (the proxy may be able to resolve it)", err)
+		}
+		return DiagnosticFail, err.Error()
+	}
+	return DiagnosticPass, fmt.Sprintf("%v resolves to %v", uri.Hostname(), addrs[0])
+}
+
+// checkConnection sends an unauthenticated request to the profile's URL, using the profile's
+// network settings; any HTTP response means that the platform is reachable (and TLS works)
+func checkConnection(goContext context.Context, cfg *config.Context, uri *url.URL) (string, string) {
+	client, err := newHTTPClient(cfg)
+	if err != nil {
+		return DiagnosticFail, err.Error()
+	}
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	goContext, cancel := context.WithTimeout(goContext, diagnosticTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(goContext, "GET", uri.String(), nil)
+	if err != nil {
+		return DiagnosticFail, err.Error()
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		var certErr *tls.CertificateVerificationError
+		if errors.As(err, &certErr) {
+			return DiagnosticFail, fmt.Sprintf("TLS certificate verification failed: %v; if the platform uses a private CA, set ca-file", certErr.Err)
+		}
+		return DiagnosticFail, err.Error()
+	}
+	resp.Body.Close()
+
+	message := fmt.Sprintf("HTTP status %v", resp.StatusCode)
+	if resp.TLS != nil {
+		message = fmt.Sprintf("%v, %v", tls.VersionName(resp.TLS.Version), message)
+	}
+	if cfg.InsecureSkipVerify {
+		return DiagnosticWarn, message + "; TLS certificate verification is disabled"
+	}
+	return DiagnosticPass, message
+}
+
+// checkTenant resolves the tenant ID from the profile's URL
+func checkTenant(goContext context.Context, cfg *config.Context) (string, string) {
+	if _, err := computeResolverEndpoint(cfg); err != nil {
+		return DiagnosticFail, err.Error()
+	}
+	goContext, cancel := context.WithTimeout(goContext, diagnosticTimeout)
+	defer cancel()
+
+	tenantID, err := resolveTenant(&callContext{goContext: goContext, cfg: cfg})
+	if err != nil {
+		return DiagnosticFail, fmt.Sprintf("could not resolve the tenant ID: %v", err)
+	}
+	return DiagnosticPass, fmt.Sprintf("resolved to %q", tenantID)
+}
+
+// checkToken checks whether the profile has an unexpired access token, when one is needed
+func checkToken(cfg *config.Context) (string, string) {
+	switch cfg.AuthMethod {
+	case config.AuthMethodNone, config.AuthMethodLocal:
+		return DiagnosticSkip, fmt.Sprintf("not needed for the %q auth method", cfg.AuthMethod)
+	}
+
+	if cfg.Token == "" {
+		if cfg.AuthMethod == config.AuthMethodJWT {
+			return DiagnosticFail, "no token configured"
+		}
+		return DiagnosticWarn, "not logged in"
+	}
+	expiry := tokenExpiry(cfg)
+	switch {
+	case expiry.IsZero():
+		return DiagnosticPass, "token present, expiration unknown"
+	case time.Now().After(expiry):
+		if cfg.RefreshToken != "" || (cfg.AuthMethod != config.AuthMethodOAuth && cfg.AuthMethod != config.AuthMethodJWT) {
+			return DiagnosticPass, fmt.Sprintf("token expired at %v; it will be renewed automatically", expiry.Local().Format(time.RFC3339))
+		}
+		if cfg.AuthMethod == config.AuthMethodJWT {
+			return DiagnosticFail, fmt.Sprintf("token expired at %v", expiry.Local().Format(time.RFC3339))
+		}
+		return DiagnosticWarn, fmt.Sprintf("token expired at %v", expiry.Local().Format(time.RFC3339))
+	}
+	return DiagnosticPass, fmt.Sprintf("token valid until %v", expiry.Local().Format(time.RFC3339))
+}
+
+// checkAPICall makes a read-only authenticated API call, logging in if needed
+func checkAPICall(goContext context.Context) (string, string) {
+	var out any
+	err := JSONGet(diagnosticPath, &out, &Options{
+		Context: goContext,
+		Quiet:   true,
+		Timeout: diagnosticTimeout,
+		Retry:   &RetryPolicy{MaxAttempts: 1},
+	})
+	if err != nil {
+		var statusErr *HttpStatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusForbidden {
+			return DiagnosticWarn, fmt.Sprintf("authenticated, but not authorized to read solutions: %v", err)
+		}
+		return DiagnosticFail, err.Error()
+	}
+	return DiagnosticPass, "authenticated call succeeded"
+}
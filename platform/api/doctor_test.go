@@ -0,0 +1,61 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cisco-open/fsoc/config"
+	"github.com/cisco-open/fsoc/test"
+)
+
+func diagnosticStatuses(results []Diagnostic) map[string]string {
+	statuses := map[string]string{}
+	for _, result := range results {
+		statuses[result.Check] = result.Status
+	}
+	return statuses
+}
+
+func TestDiagnose(t *testing.T) {
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	defer test.SetActiveConfigProfileServer(server.URL)()
+
+	// reachable profile without authentication
+	results := Diagnose(context.Background())
+	assert.Equal(t, map[string]string{
+		"profile":    DiagnosticPass,
+		"url":        DiagnosticPass,
+		"dns":        DiagnosticPass,
+		"connection": DiagnosticPass,
+		"tenant":     DiagnosticSkip,
+		"token":      DiagnosticSkip,
+		"api call":   DiagnosticPass,
+	}, diagnosticStatuses(results))
+	assert.Equal(t, []string{"/", "/knowledge-store/v1/objects/extensibility:solution"}, calls)
+
+	// jwt profile without a token and with an invalid subsystem setting
+	ctx, err := config.GetContext(test.TEST_CONTEXT_NAME)
+	require.NoError(t, err)
+	ctx.AuthMethod = config.AuthMethodJWT
+	ctx.SubsystemConfigs = map[string]map[string]any{"nosuchsubsystem": {"setting": "value"}}
+	require.NoError(t, config.UpsertContext(ctx))
+
+	calls = nil
+	statuses := diagnosticStatuses(Diagnose(context.Background()))
+	assert.Equal(t, DiagnosticFail, statuses["profile"])
+	assert.Equal(t, DiagnosticFail, statuses["token"])
+	assert.Equal(t, DiagnosticSkip, statuses["api call"])
+	assert.Equal(t, DiagnosticFail, statuses["subsystem nosuchsubsystem"])
+	assert.Equal(t, []string{"/"}, calls)
+}
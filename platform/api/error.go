@@ -15,9 +15,10 @@
 package api
 
 type HttpStatusError struct {
-	Message    string // used only if WrappedError is nil
-	StatusCode int
-	WrappedErr error
+	Message       string // used only if WrappedError is nil
+	StatusCode    int
+	TraceResponse string // value of the traceresponse header, if provided by the platform
+	WrappedErr    error
 }
 
 func (e *HttpStatusError) Error() string {
@@ -15,10 +15,12 @@
 package api
 
 import (
-	"context"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/apex/log"
 
@@ -35,6 +37,10 @@ var requiredSettings = map[string][]string{
 	config.AuthMethodJWT:              {"URL", "Token"}, // tenant is desired but may not be mandatory for all requests
 }
 
+// jwtExpiryWarned keeps the names of the profiles whose JWT token expiration has been reported,
+// so that the warning is displayed once per fsoc invocation rather than on each API call
+var jwtExpiryWarned sync.Map
+
 // fieldToFlag maps a config.Context field to CLI flag name, so that we can display better
 // help/error message for missing fields
 var fieldToFlag = map[string]string{
@@ -48,11 +54,30 @@ var fieldToFlag = map[string]string{
 	"LocalAuthOptions.AppdTid": "appd-tid",
 }
 
+// LoginOptions modify how Login logs in
+type LoginOptions struct {
+	// OAuthFlow selects the OAuth login flow (config.OAuthFlowBrowser or config.OAuthFlowDevice)
+	// instead of the profile's oauth_flow setting; empty to use the profile's setting
+	OAuthFlow string
+
+	// Profile is the name of the profile to log in to; empty for the current profile
+	Profile string
+}
+
 // Login performs a login into the platform API and saves the provided access token.
 // Login respects different access profile types (when supported) to provide the correct
 // login mechanism for each.
 func Login() error {
-	callCtx := newCallContext(context.Background(), false)
+	return LoginWithOptions(LoginOptions{})
+}
+
+// LoginWithOptions performs a login like Login, with options
+func LoginWithOptions(opts LoginOptions) error {
+	callCtx, err := newCallContext(baseContext, false, opts.Profile)
+	if err != nil {
+		return err
+	}
+	callCtx.oauthFlow = opts.OAuthFlow
 	defer callCtx.stopSpinner(false) // ensure not running when returning
 
 	return login(callCtx)
@@ -61,12 +86,24 @@ func Login() error {
 func login(callCtx *callContext) error {
 	log.Infof("Login is forced in order to get a valid access token")
 
+	// recorded responses don't require authentication
+	if isReplaying() {
+		log.Info("Replaying recorded platform API calls, login skipped")
+		return nil
+	}
+
 	// check current context for required fields
 	cfg := callCtx.cfg
 	if err := checkConfigForAuth(cfg); err != nil {
 		return err
 	}
 
+	original := *cfg // to detect whether there is anything to save
+
+	if callCtx.oauthFlow != "" && cfg.AuthMethod != config.AuthMethodOAuth {
+		log.Warnf("The %q login flow applies only to the %q authentication method; ignoring it", callCtx.oauthFlow, config.AuthMethodOAuth)
+	}
+
 	var authErr error
 	switch cfg.AuthMethod {
 	case config.AuthMethodLocal:
@@ -88,11 +125,30 @@ func login(callCtx *callContext) error {
 		return authErr
 	}
 
-	// update current context with logged in credentials (token(s)) to use
-	config.ReplaceCurrentContext(cfg)
+	// record when the token expires, to refresh it ahead of time
+	updateTokenExpiry(cfg)
+	if cfg.AuthMethod == config.AuthMethodJWT && needsLogin(cfg) {
+		if _, warned := jwtExpiryWarned.LoadOrStore(cfg.Name, true); !warned {
+			log.Warnf("The profile's access token expires at %v; please update it", tokenExpiry(cfg).Local().Format(time.RFC3339))
+		}
+	}
+
+	// nothing to save if the login didn't change the context (e.g., for a JWT token)
+	if reflect.DeepEqual(original, *cfg) {
+		return nil
+	}
+
+	// update the context with logged in credentials (token(s)) to use
+	if err := config.UpsertContext(cfg); err != nil {
+		return fmt.Errorf("failed to save the access token for profile %q: %w", cfg.Name, err)
+	}
 
 	// reload context
-	callCtx.cfg = config.GetCurrentContext()
+	reloaded, err := config.GetContext(cfg.Name)
+	if err != nil {
+		return err
+	}
+	callCtx.cfg = reloaded
 
 	return nil
 }
@@ -120,6 +176,16 @@ func recursiveWalkThrough(prefix string, value reflect.Value) []string {
 	return nonZeroFields
 }
 
+// CheckConfigForAuth checks that a context has all configuration required for its authentication
+// method, e.g., before adding it to the config file. The context is not modified.
+func CheckConfigForAuth(cfg *config.Context) error {
+	if cfg == nil {
+		return checkConfigForAuth(nil)
+	}
+	ctx := *cfg
+	return checkConfigForAuth(&ctx)
+}
+
 // checkConfigForAuth checks that all required configuration is available for the selected
 // authentication method, with detailed error messages and suggested remedies
 func checkConfigForAuth(cfg *config.Context) error {
@@ -136,6 +202,9 @@ func checkConfigForAuth(cfg *config.Context) error {
 
 	// get method's required settings
 	required, methodFound := requiredSettings[cfg.AuthMethod]
+	if !usesSecretFile(cfg) { // the principal credentials come from another source
+		required = slices.DeleteFunc(slices.Clone(required), func(field string) bool { return field == "SecretFile" })
+	}
 
 	// fail if method is not supported
 	if !methodFound {
@@ -19,3 +19,12 @@ func TestRecursiveWalkThroughStruct(t *testing.T) {
 	fields := nonZeroStructFields(&ctx)
 	assert.ElementsMatch(t, fields, []string{"Name", "AuthMethod", "LocalAuthOptions", "LocalAuthOptions.AppdTid"})
 }
+
+func TestCheckConfigForAuthInlineCredentials(t *testing.T) {
+	ctx := &config.Context{Name: "env", AuthMethod: config.AuthMethodServicePrincipal, URL: "https://t1.example.com"}
+	assert.ErrorContains(t, checkConfigForAuth(ctx), "secret-file")
+
+	ctx.ClientID, ctx.ClientSecret = "id", "secret"
+	assert.NoError(t, checkConfigForAuth(ctx))
+	assert.Equal(t, []string{"SecretFile"}, requiredSettings[config.AuthMethodServicePrincipal]) // not modified
+}
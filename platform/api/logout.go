@@ -0,0 +1,111 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/apex/log"
+
+	"github.com/cisco-open/fsoc/config"
+)
+
+const oauth2RevokeUriSuffix = "oauth2/revoke" // API for revoking tokens (RFC 7009)
+
+// Logout outcomes, see LogoutResult
+const (
+	LogoutRevoked     = "revoked"       // the refresh token was revoked by the platform and the tokens were cleared
+	LogoutCleared     = "cleared"       // the tokens were cleared locally only
+	LogoutNotLoggedIn = "not-logged-in" // there were no tokens to clear
+	LogoutSkipped     = "skipped"       // the token is part of the profile's configuration (jwt auth method)
+)
+
+// LogoutResult describes the outcome of logging out of a profile
+type LogoutResult struct {
+	Profile string `json:"profile"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// Logout ends the session of a profile: it revokes the refresh token at the platform (for
+// the oauth authentication method) and clears the access and refresh tokens from the profile.
+// Failure to revoke the token is reported in the result and doesn't prevent clearing it;
+// an error is returned only if the profile cannot be updated.
+func Logout(profile string) (*LogoutResult, error) {
+	cfg, err := config.GetContext(profile)
+	if err != nil {
+		return nil, err
+	}
+	result := &LogoutResult{Profile: profile}
+
+	if cfg.AuthMethod == config.AuthMethodJWT {
+		result.Status = LogoutSkipped
+		result.Message = `the token is set in the profile; use "fsoc config set token=..." to change it`
+		return result, nil
+	}
+	if cfg.Token == "" && cfg.RefreshToken == "" {
+		result.Status = LogoutNotLoggedIn
+		return result, nil
+	}
+
+	// revoke the refresh token, which also invalidates the access tokens issued with it
+	result.Status = LogoutCleared
+	if cfg.AuthMethod == config.AuthMethodOAuth && cfg.RefreshToken != "" {
+		callCtx := &callContext{goContext: baseContext, cfg: cfg}
+		if isReplaying() {
+			result.Message = "revocation skipped when replaying recorded calls"
+		} else if err := oauthRevokeToken(callCtx, cfg.RefreshToken, "refresh_token"); err != nil {
+			log.WithFields(log.Fields{"profile": profile, "error": err}).Warn("Failed to revoke the refresh token")
+			result.Message = fmt.Sprintf("failed to revoke the refresh token: %v", err)
+		} else {
+			result.Status = LogoutRevoked
+		}
+	} else {
+		result.Message = "the access token remains valid until it expires"
+	}
+
+	// clear the tokens from the profile
+	cfg.Token = ""
+	cfg.RefreshToken = ""
+	cfg.TokenExpiry = 0
+	if err := config.UpsertContext(cfg); err != nil {
+		return nil, fmt.Errorf("failed to clear the tokens of profile %q: %w", profile, err)
+	}
+
+	return result, nil
+}
+
+// oauthRevokeToken revokes a token at the OAuth revocation endpoint (RFC 7009)
+func oauthRevokeToken(ctx *callContext, token string, tokenTypeHint string) error {
+	client, err := newHTTPClient(ctx.cfg)
+	if err != nil {
+		return err
+	}
+
+	values := url.Values{}
+	values.Add("client_id", oauth2ClientId)
+	values.Add("token", token)
+	values.Add("token_type_hint", tokenTypeHint)
+	status, respBytes, err := postOAuthForm(ctx, client, oauthUriWithSuffix(ctx.cfg, oauth2RevokeUriSuffix), values)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK { // the server responds with 200 also if the token was already invalid
+		return oauthResponseError(status, respBytes)
+	}
+	return nil
+}
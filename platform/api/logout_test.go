@@ -0,0 +1,83 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cisco-open/fsoc/config"
+	"github.com/cisco-open/fsoc/test"
+)
+
+func setTestProfileSession(t *testing.T, authMethod string) {
+	ctx, err := config.GetContext(test.TEST_CONTEXT_NAME)
+	require.NoError(t, err)
+	ctx.AuthMethod = authMethod
+	ctx.Tenant = "tenant1"
+	ctx.Token = "access-token"
+	ctx.RefreshToken = "refresh-token"
+	ctx.TokenExpiry = 1
+	require.NoError(t, config.UpsertContext(ctx))
+}
+
+func TestLogout(t *testing.T) {
+	revokeStatus := http.StatusOK
+	var revoked []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		if r.URL.Path != "/auth/tenant1/default/"+oauth2RevokeUriSuffix {
+			http.NotFound(w, r)
+			return
+		}
+		assert.Equal(t, oauth2ClientId, r.PostForm.Get("client_id"))
+		assert.Equal(t, "refresh_token", r.PostForm.Get("token_type_hint"))
+		revoked = append(revoked, r.PostForm.Get("token"))
+		w.WriteHeader(revokeStatus)
+	}))
+	defer server.Close()
+	defer test.SetActiveConfigProfileServer(server.URL)()
+
+	tests := []struct {
+		authMethod   string
+		revokeStatus int
+		status       string
+		revoked      bool
+	}{
+		{config.AuthMethodOAuth, http.StatusOK, LogoutRevoked, true},
+		{config.AuthMethodOAuth, http.StatusBadRequest, LogoutCleared, true},
+		{config.AuthMethodServicePrincipal, http.StatusOK, LogoutCleared, false},
+		{config.AuthMethodJWT, http.StatusOK, LogoutSkipped, false},
+	}
+	for _, tt := range tests {
+		revoked = nil
+		revokeStatus = tt.revokeStatus
+		setTestProfileSession(t, tt.authMethod)
+
+		result, err := Logout(test.TEST_CONTEXT_NAME)
+		require.NoError(t, err)
+		assert.Equal(t, tt.status, result.Status, tt.authMethod)
+		if tt.revoked {
+			assert.Equal(t, []string{"refresh-token"}, revoked)
+		} else {
+			assert.Empty(t, revoked)
+		}
+
+		ctx, err := config.GetContext(test.TEST_CONTEXT_NAME)
+		require.NoError(t, err)
+		if tt.status == LogoutSkipped {
+			assert.Equal(t, "access-token", ctx.Token)
+			continue
+		}
+		assert.Empty(t, ctx.Token)
+		assert.Empty(t, ctx.RefreshToken)
+		assert.Zero(t, ctx.TokenExpiry)
+
+		// logging out again finds no session
+		result, err = Logout(test.TEST_CONTEXT_NAME)
+		require.NoError(t, err)
+		assert.Equal(t, LogoutNotLoggedIn, result.Status)
+	}
+}
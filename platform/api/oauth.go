@@ -16,6 +16,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -40,6 +41,9 @@ const (
 	oauthRedirectUri     = "http://127.0.0.1:3101/callback"
 )
 
+// oauthScopes are the scopes requested on login
+var oauthScopes = []string{"openid", "introspect_tokens", "offline_access"}
+
 // appTokens is what the AppD backend returns when it hands back the tokens (in exchange for the authorization code)
 type appTokens struct {
 	AccessToken  string `json:"access_token"` // aka JWT token to make requests
@@ -109,16 +113,58 @@ func oauthLogin(ctx *callContext) error {
 		}
 	}
 
+	// log in using the selected flow
+	var token *appTokens
+	var err error
+	if oauthFlow(ctx) == config.OAuthFlowDevice {
+		token, err = oauthDeviceLogin(ctx)
+	} else {
+		token, err = oauthBrowserLogin(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	userID, err := extractUser(token.AccessToken)
+	if err != nil {
+		log.Warnf("Could not extract user identity from the bearer token: %v. Continuing without user ID", err)
+		userID = ""
+		// fall through and continue without a user ID
+	} else {
+		log.WithFields(log.Fields{"userId": userID}).Info("Extracted user ID")
+	}
+
+	// update profile
+	ctx.cfg.Token = token.AccessToken
+	ctx.cfg.RefreshToken = token.RefreshToken
+	if userID != "" {
+		ctx.cfg.User = userID
+	}
+
+	return nil
+}
+
+// oauthFlow returns the OAuth login flow to use: the one requested for this login or the profile's setting
+func oauthFlow(ctx *callContext) string {
+	if ctx.oauthFlow != "" {
+		return ctx.oauthFlow
+	}
+	return ctx.cfg.OAuthFlow
+}
+
+// oauthBrowserLogin obtains tokens using the authorization code flow with PKCE, opening a browser
+// for the user to log in and receiving the authorization code on a localhost callback server
+func oauthBrowserLogin(ctx *callContext) (*appTokens, error) {
 	// generate PKCE codes
 	code, err := pkce.Generate()
 	if err != nil {
-		return err // should never really fail
+		return nil, err // should never really fail
 	}
 
 	// generate a nonce to match the callback uniquely to our request (aka "state")
 	stateCode, err := pkce.Generate()
 	if err != nil {
-		return err // should never really fail
+		return nil, err // should never really fail
 	}
 	state := string(stateCode)
 
@@ -131,7 +177,7 @@ func oauthLogin(ctx *callContext) error {
 			TokenURL:  oauthUriWithSuffix(ctx.cfg, oauth2TokenUriSuffix),
 			AuthStyle: oauth2.AuthStyleInParams,
 		},
-		Scopes: []string{"openid", "introspect_tokens", "offline_access"},
+		Scopes: oauthScopes,
 	}
 	url := conf.AuthCodeURL(state,
 		code.Method(),
@@ -141,12 +187,12 @@ func oauthLogin(ctx *callContext) error {
 	// open browser to perform login, collect auth with a localhost http server
 	authCode, err := getAuthorizationCodes(ctx, url)
 	if err != nil {
-		return fmt.Errorf("login failed to obtain the authorization code: %v", err)
+		return nil, fmt.Errorf("login failed to obtain the authorization code: %v", err)
 	}
 
 	// verify nonce, must match
 	if state != authCode.State {
-		return fmt.Errorf("login failed: received auth state doesn't match (a session replay or similar attack is likely in progress; please log out of all sessions!)")
+		return nil, fmt.Errorf("login failed: received auth state doesn't match (a session replay or similar attack is likely in progress; please log out of all sessions!)")
 	}
 
 	// TODO: make the exchange work with the auth2 package (fails, likely due to us needing urlencoded data)
@@ -160,26 +206,10 @@ func oauthLogin(ctx *callContext) error {
 	// exchange auth code for token (using a hand-crafted exchange request)
 	token, err := exchangeCodeForToken(ctx, conf, code, authCode)
 	if err != nil {
-		return fmt.Errorf("failed to exchange auth code for a token: %v", err.Error())
+		return nil, fmt.Errorf("failed to exchange auth code for a token: %v", err.Error())
 	}
 
-	userID, err := extractUser(token.AccessToken)
-	if err != nil {
-		log.Warnf("Could not extract user identity from the bearer token: %v. Continuing without user ID", err)
-		userID = ""
-		// fall through and continue without a user ID
-	} else {
-		log.WithFields(log.Fields{"userId": userID}).Info("Extracted user ID")
-	}
-
-	// update profile
-	ctx.cfg.Token = token.AccessToken
-	ctx.cfg.RefreshToken = token.RefreshToken
-	if userID != "" {
-		ctx.cfg.User = userID
-	}
-
-	return nil
+	return token, nil
 }
 
 func getAuthorizationCodes(ctx *callContext, url string) (*authCodes, error) {
@@ -201,10 +231,16 @@ func getAuthorizationCodes(ctx *callContext, url string) (*authCodes, error) {
 		// fall through
 	}
 
-	// wait for authorization codes (TODO: add timeout, e.g., a few minutes)
+	// wait for authorization codes (or until the call is cancelled or times out)
 	ctx.startSpinner("OAuth interactive authentication")
-	authCode := <-respChan // nb: blocks until a callback is received on localhost with the correct path
-	ctx.stopSpinner(true)  // TODO: figure out whether this can indicate fail/in what condition
+	var authCode authCodes
+	select {
+	case authCode = <-respChan: // nb: blocks until a callback is received on localhost with the correct path
+	case <-ctx.goContext.Done():
+		ctx.stopSpinner(false)
+		return nil, fmt.Errorf("interactive authentication aborted: %w", context.Cause(ctx.goContext))
+	}
+	ctx.stopSpinner(true) // TODO: figure out whether this can indicate fail/in what condition
 	log.Infof("PKCE authorization codes received")
 
 	return &authCode, nil
@@ -214,7 +250,10 @@ func exchangeCodeForToken(ctx *callContext, conf *oauth2.Config, pkce pkce.Code,
 	log.Infof("Exchanging authorization codes for access token")
 
 	// create http client for the request
-	client := &http.Client{}
+	client, err := newHTTPClient(ctx.cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	// prepare urlencoded data body
 	values := url.Values{}
@@ -226,7 +265,7 @@ func exchangeCodeForToken(ctx *callContext, conf *oauth2.Config, pkce pkce.Code,
 	bodyReader := bytes.NewReader([]byte(values.Encode()))
 
 	// create a POST HTTP request
-	req, err := http.NewRequest("POST", conf.Endpoint.TokenURL, bodyReader)
+	req, err := http.NewRequestWithContext(ctx.goContext, "POST", conf.Endpoint.TokenURL, bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create a request %q: %v", conf.Endpoint.TokenURL, err.Error())
 	}
@@ -285,7 +324,10 @@ func oauthRefreshToken(ctx *callContext) error {
 	log.Infof("Trying to get a new access token using the refresh token")
 
 	// create http client for the request
-	client := &http.Client{}
+	client, err := newHTTPClient(ctx.cfg)
+	if err != nil {
+		return err
+	}
 
 	// prepare urlencoded data body
 	values := url.Values{}
@@ -297,7 +339,7 @@ func oauthRefreshToken(ctx *callContext) error {
 
 	// create a POST HTTP request
 	tokenUri := oauthUriWithSuffix(ctx.cfg, oauth2TokenUriSuffix)
-	req, err := http.NewRequest("POST", tokenUri, bodyReader)
+	req, err := http.NewRequestWithContext(ctx.goContext, "POST", tokenUri, bodyReader)
 	if err != nil {
 		return fmt.Errorf("failed to create a token refresh request %q: %v", tokenUri, err)
 	}
@@ -355,7 +397,7 @@ func oauthUriWithSuffix(ctx *config.Context, suffix string) string {
 
 func startCallbackServer() (*http.Server, chan authCodes, error) {
 	// construct a channel for the response
-	respChan := make(chan authCodes)
+	respChan := make(chan authCodes, 1) // buffered, so the handler doesn't block if the login was aborted
 
 	// start server at oauthRedirectUri
 	urlStruct, err := url.Parse(oauthRedirectUri)
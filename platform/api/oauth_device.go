@@ -0,0 +1,181 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/apex/log"
+)
+
+const (
+	oauth2DeviceAuthUriSuffix = "oauth2/device/auth" // API for obtaining device and user codes (RFC 8628)
+	oauth2DeviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+	deviceFlowDefaultInterval = 5   // seconds between token polls, unless the server specifies otherwise
+	deviceFlowSlowDownDelta   = 5   // seconds to add to the interval on "slow_down" (RFC 8628, section 3.5)
+	deviceFlowDefaultExpiry   = 600 // seconds, if the server doesn't specify the device code's lifetime
+)
+
+// deviceFlowTimeUnit is the unit of the device flow intervals (a variable, so that tests can run faster)
+var deviceFlowTimeUnit = time.Second
+
+// deviceAuthorization is the response of the device authorization endpoint (RFC 8628, section 3.2)
+type deviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationUri         string `json:"verification_uri"`
+	VerificationUriComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// oauthDeviceLogin obtains tokens using the OAuth device authorization grant (RFC 8628): it displays
+// a URL and a code for the user to complete the login on any device with a browser, and polls the token
+// endpoint until the user completes (or denies) the login or the code expires.
+func oauthDeviceLogin(ctx *callContext) (*appTokens, error) {
+	client, err := newHTTPClient(ctx.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// request device and user codes
+	values := url.Values{}
+	values.Add("client_id", oauth2ClientId)
+	values.Add("scope", strings.Join(oauthScopes, " "))
+	status, respBytes, err := postOAuthForm(ctx, client, oauthUriWithSuffix(ctx.cfg, oauth2DeviceAuthUriSuffix), values)
+	if err != nil {
+		return nil, err
+	}
+	if status/100 != 2 {
+		return nil, fmt.Errorf("failed to start device login: %w", oauthResponseError(status, respBytes))
+	}
+	var auth deviceAuthorization
+	if err := json.Unmarshal(respBytes, &auth); err != nil {
+		return nil, fmt.Errorf("failed to JSON parse the device authorization response: %w", err)
+	}
+	if auth.DeviceCode == "" || auth.UserCode == "" || auth.VerificationUri == "" {
+		return nil, fmt.Errorf("incomplete device authorization response: %v", string(respBytes))
+	}
+
+	// ask the user to log in (on stderr, so that it is visible even if the output is captured)
+	fmt.Fprintf(os.Stderr, "To log in, visit %v and enter the code %v\n", auth.VerificationUri, auth.UserCode)
+	if auth.VerificationUriComplete != "" {
+		fmt.Fprintf(os.Stderr, "or open %v\n", auth.VerificationUriComplete)
+	}
+
+	// poll for the tokens
+	interval := auth.Interval
+	if interval <= 0 {
+		interval = deviceFlowDefaultInterval
+	}
+	expiresIn := auth.ExpiresIn
+	if expiresIn <= 0 {
+		expiresIn = deviceFlowDefaultExpiry
+	}
+	deadline := time.Now().Add(time.Duration(expiresIn) * deviceFlowTimeUnit)
+
+	values = url.Values{}
+	values.Add("grant_type", oauth2DeviceCodeGrantType)
+	values.Add("client_id", oauth2ClientId)
+	values.Add("device_code", auth.DeviceCode)
+	tokenUri := oauthUriWithSuffix(ctx.cfg, oauth2TokenUriSuffix)
+
+	ctx.startSpinner("Waiting for device login")
+	defer ctx.stopSpinnerHide()
+	for {
+		// wait before polling, as required by the server
+		timer := time.NewTimer(time.Duration(interval) * deviceFlowTimeUnit)
+		select {
+		case <-timer.C:
+		case <-ctx.goContext.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("device login aborted: %w", context.Cause(ctx.goContext))
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("device login timed out: the code %v has expired; please try again", auth.UserCode)
+		}
+
+		status, respBytes, err := postOAuthForm(ctx, client, tokenUri, values)
+		if err != nil {
+			return nil, err
+		}
+		if status/100 == 2 {
+			var token appTokens
+			if err := json.Unmarshal(respBytes, &token); err != nil {
+				return nil, fmt.Errorf("failed to JSON parse the response as a token object: %w", err)
+			}
+			log.Infof("Device login completed")
+			return &token, nil
+		}
+
+		var errobj oauthErrorPayload
+		_ = json.Unmarshal(respBytes, &errobj) // tolerate non-JSON responses, handled as failures
+		switch errobj.Error {
+		case "authorization_pending":
+			log.Info("Device login pending")
+		case "slow_down":
+			interval += deviceFlowSlowDownDelta
+			log.Infof("Device login polling too fast; slowing down to every %d seconds", interval)
+		case "access_denied":
+			return nil, fmt.Errorf("device login was denied")
+		case "expired_token":
+			return nil, fmt.Errorf("device login timed out: the code %v has expired; please try again", auth.UserCode)
+		default:
+			return nil, fmt.Errorf("device login failed: %w", oauthResponseError(status, respBytes))
+		}
+	}
+}
+
+// postOAuthForm posts the urlencoded values to an OAuth endpoint, returning the response status and body
+func postOAuthForm(ctx *callContext, client *http.Client, uri string, values url.Values) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx.goContext, "POST", uri, bytes.NewReader([]byte(values.Encode())))
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to create a request %q: %w", uri, err)
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("POST request to %q failed: %w", uri, err)
+	}
+	defer resp.Body.Close()
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed reading response to POST to %q: %w", uri, err)
+	}
+	return resp.StatusCode, respBytes, nil
+}
+
+// oauthResponseError creates an error from an OAuth endpoint's error response, tolerating non-JSON responses
+func oauthResponseError(status int, respBytes []byte) error {
+	var errobj oauthErrorPayload
+	if err := json.Unmarshal(respBytes, &errobj); err != nil || errobj.Error == "" {
+		return fmt.Errorf("error response (status %d): `%v`", status, string(respBytes))
+	}
+	if errobj.ErrorDesc != "" {
+		return fmt.Errorf("%v: %v", errobj.Error, errobj.ErrorDesc)
+	}
+	return fmt.Errorf("%v", errobj.Error)
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cisco-open/fsoc/config"
+)
+
+// newDeviceFlowServer creates an authorization server that responds to token polls with the given
+// errors, in order, and then with tokens. It records the times of the token polls.
+func newDeviceFlowServer(t *testing.T, pollErrors []string, pollTimes *[]time.Time) *httptest.Server {
+	var mu sync.Mutex
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/auth/tenant1/default/" + oauth2DeviceAuthUriSuffix:
+			assert.Equal(t, oauth2ClientId, r.PostForm.Get("client_id"))
+			assert.Equal(t, "openid introspect_tokens offline_access", r.PostForm.Get("scope"))
+			_ = json.NewEncoder(w).Encode(deviceAuthorization{
+				DeviceCode:      "device-code",
+				UserCode:        "ABCD-EFGH",
+				VerificationUri: "https://example.com/device",
+				ExpiresIn:       600,
+				Interval:        1,
+			})
+		case "/auth/tenant1/default/" + oauth2TokenUriSuffix:
+			assert.Equal(t, oauth2DeviceCodeGrantType, r.PostForm.Get("grant_type"))
+			assert.Equal(t, "device-code", r.PostForm.Get("device_code"))
+			mu.Lock()
+			defer mu.Unlock()
+			poll := len(*pollTimes)
+			*pollTimes = append(*pollTimes, time.Now())
+			if poll < len(pollErrors) {
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(oauthErrorPayload{Error: pollErrors[poll]})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(appTokens{AccessToken: "access-token", RefreshToken: "refresh-token", TokenType: "bearer"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func newDeviceFlowCallContext(url string) *callContext {
+	return &callContext{
+		goContext: context.Background(),
+		cfg:       &config.Context{Name: "test", AuthMethod: config.AuthMethodOAuth, URL: url, Tenant: "tenant1", OAuthFlow: config.OAuthFlowDevice},
+	}
+}
+
+func TestOAuthDeviceLogin(t *testing.T) {
+	defer func(unit time.Duration) { deviceFlowTimeUnit = unit }(deviceFlowTimeUnit)
+	deviceFlowTimeUnit = 10 * time.Millisecond
+
+	var pollTimes []time.Time
+	server := newDeviceFlowServer(t, []string{"authorization_pending", "slow_down"}, &pollTimes)
+	defer server.Close()
+
+	token, err := oauthDeviceLogin(newDeviceFlowCallContext(server.URL))
+	require.NoError(t, err)
+	assert.Equal(t, "access-token", token.AccessToken)
+	assert.Equal(t, "refresh-token", token.RefreshToken)
+
+	// the interval increases by 5 units after slow_down
+	require.Len(t, pollTimes, 3)
+	assert.GreaterOrEqual(t, pollTimes[2].Sub(pollTimes[1]), (1+deviceFlowSlowDownDelta)*deviceFlowTimeUnit)
+}
+
+func TestOAuthDeviceLoginDenied(t *testing.T) {
+	defer func(unit time.Duration) { deviceFlowTimeUnit = unit }(deviceFlowTimeUnit)
+	deviceFlowTimeUnit = time.Millisecond
+
+	var pollTimes []time.Time
+	server := newDeviceFlowServer(t, []string{"authorization_pending", "access_denied"}, &pollTimes)
+	defer server.Close()
+
+	_, err := oauthDeviceLogin(newDeviceFlowCallContext(server.URL))
+	assert.ErrorContains(t, err, "denied")
+	assert.Len(t, pollTimes, 2)
+
+	pollTimes = nil
+	server2 := newDeviceFlowServer(t, []string{"expired_token"}, &pollTimes)
+	defer server2.Close()
+	_, err = oauthDeviceLogin(newDeviceFlowCallContext(server2.URL))
+	assert.ErrorContains(t, err, "expired")
+}
+
+func TestOAuthDeviceLoginCancelled(t *testing.T) {
+	var pollTimes []time.Time
+	server := newDeviceFlowServer(t, nil, &pollTimes)
+	defer server.Close()
+
+	callCtx := newDeviceFlowCallContext(server.URL)
+	goCtx, cancel := context.WithCancel(context.Background())
+	callCtx.goContext = goCtx
+	cancel()
+
+	_, err := oauthDeviceLogin(callCtx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, pollTimes)
+}
@@ -15,8 +15,10 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
@@ -39,12 +41,15 @@ func RunProxyServer(port int, command []string, statusPrinter func(string), exit
 	}
 
 	// Create a new call context
-	callCtx := newCallContext(context.Background(), false)
+	callCtx, err := newCallContext(baseContext, false, "")
+	if err != nil {
+		return err
+	}
 	cfg := callCtx.cfg // quick access to config
 
-	// force login if no token
-	if cfg.Token == "" {
-		log.Info("No auth token available, trying to log in")
+	// force login if no token or the token is about to expire
+	if needsLogin(cfg) {
+		log.Info("No valid auth token available, trying to log in")
 		if err := login(callCtx); err != nil {
 			return err
 		}
@@ -57,10 +62,14 @@ func RunProxyServer(port int, command []string, statusPrinter func(string), exit
 	// Set up the reverse proxy handler
 	url, err := url.Parse(cfg.URL)
 	if err != nil {
-		log.Fatalf("Invalid URL %q in profile %q: %v", cfg.URL, cfg.Name, err)
+		return fmt.Errorf("invalid URL %q in profile %q: %v", cfg.URL, cfg.Name, err)
+	}
+	transport, err := newApiRetriableTransport(callCtx, statusPrinter)
+	if err != nil {
+		return err
 	}
 	proxy := &httputil.ReverseProxy{
-		Transport: newApiRetriableTransport(callCtx, statusPrinter),
+		Transport: transport,
 		Rewrite: func(r *httputil.ProxyRequest) {
 			r.SetURL(url)
 		},
@@ -158,39 +167,105 @@ type apiRetriableTransport struct {
 	callContext   *callContext // contains the auth token
 }
 
-func newApiRetriableTransport(callContext *callContext, statusPrinter func(string)) *apiRetriableTransport {
+func newApiRetriableTransport(callContext *callContext, statusPrinter func(string)) (*apiRetriableTransport, error) {
+	transport, err := httpTransport(callContext.cfg)
+	if err != nil {
+		return nil, err
+	}
+	transport = transport.Clone() // don't affect the shared transport
+	transport.ResponseHeaderTimeout = 30 * time.Second
+	// the rest of the timeout fields have satisfactory default values
+
 	return &apiRetriableTransport{
-		transport: &http.Transport{
-			ResponseHeaderTimeout: 30 * time.Second,
-			// the rest of the timeout fields have satisfactory default values
-		},
+		transport:     transport,
 		callContext:   callContext,
 		statusPrinter: statusPrinter,
-	}
+	}, nil
 }
 
 func (t *apiRetriableTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	// add auth header
-	req.Header.Add("Authorization", "Bearer "+t.callContext.cfg.Token)
-
-	// make the request and return, unless it's a 403 (likely expired token)
-	t.statusPrinter(fmt.Sprintf("Proxying request %q", req.URL))
-	resp, err := t.transport.RoundTrip(req)
-	if err != nil || resp.StatusCode != http.StatusForbidden {
-		return resp, err // return the response as is
+	// buffer the request body so that the request can be replayed on retry
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the body of request %q: %w", req.URL, err)
+		}
+	}
+
+	// refresh the token ahead of its expiration (the proxy may run longer than the token's lifetime)
+	if t.callContext.cfg.Token != "" && needsLogin(t.callContext.cfg) {
+		log.Info("Auth token expires soon, refreshing it")
+		if err := login(t.callContext); err != nil {
+			log.Errorf("Login failed: %v", err)
+			// fall through, the request will fail with the current token
+		}
 	}
 
-	// refresh the API token
-	log.Warn("Current token is no longer valid; trying to refresh")
-	if err := login(t.callContext); err != nil {
-		log.Errorf("Login failed: %w", err)
-		return resp, nil // return the original 403 response
+	retryPolicy := effectiveRetryPolicy(nil)
+	loggedIn := false
+	for attempt := 1; ; attempt++ {
+		// add auth header
+		req.Header.Set("Authorization", "Bearer "+t.callContext.cfg.Token)
+		if body != nil {
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		// make the request
+		if attempt == 1 && !loggedIn {
+			t.statusPrinter(fmt.Sprintf("Proxying request %q", req.URL))
+		}
+		resp, err := t.transport.RoundTrip(req)
+		if err != nil {
+			if !retryPolicy.shouldRetryError(req.Method, attempt, err) {
+				return nil, err
+			}
+			delay, _ := retryPolicy.backoff(attempt, "")
+			if err := t.waitBeforeRetry(req, &retryPolicy, attempt, delay, err.Error()); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		// refresh the API token on 403 (likely expired token) and retry the request once,
+		// unless the token is known to be valid (i.e., the principal is not authorized)
+		if resp.StatusCode == http.StatusForbidden && !loggedIn && !hasValidToken(t.callContext.cfg) {
+			log.Warn("Current token is no longer valid; trying to refresh")
+			if err := login(t.callContext); err != nil {
+				log.Errorf("Login failed: %v", err)
+				return resp, nil // return the original 403 response
+			}
+			// note: callContext.cfg.Token has been updated by login()
+			resp.Body.Close()
+			loggedIn = true
+			attempt--
+			t.statusPrinter(fmt.Sprintf("Retrying request %q with refreshed token", req.URL))
+			continue
+		}
+
+		if resp.StatusCode == http.StatusForbidden && hasValidToken(t.callContext.cfg) {
+			log.Warnf("Access denied for request %q: %v", req.URL, authorizationError(t.callContext.cfg, fmt.Errorf("status %v", resp.Status)))
+		}
+
+		// retry transient failures, otherwise return the response as is
+		if !retryPolicy.shouldRetryStatus(req.Method, attempt, resp.StatusCode) {
+			return resp, nil
+		}
+		delay, ok := retryPolicy.backoff(attempt, resp.Header.Get("Retry-After"))
+		if !ok {
+			t.statusPrinter(fmt.Sprintf("Request %q failed (%v), not retrying since the platform asked to wait for more than %v", req.URL, resp.Status, MaxRetryAfter))
+			return resp, nil
+		}
+		resp.Body.Close()
+		if err := t.waitBeforeRetry(req, &retryPolicy, attempt, delay, resp.Status); err != nil {
+			return nil, err
+		}
 	}
-	// note: callContext.cfg.Token has been updated by login()
+}
 
-	// retry the request with the new token
-	t.statusPrinter(fmt.Sprintf("Retrying request %q with refreshed token", req.URL))
-	req.Header.Del("Authorization")
-	req.Header.Add("Authorization", "Bearer "+t.callContext.cfg.Token)
-	return t.transport.RoundTrip(req)
+func (t *apiRetriableTransport) waitBeforeRetry(req *http.Request, policy *RetryPolicy, attempt int, delay time.Duration, reason string) error {
+	t.statusPrinter(fmt.Sprintf("Request %q failed (%v), retrying in %v (attempt %v of %v)", req.URL, reason, delay.Round(time.Millisecond), attempt+1, policy.MaxAttempts))
+	return sleepWithContext(req.Context(), delay)
 }
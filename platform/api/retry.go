@@ -0,0 +1,242 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/cisco-open/fsoc/config"
+)
+
+// Default retry policy values, used when neither the call options nor the profile specify them
+const (
+	DefaultRetryMaxAttempts    = 3
+	DefaultRetryInitialBackoff = 500 * time.Millisecond
+	DefaultRetryMaxBackoff     = 10 * time.Second
+)
+
+// MaxRetryAfter is the longest Retry-After delay that is honored; if the server asks to wait
+// longer than this, the call is not retried and fails with the server's response instead
+const MaxRetryAfter = 2 * time.Minute
+
+// SubsystemName is the name under which the API client's settings are stored in the
+// config profile (e.g., "fsoc config set api.retry-max-attempts=5")
+const SubsystemName = "api"
+
+// RetryPolicy defines how API calls are retried on transient failures (throttling,
+// gateway errors and network errors). Zero values are replaced with the profile's
+// settings or, if not set there either, with the built-in defaults.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one (1 disables retries)
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry; each subsequent retry doubles it
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between attempts (a Retry-After header value is honored even if longer,
+	// up to MaxRetryAfter)
+	MaxBackoff time.Duration
+
+	// RetryNonIdempotent allows retrying POST and PATCH requests on failures where the server
+	// may have already processed the request (gateway errors, connection resets). Throttled
+	// requests (429) are always retried since the server explicitly did not process them.
+	RetryNonIdempotent bool
+}
+
+// apiConfig is the profile-level (subsystem) configuration of the platform API client
+type apiConfig struct {
+	RetryMaxAttempts    int           `mapstructure:"retry-max-attempts,omitempty" fsoc-help:"Maximum number of attempts for platform API calls that fail with a transient error (429, 502, 503, 504 or a network error), including the first attempt. Use 1 to disable retries. The default is 3."`
+	RetryInitialBackoff time.Duration `mapstructure:"retry-initial-backoff,omitempty" fsoc-help:"Delay before the first retry, doubled (with jitter) for each subsequent retry, e.g., \"500ms\" or \"2s\". The default is \"500ms\"."`
+	RetryMaxBackoff     time.Duration `mapstructure:"retry-max-backoff,omitempty" fsoc-help:"Maximum delay between retries, e.g., \"30s\". A Retry-After header returned by the platform takes precedence, up to 2 minutes. The default is \"10s\"."`
+	RetryNonIdempotent  bool          `mapstructure:"retry-non-idempotent,omitempty" fsoc-help:"Set to \"true\" to also retry POST and PATCH requests on gateway and network errors, where the request may have been processed already. The default is \"false\"."`
+}
+
+// GlobalConfig contains the API client settings from the current profile
+var GlobalConfig apiConfig
+
+// retriableStatusCodes lists the HTTP status codes that indicate a transient failure
+var retriableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+func init() {
+	config.RegisterTypeDecodeHooks(mapstructure.StringToTimeDurationHookFunc(), scalarDecodeHookFunc())
+	if err := config.RegisterSubsystemConfigStorage(SubsystemName, &GlobalConfig); err != nil {
+		panic(err) // can only happen if registered twice, a bug
+	}
+}
+
+// scalarDecodeHookFunc converts string setting values (as provided by "fsoc config set")
+// into int and bool settings
+func scalarDecodeHookFunc() mapstructure.DecodeHookFunc {
+	return func(
+		f reflect.Type,
+		t reflect.Type,
+		data interface{}) (interface{}, error) {
+		if f.Kind() != reflect.String {
+			return data, nil
+		}
+		switch t {
+		case reflect.TypeOf(int(0)):
+			return strconv.Atoi(data.(string))
+		case reflect.TypeOf(false):
+			return strconv.ParseBool(data.(string))
+		}
+		return data, nil
+	}
+}
+
+// effectiveRetryPolicy combines the retry policy requested for the call (if any) with
+// the profile settings and the built-in defaults
+func effectiveRetryPolicy(p *RetryPolicy) RetryPolicy {
+	var policy RetryPolicy
+	if p != nil {
+		policy = *p
+	}
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = GlobalConfig.RetryMaxAttempts
+	}
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = DefaultRetryMaxAttempts
+	}
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = GlobalConfig.RetryInitialBackoff
+	}
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = DefaultRetryInitialBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = GlobalConfig.RetryMaxBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = DefaultRetryMaxBackoff
+	}
+	policy.RetryNonIdempotent = policy.RetryNonIdempotent || GlobalConfig.RetryNonIdempotent
+	return policy
+}
+
+// isIdempotent returns true for HTTP methods that can be safely repeated
+func isIdempotent(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		return true
+	}
+	return false
+}
+
+// shouldRetryStatus determines whether a request that returned the given status code should be
+// retried. Attempts are counted from 1.
+func (p *RetryPolicy) shouldRetryStatus(method string, attempt int, statusCode int) bool {
+	if attempt >= p.MaxAttempts || !slices.Contains(retriableStatusCodes, statusCode) {
+		return false
+	}
+	return statusCode == http.StatusTooManyRequests || isIdempotent(method) || p.RetryNonIdempotent
+}
+
+// shouldRetryError determines whether a request that failed without a response should be retried.
+// Attempts are counted from 1.
+func (p *RetryPolicy) shouldRetryError(method string, attempt int, err error) bool {
+	if attempt >= p.MaxAttempts || !isTransientNetworkError(err) {
+		return false
+	}
+	return isIdempotent(method) || p.RetryNonIdempotent
+}
+
+// backoff returns the delay before the next attempt, given the number of the attempt that failed
+// (counted from 1) and the Retry-After response header value (may be empty). Exponential backoff
+// uses "equal jitter": half of the computed delay is fixed and the other half is random.
+// It returns false if the Retry-After delay exceeds MaxRetryAfter, in which case the call
+// should not be retried.
+func (p *RetryPolicy) backoff(attempt int, retryAfter string) (time.Duration, bool) {
+	if d, ok := parseRetryAfter(retryAfter); ok {
+		return d, d <= MaxRetryAfter
+	}
+
+	d := p.InitialBackoff
+	for i := 1; i < attempt && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1)), true
+}
+
+// parseRetryAfter parses a Retry-After header value, which may be either a number of
+// seconds or an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// isTransientNetworkError returns true for network errors that are likely to go away on retry
+func isTransientNetworkError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return false
+}
+
+// sleepWithContext waits for the specified duration or until the context is done,
+// whichever comes first. Returns the context's cancellation cause if it was done first.
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEffectiveRetryPolicyDefaults(t *testing.T) {
+	policy := effectiveRetryPolicy(nil)
+	assert.Equal(t, DefaultRetryMaxAttempts, policy.MaxAttempts)
+	assert.Equal(t, DefaultRetryInitialBackoff, policy.InitialBackoff)
+	assert.Equal(t, DefaultRetryMaxBackoff, policy.MaxBackoff)
+	assert.False(t, policy.RetryNonIdempotent)
+
+	policy = effectiveRetryPolicy(&RetryPolicy{MaxAttempts: 1})
+	assert.Equal(t, 1, policy.MaxAttempts)
+	assert.Equal(t, DefaultRetryInitialBackoff, policy.InitialBackoff)
+}
+
+func TestShouldRetryStatus(t *testing.T) {
+	policy := effectiveRetryPolicy(&RetryPolicy{MaxAttempts: 3})
+
+	assert.True(t, policy.shouldRetryStatus("GET", 1, http.StatusServiceUnavailable))
+	assert.True(t, policy.shouldRetryStatus("GET", 2, http.StatusTooManyRequests))
+	assert.False(t, policy.shouldRetryStatus("GET", 3, http.StatusTooManyRequests)) // attempts exhausted
+	assert.False(t, policy.shouldRetryStatus("GET", 1, http.StatusInternalServerError))
+	assert.False(t, policy.shouldRetryStatus("GET", 1, http.StatusNotFound))
+
+	// non-idempotent methods are retried only when throttled, unless enabled
+	assert.True(t, policy.shouldRetryStatus("POST", 1, http.StatusTooManyRequests))
+	assert.False(t, policy.shouldRetryStatus("POST", 1, http.StatusBadGateway))
+	policy.RetryNonIdempotent = true
+	assert.True(t, policy.shouldRetryStatus("POST", 1, http.StatusBadGateway))
+}
+
+func TestShouldRetryError(t *testing.T) {
+	policy := effectiveRetryPolicy(&RetryPolicy{MaxAttempts: 2})
+
+	assert.True(t, policy.shouldRetryError("GET", 1, syscall.ECONNRESET))
+	assert.False(t, policy.shouldRetryError("GET", 2, syscall.ECONNRESET))
+	assert.False(t, policy.shouldRetryError("PATCH", 1, syscall.ECONNRESET))
+}
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	for attempt, limit := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		limit *= time.Millisecond
+		d, ok := policy.backoff(attempt+1, "")
+		assert.True(t, ok)
+		assert.GreaterOrEqual(t, d, limit/2, "attempt %d", attempt+1)
+		assert.LessOrEqual(t, d, limit, "attempt %d", attempt+1)
+	}
+
+	// Retry-After takes precedence, even if longer than the max backoff
+	d, ok := policy.backoff(1, "7")
+	assert.True(t, ok)
+	assert.Equal(t, 7*time.Second, d)
+
+	// ...but not if it's longer than the max Retry-After
+	_, ok = policy.backoff(1, "86400")
+	assert.False(t, ok)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	d, ok := parseRetryAfter("120")
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Minute, d)
+
+	d, ok = parseRetryAfter(time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), d)
+
+	_, ok = parseRetryAfter("")
+	assert.False(t, ok)
+	_, ok = parseRetryAfter("soon")
+	assert.False(t, ok)
+}
+
+func TestSleepWithContext(t *testing.T) {
+	assert.NoError(t, sleepWithContext(context.Background(), time.Millisecond))
+
+	cause := errors.New("timed out")
+	ctx, cancel := context.WithTimeoutCause(context.Background(), time.Millisecond, cause)
+	defer cancel()
+	assert.ErrorIs(t, sleepWithContext(ctx, time.Minute), cause)
+}
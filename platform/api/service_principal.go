@@ -72,14 +72,9 @@ type helmCredentialsStruct struct {
 
 // servicePrincipalLogin performs a login into the platform API and updates the token(s) in the provided context
 func servicePrincipalLogin(ctx *callContext) error {
-	// read credentials file
-	file := ctx.cfg.SecretFile
-	if file == "" {
-		file = ctx.cfg.CsvFile // implicitly update config schema (backward compatibility)
-	}
-	credentials, err := readServiceCredentials(file)
+	credentials, err := principalCredentials(ctx, false)
 	if err != nil {
-		return fmt.Errorf("failed to read credentials file %q: %v", file, err)
+		return err
 	}
 
 	return agentOrServicePrincipalLogin(ctx, "service principal", credentials)
@@ -87,11 +82,9 @@ func servicePrincipalLogin(ctx *callContext) error {
 
 // agentPrincipalLogin performs a login into the platform API and updates the token(s) in the provided context
 func agentPrincipalLogin(ctx *callContext) error {
-	// read credentials file
-	file := ctx.cfg.SecretFile
-	credentials, err := readAgentCredentials(file)
+	credentials, err := principalCredentials(ctx, true)
 	if err != nil {
-		return fmt.Errorf("failed to read credentials file %q: %v", file, err)
+		return err
 	}
 
 	return agentOrServicePrincipalLogin(ctx, "agent principal", credentials)
@@ -126,12 +119,15 @@ func agentOrServicePrincipalLogin(ctx *callContext, principalType string, creden
 	// create a HTTP request
 	url, err := url.Parse(ctx.cfg.URL)
 	if err != nil {
-		log.Fatalf("Failed to parse the url provided in context. URL: %s, err: %s", ctx.cfg.URL, err)
+		return fmt.Errorf("failed to parse the url provided in context (%q): %v", ctx.cfg.URL, err)
 	}
 	url.Path = "auth/" + ctx.cfg.Tenant + "/default/oauth2/token"
 
-	client := &http.Client{}
-	req, err := http.NewRequest("POST", url.String(), strings.NewReader("grant_type=client_credentials")) //TODO: urlencode data!
+	client, err := newHTTPClient(ctx.cfg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx.goContext, "POST", url.String(), strings.NewReader("grant_type=client_credentials")) //TODO: urlencode data!
 	if err != nil {
 		return fmt.Errorf("failed to create a request for %q: %v", url.String(), err)
 	}
@@ -223,11 +219,19 @@ func readJsonCredentials(file string) (*credentialsStruct, error) {
 		return nil, fmt.Errorf("failed to read the credentials file %q: %w", file, err)
 	}
 
-	var credentials credentialsStruct
-	if err = json.Unmarshal(data, &credentials); err != nil {
+	credentials, err := parseJsonCredentials(data)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse credentials file %q: %w", file, err)
 	}
 
+	return credentials, nil
+}
+
+func parseJsonCredentials(data []byte) (*credentialsStruct, error) {
+	var credentials credentialsStruct
+	if err := json.Unmarshal(data, &credentials); err != nil {
+		return nil, err
+	}
 	return &credentials, nil
 }
 
@@ -323,11 +327,20 @@ func readAgentJsonCredentials(file string) (*credentialsStruct, error) {
 		return nil, fmt.Errorf("failed to read the credentials file %q: %w", file, err)
 	}
 
-	var agentCredentials agentCredentialsStruct
-	if err = json.Unmarshal(data, &agentCredentials); err != nil {
+	credentials, err := parseAgentJsonCredentials(data)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse credentials file %q: %w", file, err)
 	}
 
+	return credentials, nil
+}
+
+func parseAgentJsonCredentials(data []byte) (*credentialsStruct, error) {
+	var agentCredentials agentCredentialsStruct
+	if err := json.Unmarshal(data, &agentCredentials); err != nil {
+		return nil, err
+	}
+
 	return &credentialsStruct{
 		ClientID: agentCredentials.ClientID,
 		Secret:   agentCredentials.Secret,
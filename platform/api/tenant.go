@@ -40,8 +40,11 @@ func resolveTenant(ctx *callContext) (string, error) {
 	log.Infof("Looking up tenant ID for %v", ctx.cfg.URL)
 
 	// create a GET HTTP request
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", resolverUri, nil)
+	client, err := newHTTPClient(ctx.cfg)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx.goContext, "GET", resolverUri, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create a request %q: %v", resolverUri, err.Error())
 	}
@@ -0,0 +1,77 @@
+// Copyright 2024 Cisco Systems, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cisco-open/fsoc/config"
+)
+
+// tokenRefreshAhead is how long before its expiration the access token is refreshed, so that
+// requests are not sent with a token that expires in flight
+var tokenRefreshAhead = time.Minute
+
+// tokenExpiry returns the expiration time of the profile's access token, as recorded in the profile
+// on login or, for tokens obtained otherwise, from the token's claims. It returns the zero time if
+// there is no token or its expiration is not known (e.g., the token is not a JWT).
+func tokenExpiry(cfg *config.Context) time.Time {
+	if cfg.Token == "" {
+		return time.Time{}
+	}
+	if cfg.TokenExpiry != 0 {
+		return time.Unix(cfg.TokenExpiry, 0)
+	}
+	claims, err := parseTokenClaims(cfg.Token)
+	if err != nil || claims.Expiry == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Expiry, 0)
+}
+
+// updateTokenExpiry records the expiration time of the profile's access token in the profile
+func updateTokenExpiry(cfg *config.Context) {
+	cfg.TokenExpiry = 0 // the new token's expiration is determined from its claims
+	if expiry := tokenExpiry(cfg); !expiry.IsZero() {
+		cfg.TokenExpiry = expiry.Unix()
+	}
+}
+
+// needsLogin returns true if the profile has no access token or the token expires soon (or has expired)
+func needsLogin(cfg *config.Context) bool {
+	if cfg.Token == "" {
+		return true
+	}
+	expiry := tokenExpiry(cfg)
+	return !expiry.IsZero() && time.Now().Add(tokenRefreshAhead).After(expiry)
+}
+
+// hasValidToken returns true if the profile's access token is known not to have expired
+func hasValidToken(cfg *config.Context) bool {
+	expiry := tokenExpiry(cfg)
+	return !expiry.IsZero() && time.Now().Before(expiry)
+}
+
+// authorizationError explains a 403 response received with a valid token: the principal
+// is authenticated but not authorized for the request, so logging in again would not help
+func authorizationError(cfg *config.Context, err error) error {
+	principal := "the profile's principal"
+	if cfg.User != "" {
+		principal = fmt.Sprintf("%q", cfg.User)
+	}
+	return fmt.Errorf("access denied: %v is not authorized for this operation in profile %q (the access token is valid until %v); check the permissions (roles) granted to it or use a different profile: %w",
+		principal, cfg.Name, tokenExpiry(cfg).Local().Format(time.RFC3339), err)
+}
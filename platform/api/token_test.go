@@ -0,0 +1,128 @@
+package api
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cisco-open/fsoc/config"
+	"github.com/cisco-open/fsoc/test"
+)
+
+// makeToken creates an unsigned JWT with the given claims
+func makeToken(claims string) string {
+	encode := base64.RawURLEncoding.EncodeToString
+	return encode([]byte(`{"alg":"none"}`)) + "." + encode([]byte(claims)) + ".sig"
+}
+
+func TestParseTokenClaims(t *testing.T) {
+	// the subject produces '-' and '_' in base64url, which standard base64 rejects
+	token := makeToken(`{"sub":"user~~~>>>???","exp":1700000000}`)
+	claims, err := parseTokenClaims(token)
+	require.NoError(t, err)
+	assert.Equal(t, "user~~~>>>???", claims.Subject)
+	assert.Equal(t, int64(1700000000), claims.Expiry)
+
+	_, err = parseTokenClaims("opaque-token")
+	assert.Error(t, err)
+}
+
+func TestTokenExpiry(t *testing.T) {
+	future := time.Now().Add(time.Hour).Unix()
+	soon := time.Now().Add(tokenRefreshAhead / 2).Unix()
+	past := time.Now().Add(-time.Hour).Unix()
+
+	tests := []struct {
+		name       string
+		cfg        config.Context
+		needsLogin bool
+		valid      bool
+	}{
+		{"no token", config.Context{}, true, false},
+		{"opaque token", config.Context{Token: "opaque"}, false, false},
+		{"valid token", config.Context{Token: makeToken(fmt.Sprintf(`{"exp":%d}`, future))}, false, true},
+		{"expiring token", config.Context{Token: makeToken(fmt.Sprintf(`{"exp":%d}`, soon))}, true, true},
+		{"expired token", config.Context{Token: makeToken(fmt.Sprintf(`{"exp":%d}`, past))}, true, false},
+		{"recorded expiry", config.Context{Token: "opaque", TokenExpiry: past}, true, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.needsLogin, needsLogin(&tt.cfg), "needsLogin for %v", tt.name)
+		assert.Equal(t, tt.valid, hasValidToken(&tt.cfg), "hasValidToken for %v", tt.name)
+	}
+
+	cfg := config.Context{Token: makeToken(fmt.Sprintf(`{"exp":%d}`, future)), TokenExpiry: past}
+	updateTokenExpiry(&cfg)
+	assert.Equal(t, future, cfg.TokenExpiry)
+}
+
+// setTestProfileToken switches the test profile to JWT authentication with the given token
+func setTestProfileToken(t *testing.T, token string) {
+	ctx, err := config.GetContext(test.TEST_CONTEXT_NAME)
+	require.NoError(t, err)
+	ctx.AuthMethod = config.AuthMethodJWT
+	ctx.Token = token
+	ctx.TokenExpiry = 0
+	require.NoError(t, config.UpsertContext(ctx))
+}
+
+func TestForbiddenWithValidToken(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	defer test.SetActiveConfigProfileServer(server.URL)()
+
+	// a valid token is not refreshed on 403; the error explains the authorization failure
+	setTestProfileToken(t, makeToken(fmt.Sprintf(`{"sub":"svc","exp":%d}`, time.Now().Add(time.Hour).Unix())))
+	err := JSONGet("/objects", nil, &Options{Retry: &RetryPolicy{MaxAttempts: 1}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not authorized")
+	var statusErr *HttpStatusError
+	require.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, http.StatusForbidden, statusErr.StatusCode)
+	assert.Equal(t, int32(1), requests.Load())
+
+	// an expired token is refreshed (a no-op for JWT profiles) and the request retried once
+	requests.Store(0)
+	setTestProfileToken(t, makeToken(fmt.Sprintf(`{"sub":"svc","exp":%d}`, time.Now().Add(-time.Hour).Unix())))
+	err = JSONGet("/objects", nil, &Options{Retry: &RetryPolicy{MaxAttempts: 1}})
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "not authorized")
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestExpiredJWTNotSavedOnEachCall(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	defer test.SetActiveConfigProfileServer(server.URL)()
+
+	var out map[string]any
+
+	// the first call records the token's expiry; subsequent calls have nothing to save
+	setTestProfileToken(t, makeToken(fmt.Sprintf(`{"sub":"svc","exp":%d}`, time.Now().Add(-time.Hour).Unix())))
+	require.NoError(t, JSONGet("/objects", &out, nil))
+	info, err := os.Stat(viper.ConfigFileUsed())
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, JSONGet("/objects", &out, nil))
+	}
+	after, err := os.Stat(viper.ConfigFileUsed())
+	require.NoError(t, err)
+	assert.True(t, os.SameFile(info, after), "the config file must not be rewritten")
+	assert.Equal(t, info.ModTime(), after.ModTime())
+}
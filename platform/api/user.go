@@ -21,26 +21,40 @@ import (
 	"strings"
 )
 
-type user struct {
-	ID string `json:"sub"`
+// tokenClaims are the JWT claims of an access token that fsoc uses
+type tokenClaims struct {
+	Subject string `json:"sub"` // user or principal ID
+	Expiry  int64  `json:"exp"` // expiration time, Unix seconds (0 if not present)
 }
 
-func extractUser(accessToken string) (string, error) {
-	var userData user
+// parseTokenClaims decodes the claims of a JWT access token (without verifying its signature)
+func parseTokenClaims(accessToken string) (*tokenClaims, error) {
+	var claims tokenClaims
 	metaDataStringArray := strings.Split(accessToken, ".")
 	if len(metaDataStringArray) < 3 {
-		return "", fmt.Errorf("invalid bearer token detected")
+		return nil, fmt.Errorf("invalid bearer token detected")
 	}
 
-	// try to decode metadata token
-	metaDataString := metaDataStringArray[1]
-	decodedMetaDataBytes, err := base64.RawStdEncoding.DecodeString(metaDataString)
+	// try to decode metadata token (base64url per the JWT spec, tolerating standard base64 and padding)
+	metaDataString := strings.TrimRight(metaDataStringArray[1], "=")
+	decodedMetaDataBytes, err := base64.RawURLEncoding.DecodeString(metaDataString)
+	if err != nil {
+		decodedMetaDataBytes, err = base64.RawStdEncoding.DecodeString(metaDataString)
+	}
 	if err != nil {
-		return "", fmt.Errorf("failed to decode base64 string: %v", err.Error())
+		return nil, fmt.Errorf("failed to decode base64 string: %v", err.Error())
 	}
-	if err := json.Unmarshal(decodedMetaDataBytes, &userData); err != nil {
-		return "", fmt.Errorf("failed to JSON parse the `sub` from the decoded bearer token with error %v", err.Error())
+	if err := json.Unmarshal(decodedMetaDataBytes, &claims); err != nil {
+		return nil, fmt.Errorf("failed to JSON parse the claims from the decoded bearer token with error %v", err.Error())
 	}
 
-	return userData.ID, nil
+	return &claims, nil
+}
+
+func extractUser(accessToken string) (string, error) {
+	claims, err := parseTokenClaims(accessToken)
+	if err != nil {
+		return "", err
+	}
+	return claims.Subject, nil
 }